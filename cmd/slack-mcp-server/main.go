@@ -17,9 +17,16 @@ var defaultSseHost = "127.0.0.1"
 var defaultSsePort = 13080
 
 func main() {
+	// Over the stdio transport stdout carries the JSON-RPC stream, a single
+	// stray line there corrupts it. The standard logger writes to stderr
+	// already; pin it so that no dependency redirecting it can change that.
+	log.SetOutput(os.Stderr)
+
 	var transport string
+	var validate bool
 	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio or sse)")
 	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio or sse)")
+	flag.BoolVar(&validate, "validate", false, "Check the credentials and exit without starting the server")
 	flag.Parse()
 
 	err := validateToolConfig(os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL"))
@@ -27,7 +34,18 @@ func main() {
 		log.Fatalf("error in SLACK_MCP_ADD_MESSAGE_TOOL: %v", err)
 	}
 
-	p := provider.New()
+	p, err := provider.New()
+	if err != nil {
+		log.Fatalf("Error creating provider: %v", err)
+	}
+
+	if validate {
+		if err := p.Validate(context.Background()); err != nil {
+			log.Fatalf("Validation failed: %v", err)
+		}
+		log.Println("Validation passed.")
+		return
+	}
 
 	s := server.NewMCPServer(p,
 		transport,
@@ -36,6 +54,7 @@ func main() {
 	go func() {
 		newUsersWatcher(p)()
 		newChannelsWatcher(p)()
+		newEmojiWatcher(p)()
 	}()
 
 	switch transport {
@@ -74,9 +93,15 @@ func newUsersWatcher(p *provider.ApiProvider) func() {
 			return
 		}
 
-		err := p.RefreshUsers(context.Background())
-		if err != nil {
-			log.Fatalf("Error booting provider: %v", err)
+		// Serve degraded rather than exit, the tools relying on users
+		// report ErrSubsystemUnavailable until a refresh succeeds
+		if err := p.RefreshUsers(context.Background()); err != nil {
+			if h := p.Health().Users; h.Size > 0 {
+				log.Printf("Failed to cache users, serving %d salvaged from the cache: %v", h.Size, err)
+			} else {
+				log.Printf("Failed to cache users, serving without them: %v", err)
+			}
+			return
 		}
 
 		log.Println("Users cached successfully.")
@@ -92,15 +117,36 @@ func newChannelsWatcher(p *provider.ApiProvider) func() {
 			return
 		}
 
-		err := p.RefreshChannels(context.Background())
-		if err != nil {
-			log.Fatalf("Error booting provider: %v", err)
+		// Serve degraded rather than exit, the tools relying on channels
+		// report ErrSubsystemUnavailable until a refresh succeeds
+		if err := p.RefreshChannels(context.Background()); err != nil {
+			log.Printf("Failed to cache channels, serving without them: %v", err)
+			return
 		}
 
 		log.Println("Channels cached successfully.")
 	}
 }
 
+func newEmojiWatcher(p *provider.ApiProvider) func() {
+	return func() {
+		log.Println("Caching custom emoji...")
+
+		if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+			log.Println("Demo credentials are set, skip.")
+			return
+		}
+
+		// Emoji images are a nicety, a failure here must not stop the server
+		if err := p.RefreshEmoji(context.Background()); err != nil {
+			log.Printf("Failed to cache custom emoji: %v", err)
+			return
+		}
+
+		log.Println("Custom emoji cached successfully.")
+	}
+}
+
 func validateToolConfig(config string) error {
 	if config == "" || config == "true" || config == "1" {
 		return nil
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"os"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type AuthInfo struct {
+	TokenType      string `json:"tokenType"`
+	Team           string `json:"team"`
+	TeamID         string `json:"teamID"`
+	UserID         string `json:"userID"`
+	BotID          string `json:"botID"`
+	EnterpriseID   string `json:"enterpriseID"`
+	CanSearch      bool   `json:"canSearch"`
+	CanPost        bool   `json:"canPost"`
+	CanReadHistory bool   `json:"canReadHistory"`
+}
+
+type GridTeam struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+	URL    string `json:"url"`
+}
+
+type AuthHandler struct {
+	apiProvider *provider.ApiProvider
+}
+
+func NewAuthHandler(apiProvider *provider.ApiProvider) *AuthHandler {
+	return &AuthHandler{
+		apiProvider: apiProvider,
+	}
+}
+
+// AuthInfoHandler reports the current auth context and what it allows, so
+// missing capabilities don't have to be discovered by trial and error.
+func (ah *AuthHandler) AuthInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ah.apiProvider.AuthInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []AuthInfo{newAuthInfo(info, os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL"))}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// TeamsListHandler lists the workspaces of the Enterprise Grid, so agents
+// working across a grid know which ones exist.
+func (ah *AuthHandler) TeamsListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := ah.apiProvider.GetGridTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]GridTeam, 0, len(teams))
+	for _, t := range teams {
+		rows = append(rows, GridTeam{ID: t.ID, Name: t.Name, Domain: t.Domain, URL: t.URL})
+	}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// newAuthInfo builds the capability matrix: search.messages is unavailable
+// to bot tokens, posting requires the conversations_add_message tool to be
+// enabled and nothing can be read live in offline mode.
+func newAuthInfo(info provider.AuthInfo, addMessageToolConfig string) AuthInfo {
+	live := info.TokenType != provider.TokenTypeOffline
+
+	return AuthInfo{
+		TokenType:      info.TokenType,
+		Team:           info.Team,
+		TeamID:         info.TeamID,
+		UserID:         info.UserID,
+		BotID:          info.BotID,
+		EnterpriseID:   info.EnterpriseID,
+		CanSearch:      live && info.TokenType != provider.TokenTypeBot,
+		CanPost:        live && addMessageToolConfig != "",
+		CanReadHistory: live,
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthInfo(t *testing.T) {
+	user := newAuthInfo(provider.AuthInfo{TokenType: provider.TokenTypeUser, Team: "Acme", UserID: "U1"}, "")
+	assert.True(t, user.CanSearch)
+	assert.False(t, user.CanPost, "posting is disabled unless the add message tool is enabled")
+	assert.True(t, user.CanReadHistory)
+	assert.Equal(t, "Acme", user.Team)
+
+	bot := newAuthInfo(provider.AuthInfo{TokenType: provider.TokenTypeBot, BotID: "B1"}, "true")
+	assert.False(t, bot.CanSearch)
+	assert.True(t, bot.CanPost)
+	assert.True(t, bot.CanReadHistory)
+
+	session := newAuthInfo(provider.AuthInfo{TokenType: provider.TokenTypeSession}, "C1234567890")
+	assert.True(t, session.CanSearch)
+	assert.True(t, session.CanPost)
+
+	offline := newAuthInfo(provider.AuthInfo{TokenType: provider.TokenTypeOffline}, "true")
+	assert.False(t, offline.CanSearch)
+	assert.False(t, offline.CanPost)
+	assert.False(t, offline.CanReadHistory)
+}
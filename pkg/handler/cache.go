@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type CacheStatus struct {
+	Cache string `json:"cache"`
+	Size  int    `json:"size"`
+}
+
+type CacheHandler struct {
+	apiProvider *provider.ApiProvider
+}
+
+func NewCacheHandler(apiProvider *provider.ApiProvider) *CacheHandler {
+	return &CacheHandler{
+		apiProvider: apiProvider,
+	}
+}
+
+// CacheRefreshHandler refetches the users or channels cache from Slack,
+// bypassing the cache files, e.g. after someone joined the workspace.
+func (h *CacheHandler) CacheRefreshHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	cache := request.GetString("cache", "all")
+
+	var refreshUsers, refreshChannels bool
+	switch cache {
+	case "all":
+		refreshUsers, refreshChannels = true, true
+	case provider.SubsystemUsers:
+		refreshUsers = true
+	case provider.SubsystemChannels:
+		refreshChannels = true
+	default:
+		return nil, fmt.Errorf("invalid cache %q, must be 'users', 'channels' or 'all'", cache)
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// users first, the names of DMs are derived from them
+	if refreshUsers {
+		if err := h.apiProvider.ForceRefreshUsers(ctx); err != nil {
+			return nil, fmt.Errorf("failed to refresh the users: %w", err)
+		}
+	}
+	if refreshChannels {
+		if err := h.apiProvider.ForceRefreshChannels(ctx); err != nil {
+			return nil, fmt.Errorf("failed to refresh the channels: %w", err)
+		}
+	}
+
+	health := h.apiProvider.Health()
+	var rows []CacheStatus
+	if refreshUsers {
+		rows = append(rows, CacheStatus{Cache: provider.SubsystemUsers, Size: health.Users.Size})
+	}
+	if refreshChannels {
+		rows = append(rows, CacheStatus{Cache: provider.SubsystemChannels, Size: health.Channels.Size})
+	}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
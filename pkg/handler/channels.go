@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"cmp"
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
@@ -20,6 +26,36 @@ type Channel struct {
 	Cursor      string `json:"cursor"`
 }
 
+type ChannelInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Topic       string `json:"topic"`
+	Purpose     string `json:"purpose"`
+	MemberCount int    `json:"memberCount"`
+	IsPrivate   bool   `json:"isPrivate"`
+	IsIM        bool   `json:"isIM"`
+	IsMpIM      bool   `json:"isMpIM"`
+	IsArchived  bool   `json:"isArchived"`
+	// Slack Connect channels may not return their full history
+	IsShared           bool `json:"isShared"`
+	IsExtShared        bool `json:"isExtShared"`
+	IsPendingExtShared bool `json:"isPendingExtShared"`
+}
+
+type ActiveChannel struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	MemberCount int    `json:"memberCount"`
+	LatestTS    string `json:"latestTs"`
+	LatestTime  string `json:"latestTime"`
+}
+
+type ChannelMember struct {
+	UserID   string `json:"userID"`
+	UserName string `json:"userName"`
+	RealName string `json:"realName"`
+}
+
 type ChannelsHandler struct {
 	apiProvider *provider.ApiProvider
 	validTypes  map[string]bool
@@ -39,16 +75,22 @@ func NewChannelsHandler(apiProvider *provider.ApiProvider) *ChannelsHandler {
 
 func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	sortType := request.GetString("sort", "popularity")
-	types := request.GetString("channel_types", provider.PubChanType)
+	compare, err := channelOrder(sortType, request.GetString("sort_direction", "asc"))
+	if err != nil {
+		return nil, err
+	}
+	channelTypes := ch.channelTypes(request.GetString("channel_types", provider.PubChanType))
 
-	// MCP Inspector v0.14.0 has issues with Slice type
-	// introspection, so some type simplification makes sense here
-	channelTypes := []string{}
-	for _, t := range strings.Split(types, ",") {
-		t = strings.TrimSpace(t)
-		if ch.validTypes[t] {
-			channelTypes = append(channelTypes, t)
-		}
+	// An empty list would pass for a workspace without channels
+	if err := ch.apiProvider.Available(provider.SubsystemChannels); err != nil {
+		return nil, err
+	}
+
+	includeArchived := request.GetBool("include_archived", false)
+	memberOnly := request.GetBool("member_only", false)
+	memberRange := provider.MemberRange{
+		Min: request.GetInt("min_members", 0),
+		Max: request.GetInt("max_members", 0),
 	}
 
 	cursor := request.GetString("cursor", "")
@@ -65,7 +107,10 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 		channelList []Channel
 	)
 
-	channels := filterChannelsByTypes(ch.apiProvider.ProvideChannelsMaps().Channels, channelTypes)
+	channels := filterChannelsByTypes(ch.apiProvider.ProvideChannelsMaps().Channels, channelTypes, includeArchived)
+	channels = slices.DeleteFunc(channels, func(c provider.Channel) bool {
+		return !memberRange.Contains(c.MemberCount) || (memberOnly && !c.IsMember)
+	})
 
 	var chans []provider.Channel
 
@@ -73,6 +118,7 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 		channels,
 		cursor,
 		limit,
+		compare,
 	)
 
 	for _, channel := range chans {
@@ -106,7 +152,251 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 	return mcp.NewToolResultText(string(csvBytes)), nil
 }
 
-func filterChannelsByTypes(channels map[string]provider.Channel, types []string) []provider.Channel {
+// channelSearchFields are the fields channels_search matches against.
+var channelSearchFields = map[string]func(c provider.Channel) string{
+	"name":    func(c provider.Channel) string { return c.Name },
+	"topic":   func(c provider.Channel) string { return c.Topic },
+	"purpose": func(c provider.Channel) string { return c.Purpose },
+}
+
+// ChannelsSearchHandler lists the cached channels whose name, topic or
+// purpose contains the query, case-insensitively. Nothing is fetched from
+// Slack.
+func (ch *ChannelsHandler) ChannelsSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := strings.ToLower(strings.TrimSpace(normalizeString(request.GetString("query", ""))))
+	if query == "" {
+		return nil, errors.New("query must be a non-empty string")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := request.GetString("fields", "all")
+	var matchers []func(c provider.Channel) string
+	if fields == "all" {
+		matchers = []func(c provider.Channel) string{
+			channelSearchFields["name"],
+			channelSearchFields["topic"],
+			channelSearchFields["purpose"],
+		}
+	} else {
+		for _, f := range strings.Split(fields, ",") {
+			m, ok := channelSearchFields[strings.TrimSpace(f)]
+			if !ok {
+				return nil, fmt.Errorf("invalid field %q, must be 'name', 'topic', 'purpose' or 'all'", strings.TrimSpace(f))
+			}
+			matchers = append(matchers, m)
+		}
+	}
+
+	// An empty result would pass for a query matching nothing
+	if err := ch.apiProvider.Available(provider.SubsystemChannels); err != nil {
+		return nil, err
+	}
+
+	includeArchived := request.GetBool("include_archived", false)
+	limit := request.GetInt("limit", 0)
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var matches []provider.Channel
+	for _, c := range ch.apiProvider.ProvideChannelsMaps().Channels {
+		if c.IsArchived && !includeArchived {
+			continue
+		}
+		if slices.ContainsFunc(matchers, func(field func(c provider.Channel) string) bool {
+			return strings.Contains(strings.ToLower(normalizeString(field(c))), query)
+		}) {
+			matches = append(matches, c)
+		}
+	}
+	slices.SortFunc(matches, func(a, b provider.Channel) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.ID, b.ID))
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	channelList := make([]Channel, 0, len(matches))
+	for _, c := range matches {
+		channelList = append(channelList, Channel{
+			ID:          c.ID,
+			Name:        c.Name,
+			Topic:       c.Topic,
+			Purpose:     c.Purpose,
+			MemberCount: c.MemberCount,
+		})
+	}
+
+	content, err := marshalRows(&channelList, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// maxActivityProbes caps how many channels channels_recently_active looks
+// up per call, each costs a conversations.history request.
+const maxActivityProbes = 200
+
+// ChannelsRecentlyActiveHandler lists the cached channels by their latest
+// message, most recent first.
+func (ch *ChannelsHandler) ChannelsRecentlyActiveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	channelTypes := ch.channelTypes(request.GetString("channel_types", "public_channel,private_channel"))
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.apiProvider.Available(provider.SubsystemChannels); err != nil {
+		return nil, err
+	}
+
+	limit := request.GetInt("limit", 0)
+	if limit <= 0 {
+		limit = 20
+	}
+	maxProbes := min(request.GetInt("max_probed", 50), maxActivityProbes)
+
+	channels := filterChannelsByTypes(ch.apiProvider.ProvideChannelsMaps().Channels, channelTypes, false)
+	active, err := ch.apiProvider.GetRecentlyActiveChannels(ctx, channels, maxProbes)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > limit {
+		active = active[:limit]
+	}
+
+	rows := make([]ActiveChannel, 0, len(active))
+	for _, c := range active {
+		row := ActiveChannel{
+			ID:          c.ID,
+			Name:        c.Name,
+			MemberCount: c.MemberCount,
+			LatestTS:    c.LatestTS,
+		}
+		if secs, err := strconv.ParseFloat(c.LatestTS, 64); err == nil {
+			row.LatestTime = time.Unix(int64(secs), 0).UTC().Format(time.RFC3339)
+		}
+		rows = append(rows, row)
+	}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// ChannelsInfoHandler returns a single channel fetched with
+// conversations.info, bypassing the channels cache for its details.
+func (ch *ChannelsHandler) ChannelsInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	channelID := request.GetString("channel_id", "")
+	if channelID == "" {
+		return nil, errors.New("channel_id must be a string")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+	channelID, err = ch.apiProvider.ResolveChannelID(channelID, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := ch.apiProvider.GetChannelInfo(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := []ChannelInfo{{
+		ID:          channel.ID,
+		Name:        channel.Name,
+		Topic:       channel.Topic,
+		Purpose:     channel.Purpose,
+		MemberCount: channel.MemberCount,
+		IsPrivate:   channel.IsPrivate,
+		IsIM:        channel.IsIM,
+		IsMpIM:      channel.IsMpIM,
+		IsArchived:  channel.IsArchived,
+
+		IsShared:           channel.IsShared,
+		IsExtShared:        channel.IsExtShared,
+		IsPendingExtShared: channel.IsPendingExtShared,
+	}}
+
+	content, err := marshalRows(&info, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// ChannelsMembersHandler lists the members of a channel with their names,
+// resolved through the users cache. Unknown users keep their ID as name.
+func (ch *ChannelsHandler) ChannelsMembersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	channelID := request.GetString("channel_id", "")
+	if channelID == "" {
+		return nil, errors.New("channel_id must be a string")
+	}
+	limit := request.GetInt("limit", 0)
+	if limit < 0 {
+		return nil, errors.New("limit must not be negative")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+	channelID, err = ch.apiProvider.ResolveChannelID(channelID, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := ch.apiProvider.GetChannelMembers(ctx, channelID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users := ch.apiProvider.ResolveUsers(ids)
+	members := make([]ChannelMember, 0, len(ids))
+	for _, id := range ids {
+		m := ChannelMember{UserID: id, UserName: id, RealName: id}
+		if u, ok := users[id]; ok {
+			m.UserName = u.Name
+			m.RealName = u.RealName
+		}
+		members = append(members, m)
+	}
+
+	content, err := marshalRows(&members, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// channelTypes parses a comma-separated channel_types parameter, unknown
+// types are ignored.
+func (ch *ChannelsHandler) channelTypes(types string) []string {
+	// MCP Inspector v0.14.0 has issues with Slice type
+	// introspection, so some type simplification makes sense here
+	channelTypes := []string{}
+	for _, t := range strings.Split(types, ",") {
+		t = strings.TrimSpace(t)
+		if ch.validTypes[t] {
+			channelTypes = append(channelTypes, t)
+		}
+	}
+	return channelTypes
+}
+
+func filterChannelsByTypes(channels map[string]provider.Channel, types []string, includeArchived bool) []provider.Channel {
 	var result []provider.Channel
 	typeSet := make(map[string]bool)
 
@@ -115,6 +405,9 @@ func filterChannelsByTypes(channels map[string]provider.Channel, types []string)
 	}
 
 	for _, ch := range channels {
+		if ch.IsArchived && !includeArchived {
+			continue
+		}
 		if typeSet["public_channel"] && !ch.IsPrivate && !ch.IsIM && !ch.IsMpIM {
 			result = append(result, ch)
 		}
@@ -131,19 +424,71 @@ func filterChannelsByTypes(channels map[string]provider.Channel, types []string)
 	return result
 }
 
-func paginateChannels(channels []provider.Channel, cursor string, limit int) ([]provider.Channel, string) {
-	sort.Slice(channels, func(i, j int) bool {
-		return channels[i].ID < channels[j].ID
+// channelSorts are the sort values of channels_list ordering all the
+// listed channels, not just a page, ascending.
+var channelSorts = map[string]func(a, b provider.Channel) int{
+	"name": func(a, b provider.Channel) int {
+		return strings.Compare(a.Name, b.Name)
+	},
+	"member_count": func(a, b provider.Channel) int {
+		return cmp.Compare(a.MemberCount, b.MemberCount)
+	},
+	"created": func(a, b provider.Channel) int {
+		return cmp.Compare(a.Created, b.Created)
+	},
+}
+
+// channelOrder returns the comparison ordering channels for sortType and
+// direction, nil for popularity, which sorts each page by itself instead.
+func channelOrder(sortType, direction string) (func(a, b provider.Channel) int, error) {
+	var desc bool
+	switch direction {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return nil, fmt.Errorf("invalid sort_direction %q, must be 'asc' or 'desc'", direction)
+	}
+
+	if sortType == "popularity" {
+		return nil, nil
+	}
+	compare, ok := channelSorts[sortType]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort %q, must be 'popularity', 'name', 'member_count' or 'created'", sortType)
+	}
+	if desc {
+		return func(a, b provider.Channel) int { return compare(b, a) }, nil
+	}
+	return compare, nil
+}
+
+// paginateChannels orders channels by compare, or by ID when it is nil, and
+// returns the page following the channel encoded in cursor. Ties are
+// broken by ID so that consecutive pages neither repeat nor skip channels.
+func paginateChannels(channels []provider.Channel, cursor string, limit int, compare func(a, b provider.Channel) int) ([]provider.Channel, string) {
+	slices.SortFunc(channels, func(a, b provider.Channel) int {
+		if compare != nil {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.ID, b.ID)
 	})
 
 	startIndex := 0
 	if cursor != "" {
 		if decoded, err := base64.StdEncoding.DecodeString(cursor); err == nil {
 			lastID := string(decoded)
-			for i, ch := range channels {
-				if ch.ID > lastID {
-					startIndex = i
-					break
+			if i := slices.IndexFunc(channels, func(ch provider.Channel) bool { return ch.ID == lastID }); i >= 0 {
+				startIndex = i + 1
+			} else if compare == nil {
+				// the channel is gone, continue after where it would be
+				for i, ch := range channels {
+					if ch.ID > lastID {
+						startIndex = i
+						break
+					}
 				}
 			}
 		}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelsHandler_Sort(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+	assert.NoError(t, os.WriteFile(cache, []byte(`[
+		{"id": "C1", "name": "#general", "memberCount": 120, "created": 1500000000},
+		{"id": "C2", "name": "#alerts", "memberCount": 15, "created": 1700000000},
+		{"id": "C3", "name": "#random", "memberCount": 120, "created": 1600000000},
+		{"id": "C4", "name": "#deploys", "memberCount": 40, "created": 1650000000}
+	]`), 0644))
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", cache)
+	t.Setenv("SLACK_MCP_USERS_CACHE", filepath.Join(t.TempDir(), "users_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshChannels(context.Background()))
+	ch := NewChannelsHandler(p)
+
+	list := func(args map[string]any) ([]string, string) {
+		t.Helper()
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]any{"channel_types": "public_channel"}
+		for k, v := range args {
+			req.Params.Arguments.(map[string]any)[k] = v
+		}
+		res, err := ch.ChannelsHandler(context.Background(), req)
+		assert.NoError(t, err)
+
+		var rows []Channel
+		assert.NoError(t, gocsv.UnmarshalString(res.Content[0].(mcp.TextContent).Text, &rows))
+		var ids []string
+		var cursor string
+		for _, r := range rows {
+			ids = append(ids, r.ID)
+			cursor = r.Cursor
+		}
+		return ids, cursor
+	}
+
+	ids, _ := list(map[string]any{"sort": "name"})
+	assert.Equal(t, []string{"C2", "C4", "C1", "C3"}, ids)
+
+	ids, _ = list(map[string]any{"sort": "created", "sort_direction": "desc"})
+	assert.Equal(t, []string{"C2", "C4", "C3", "C1"}, ids)
+
+	// the whole list is sorted before paging, ties broken by ID
+	ids, cursor := list(map[string]any{"sort": "member_count", "sort_direction": "desc", "limit": 2})
+	assert.Equal(t, []string{"C1", "C3"}, ids)
+	ids, cursor = list(map[string]any{"sort": "member_count", "sort_direction": "desc", "limit": 2, "cursor": cursor})
+	assert.Equal(t, []string{"C4", "C2"}, ids)
+	assert.Empty(t, cursor)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"channel_types": "public_channel", "sort": "name", "sort_direction": "up"}
+	_, err = ch.ChannelsHandler(context.Background(), req)
+	assert.EqualError(t, err, `invalid sort_direction "up", must be 'asc' or 'desc'`)
+
+	req.Params.Arguments = map[string]any{"channel_types": "public_channel", "sort": "nmae"}
+	_, err = ch.ChannelsHandler(context.Background(), req)
+	assert.EqualError(t, err, `invalid sort "nmae", must be 'popularity', 'name', 'member_count' or 'created'`)
+}
+
+func TestChannelsHandler_Search(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+	assert.NoError(t, os.WriteFile(cache, []byte(`[
+		{"id": "C1", "name": "#general", "topic": "Company-wide announcements", "purpose": "Everything else"},
+		{"id": "C2", "name": "#ops", "topic": "On-call: @alice", "purpose": "Incident\u200b response"},
+		{"id": "C3", "name": "#incidents-archive", "purpose": "Old incidents", "archived": true},
+		{"id": "C4", "name": "#design", "topic": "Weekly DESIGN reviews"}
+	]`), 0644))
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", cache)
+	t.Setenv("SLACK_MCP_USERS_CACHE", filepath.Join(t.TempDir(), "users_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshChannels(context.Background()))
+	ch := NewChannelsHandler(p)
+
+	search := func(args map[string]any) []string {
+		t.Helper()
+		var req mcp.CallToolRequest
+		req.Params.Arguments = args
+		res, err := ch.ChannelsSearchHandler(context.Background(), req)
+		assert.NoError(t, err)
+
+		var rows []Channel
+		assert.NoError(t, gocsv.UnmarshalString(res.Content[0].(mcp.TextContent).Text, &rows))
+		var ids []string
+		for _, r := range rows {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	// invisible characters do not break a match
+	assert.Equal(t, []string{"C2"}, search(map[string]any{"query": "incident response"}))
+	assert.Equal(t, []string{"C3", "C2"}, search(map[string]any{"query": "INCIDENT", "include_archived": true}))
+	assert.Equal(t, []string{"C4"}, search(map[string]any{"query": "design", "fields": "topic"}))
+	assert.Empty(t, search(map[string]any{"query": "announcements", "fields": "name,purpose"}))
+	assert.Equal(t, []string{"C4", "C1"}, search(map[string]any{"query": "e", "fields": "name"}))
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"query": "ops", "fields": "owner"}
+	_, err = ch.ChannelsSearchHandler(context.Background(), req)
+	assert.EqualError(t, err, `invalid field "owner", must be 'name', 'topic', 'purpose' or 'all'`)
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,23 +22,42 @@ import (
 )
 
 type Message struct {
-	UserID   string `json:"userID"`
-	UserName string `json:"userUser"`
-	RealName string `json:"realName"`
-	Channel  string `json:"channelID"`
-	ThreadTs string `json:"ThreadTs"`
-	Text     string `json:"text"`
-	Time     string `json:"time"`
-	Cursor   string `json:"cursor"`
+	UserID    string `json:"userID"`
+	UserName  string `json:"userUser"`
+	RealName  string `json:"realName"`
+	Channel   string `json:"channelID"`
+	ThreadTs  string `json:"ThreadTs"`
+	Text      string `json:"text"`
+	Time      string `json:"time"`
+	Permalink string `json:"permalink,omitempty" csv:"-"` // see messageWithPermalink
+	Cursor    string `json:"cursor"`
+}
+
+// messageWithPermalink is the CSV row of a Message when permalinks were
+// asked for, so that the column is only there when it is filled.
+type messageWithPermalink struct {
+	Message
+	Permalink string `csv:"Permalink"`
+}
+
+type Permalink struct {
+	TeamURL     string `json:"teamURL"`
+	ChannelID   string `json:"channelID"`
+	ChannelName string `json:"channelName"`
+	Ts          string `json:"ts"`
+	ThreadTs    string `json:"threadTs"`
 }
 
 type conversationParams struct {
-	channel  string
-	limit    int
-	oldest   string
-	latest   string
-	cursor   string
-	activity bool
+	channel    string
+	limit      int
+	oldest     string
+	latest     string
+	cursor     string
+	activity   bool
+	permalinks bool
+	reach      bool
+	quotes     bool // collapse quoted replies
 }
 
 var validFilterKeys = map[string]struct{}{
@@ -83,6 +103,15 @@ type setTopicParams struct {
 	topic   string
 }
 
+type markReadParams struct {
+	channel string
+	ts      string
+}
+
+type leaveChannelParams struct {
+	channel string
+}
+
 type ConversationsHandler struct {
 	apiProvider *provider.ApiProvider
 }
@@ -147,9 +176,9 @@ func (ch *ConversationsHandler) ConversationsAddMessageHandler(ctx context.Conte
 		return nil, err
 	}
 
-	messages := ch.convertMessagesFromHistory(history.Messages, historyParams.ChannelID, false)
+	messages := ch.convertMessagesFromHistory(ctx, history.Messages, historyParams.ChannelID, false, false, false, false)
 
-	return marshalMessagesToCSV(messages)
+	return marshalMessagesToCSV(messages, false)
 }
 
 func (ch *ConversationsHandler) ConversationsHistoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -177,13 +206,13 @@ func (ch *ConversationsHandler) ConversationsHistoryHandler(ctx context.Context,
 		return nil, err
 	}
 
-	messages := ch.convertMessagesFromHistory(history.Messages, params.channel, params.activity)
+	messages := ch.convertMessagesFromHistory(ctx, history.Messages, params.channel, params.activity, params.permalinks, params.reach, params.quotes)
 
 	if len(messages) > 0 && history.HasMore {
 		messages[len(messages)-1].Cursor = history.ResponseMetaData.NextCursor
 	}
 
-	return marshalMessagesToCSV(messages)
+	return marshalMessagesToCSV(messages, params.permalinks)
 }
 
 func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -197,11 +226,6 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 		return nil, errors.New("thread_ts must be a string")
 	}
 
-	api, err := ch.apiProvider.ProvideGeneric()
-	if err != nil {
-		return nil, err
-	}
-
 	repliesParams := slack.GetConversationRepliesParameters{
 		ChannelID: params.channel,
 		Timestamp: threadTs,
@@ -212,18 +236,18 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 		Inclusive: false,
 	}
 
-	replies, hasMore, nextCursor, err := api.GetConversationRepliesContext(ctx, &repliesParams)
+	replies, hasMore, nextCursor, err := ch.apiProvider.GetThreadReplies(ctx, &repliesParams)
 	if err != nil {
 		return nil, err
 	}
 
-	messages := ch.convertMessagesFromHistory(replies, params.channel, params.activity)
+	messages := ch.convertMessagesFromHistory(ctx, replies, params.channel, params.activity, params.permalinks, params.reach, params.quotes)
 
 	if len(messages) > 0 && hasMore {
 		messages[len(messages)-1].Cursor = nextCursor
 	}
 
-	return marshalMessagesToCSV(messages)
+	return marshalMessagesToCSV(messages, params.permalinks)
 }
 
 func (ch *ConversationsHandler) ConversationsSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -236,6 +260,9 @@ func (ch *ConversationsHandler) ConversationsSearchHandler(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if !ch.apiProvider.HasScope("search:read") {
+		return nil, errors.New("search.messages requires the search:read scope, which the token lacks")
+	}
 
 	searchParams := slack.SearchParameters{
 		Sort:          slack.DEFAULT_SEARCH_SORT,
@@ -257,7 +284,7 @@ func (ch *ConversationsHandler) ConversationsSearchHandler(ctx context.Context,
 		messages[len(messages)-1].Cursor = base64.StdEncoding.EncodeToString([]byte(nextCursor))
 	}
 
-	return marshalMessagesToCSV(messages)
+	return marshalMessagesToCSV(messages, false)
 }
 
 func (ch *ConversationsHandler) ConversationsCreateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -341,6 +368,108 @@ func (ch *ConversationsHandler) ConversationsSetTopicHandler(ctx context.Context
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully set topic for channel %s: %s", channel.Name, params.topic)), nil
 }
 
+func (ch *ConversationsHandler) ConversationsMarkReadHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// conversations.mark tracks the read cursor of a user, bots have none
+	if ch.apiProvider.IsBotToken() {
+		return nil, errors.New("conversations_mark_read requires a user or session token, bots cannot mark channels as read")
+	}
+
+	params, err := ch.parseParamsToolMarkRead(request)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := ch.apiProvider.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := api.MarkConversationContext(ctx, params.channel, params.ts); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Marked channel %s as read up to %s", params.channel, params.ts)), nil
+}
+
+// PermalinkResolveHandler splits a pasted permalink into the channel and
+// the message timestamps to pass to conversations_replies or
+// conversations_history, the channel named from the channels cache.
+func (ch *ConversationsHandler) PermalinkResolveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	link := request.GetString("permalink", "")
+	if link == "" {
+		return nil, errors.New("permalink must be a string")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := provider.ParsePermalink(link)
+	if err != nil {
+		return nil, err
+	}
+
+	// an unknown channel, e.g. of another workspace, is left unnamed
+	name, _ := ch.apiProvider.ChannelNameByID(parts.ChannelID)
+
+	rows := []Permalink{{
+		TeamURL:     parts.TeamURL,
+		ChannelID:   parts.ChannelID,
+		ChannelName: name,
+		Ts:          parts.Ts,
+		ThreadTs:    parts.ThreadTs,
+	}}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+func (ch *ConversationsHandler) ConversationsJoinHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id must be a string")
+	}
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
+	}
+
+	joined, alreadyMember, err := ch.apiProvider.JoinChannel(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "joined"
+	if alreadyMember {
+		status = "already_member"
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Channel %s (ID: %s) membership: %s", joined.Name, joined.ID, status)), nil
+}
+
+func (ch *ConversationsHandler) ConversationsLeaveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params, err := ch.parseParamsToolLeaveChannel(request)
+	if err != nil {
+		return nil, err
+	}
+
+	channelID, notMember, err := ch.apiProvider.LeaveChannel(ctx, params.channel)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "left"
+	if notMember {
+		status = "not_member"
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Channel ID: %s membership: %s", channelID, status)), nil
+}
+
 func isChannelAllowed(channel string) bool {
 	config := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
 	if config == "" || config == "true" || config == "1" {
@@ -366,55 +495,122 @@ func isChannelAllowed(channel string) bool {
 	return !isNegated
 }
 
-func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack.Message, channel string, includeActivity bool) []Message {
-	usersMap := ch.apiProvider.ProvideUsersMap()
-	var messages []Message
-
+func (ch *ConversationsHandler) convertMessagesFromHistory(ctx context.Context, slackMessages []slack.Message, channel string, includeActivity, includePermalinks, broadcastReach, collapseQuotes bool) []Message {
+	userIDs := make([]string, 0, len(slackMessages))
 	for _, msg := range slackMessages {
+		userIDs = append(userIDs, msg.User)
+	}
+	usersMap := ch.apiProvider.ResolveUsers(userIDs)
+
+	// Broadcast mentions are annotated with the cached member count, it is
+	// not worth a conversations.info call per page
+	var reach int
+	if broadcastReach {
+		c, _ := ch.apiProvider.ChannelByID(channel)
+		reach = c.MemberCount
+	}
+
+	// Extract text from all message content (text, blocks, attachments)
+	texts := make([]string, len(slackMessages))
+	for i := range slackMessages {
+		texts[i] = text.ExtractTextFromMessage(&slackMessages[i])
+	}
+	if collapseQuotes {
+		texts = collapseQuotedReplies(slackMessages, texts)
+	}
+
+	var (
+		messages []Message
+		err      error
+	)
+
+	for i, msg := range slackMessages {
 		if msg.SubType != "" && !includeActivity {
 			continue
 		}
 
-		userName, realName := getUserInfo(msg.User, usersMap.Users)
+		userName, realName := getUserInfo(msg.User, usersMap)
 
-		// Extract text from all message content (text, blocks, attachments)
-		messageText := text.ExtractTextFromMessage(&msg)
 		// Process the extracted text (clean up special chars, etc.)
-		processedText := text.ProcessText(messageText)
+		processedText := text.ProcessTextWithChannels(texts[i], nil, ch.channelName, reach)
+
+		var permalink string
+		if includePermalinks {
+			if permalink, err = ch.apiProvider.Permalink(ctx, channel, msg.Timestamp, msg.ThreadTimestamp); err != nil {
+				log.Printf("Failed to get permalink for %s in %s: %v", msg.Timestamp, channel, err)
+			}
+		}
 
 		messages = append(messages, Message{
-			UserID:   msg.User,
-			UserName: userName,
-			RealName: realName,
-			Text:     processedText,
-			Channel:  channel,
-			ThreadTs: msg.ThreadTimestamp,
-			Time:     msg.Timestamp,
+			UserID:    msg.User,
+			UserName:  userName,
+			RealName:  realName,
+			Text:      processedText,
+			Channel:   channel,
+			ThreadTs:  msg.ThreadTimestamp,
+			Time:      msg.Timestamp,
+			Permalink: permalink,
 		})
 	}
 
 	return messages
 }
 
+// channelName is the text.ChannelNamer rendering channel mentions from the
+// channels cache.
+func (ch *ConversationsHandler) channelName(id string) (string, bool) {
+	name, ok := ch.apiProvider.ChannelNameByID(id)
+	return strings.TrimPrefix(name, provider.ChannelNamePrefix()), ok
+}
+
+// collapseQuotedReplies applies text.CollapseQuotedReplies to the texts of
+// msgs, which history lists newest first and replies oldest first.
+func collapseQuotedReplies(msgs []slack.Message, texts []string) []string {
+	order := make([]int, len(msgs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return msgs[order[a]].Timestamp < msgs[order[b]].Timestamp
+	})
+
+	chrono := make([]string, len(texts))
+	for i, idx := range order {
+		chrono[i] = texts[idx]
+	}
+	chrono = text.CollapseQuotedReplies(chrono)
+
+	res := make([]string, len(texts))
+	for i, idx := range order {
+		res[idx] = chrono[i]
+	}
+	return res
+}
+
 func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.SearchMessage) []Message {
-	usersMap := ch.apiProvider.ProvideUsersMap()
+	userIDs := make([]string, 0, len(slackMessages))
+	for _, msg := range slackMessages {
+		userIDs = append(userIDs, msg.User)
+	}
+	usersMap := ch.apiProvider.ResolveUsers(userIDs)
+
 	var messages []Message
 
 	for _, msg := range slackMessages {
-		userName, realName := getUserInfo(msg.User, usersMap.Users)
+		userName, realName := getUserInfo(msg.User, usersMap)
 		threadTs, _ := extractThreadTS(msg.Permalink)
 
 		// Extract text from all message content (text, blocks, attachments)
 		messageText := text.ExtractTextFromSearchMessage(&msg)
 		// Process the extracted text (clean up special chars, etc.)
-		processedText := text.ProcessText(messageText)
+		processedText := text.ProcessTextWithChannels(messageText, nil, ch.channelName, 0)
 
 		messages = append(messages, Message{
 			UserID:   msg.User,
 			UserName: userName,
 			RealName: realName,
 			Text:     processedText,
-			Channel:  fmt.Sprintf("#%s", msg.Channel.Name),
+			Channel:  provider.ChannelNamePrefix() + msg.Channel.Name,
 			ThreadTs: threadTs,
 			Time:     msg.Timestamp,
 		})
@@ -432,6 +628,9 @@ func (ch *ConversationsHandler) parseParamsToolConversations(request mcp.CallToo
 	limit := request.GetString("limit", "")
 	cursor := request.GetString("cursor", "")
 	activity := request.GetBool("include_activity_messages", false)
+	permalinks := request.GetBool("include_permalinks", false)
+	reach := request.GetBool("include_broadcast_reach", false)
+	quotes := request.GetBool("collapse_quoted_replies", false)
 
 	var (
 		paramLimit  int
@@ -452,23 +651,21 @@ func (ch *ConversationsHandler) parseParamsToolConversations(request mcp.CallToo
 		}
 	}
 
-	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
-		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
-		chn, ok := channelsMaps.ChannelsInv[channel]
-		if !ok {
-			return nil, fmt.Errorf("channel %q not found", channel)
-		}
-
-		channel = channelsMaps.Channels[chn].ID
+	channel, err = ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
 	}
 
 	return &conversationParams{
-		channel:  channel,
-		limit:    paramLimit,
-		oldest:   paramOldest,
-		latest:   paramLatest,
-		cursor:   cursor,
-		activity: activity,
+		channel:    channel,
+		limit:      paramLimit,
+		oldest:     paramOldest,
+		latest:     paramLatest,
+		cursor:     cursor,
+		activity:   activity,
+		permalinks: permalinks,
+		reach:      reach,
+		quotes:     quotes,
 	}, nil
 }
 
@@ -483,14 +680,9 @@ func (ch *ConversationsHandler) parseParamsToolAddMessage(request mcp.CallToolRe
 		return nil, errors.New("channel_id must be a string")
 	}
 
-	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
-		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
-		chn, ok := channelsMaps.ChannelsInv[channel]
-		if !ok {
-			return nil, fmt.Errorf("channel %q not found", channel)
-		}
-
-		channel = channelsMaps.Channels[chn].ID
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
 	}
 
 	if !isChannelAllowed(channel) {
@@ -523,7 +715,12 @@ func (ch *ConversationsHandler) parseParamsToolAddMessage(request mcp.CallToolRe
 func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (*searchParams, error) {
 	rawQuery := strings.TrimSpace(req.GetString("search_query", ""))
 
+	caseSensitive := req.GetBool("case_sensitive", false)
+
 	freeText, filters := splitQuery(rawQuery)
+	if err := ch.rewriteQueryFilters(filters, caseSensitive); err != nil {
+		return nil, err
+	}
 
 	// is:thread
 	if req.GetBool("filter_threads_only", false) {
@@ -532,7 +729,7 @@ func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (
 
 	// in:channel or in:IM
 	if chName := req.GetString("filter_in_channel", ""); chName != "" {
-		f, err := ch.paramFormatChannel(chName)
+		f, err := ch.paramFormatChannel(chName, caseSensitive)
 		if err != nil {
 			return nil, err
 		}
@@ -610,6 +807,68 @@ func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (
 	}, nil
 }
 
+// rewriteQueryFilters rewrites two modifiers typed into search_query which
+// search.messages handles unreliably. from:@name becomes from:<@U...> when
+// the name is the username, display name or real name of exactly one
+// active user. in:#name, with or without the configured channel prefix,
+// becomes the canonical in:#name. All other modifiers and values, e.g.
+// from:me or in:<#C...>, are passed on as they are.
+func (ch *ConversationsHandler) rewriteQueryFilters(filters map[string][]string, caseSensitive bool) error {
+	for i, val := range filters["from"] {
+		if !strings.HasPrefix(val, "@") {
+			continue
+		}
+		uh := UsersHandler{apiProvider: ch.apiProvider}
+		matches, err := uh.resolveUsers(val, "auto", true, false)
+		if err != nil {
+			return err
+		}
+
+		var exact []UserResolution
+		for _, m := range matches {
+			if strings.HasSuffix(m.MatchType, "_exact") {
+				exact = append(exact, m)
+			}
+		}
+		switch {
+		case len(exact) == 1:
+			filters["from"][i] = fmt.Sprintf("<@%s>", exact[0].UserID)
+		case len(exact) > 1:
+			return fmt.Errorf("search modifier from:%s is ambiguous, it names %s", val, userCandidates(exact))
+		case len(matches) > 0:
+			return fmt.Errorf("search modifier from:%s: no user has exactly that name, did you mean %s", val, userCandidates(matches))
+		default:
+			return fmt.Errorf("search modifier from:%s: user not found", val)
+		}
+	}
+
+	for i, val := range filters["in"] {
+		if !strings.HasPrefix(val, "#") {
+			continue
+		}
+		f, err := ch.paramFormatChannel(val, caseSensitive)
+		if err != nil {
+			return fmt.Errorf("search modifier in:%s: %w", val, err)
+		}
+		filters["in"][i] = f
+	}
+
+	return nil
+}
+
+// userCandidates lists matches for an error message, at most
+// maxOtherMatches of them.
+func userCandidates(matches []UserResolution) string {
+	var names []string
+	for _, m := range matches[:min(len(matches), maxOtherMatches)] {
+		names = append(names, fmt.Sprintf("@%s (%s)", m.UserName, m.UserID))
+	}
+	if len(matches) > maxOtherMatches {
+		names = append(names, fmt.Sprintf("and %d more", len(matches)-maxOtherMatches))
+	}
+	return strings.Join(names, ", ")
+}
+
 func (ch *ConversationsHandler) paramFormatUser(raw string) (string, error) {
 	users := ch.apiProvider.ProvideUsersMap()
 
@@ -651,36 +910,63 @@ func (ch *ConversationsHandler) paramFormatUser(raw string) (string, error) {
 	}
 }
 
-func (ch *ConversationsHandler) paramFormatChannel(raw string) (string, error) {
+// paramFormatChannel converts a channel reference to the form the in:
+// modifier of search.messages expects: #name for channels and @user for
+// DMs. Group DMs have no such form and are rejected.
+func (ch *ConversationsHandler) paramFormatChannel(raw string, caseSensitive bool) (string, error) {
 	raw = strings.TrimSpace(raw)
-	cms := ch.apiProvider.ProvideChannelsMaps()
-	if strings.HasPrefix(raw, "#") {
-		if chID, ok := cms.ChannelsInv[raw]; ok {
-			return cms.Channels[chID].Name, nil
+	id, err := ch.apiProvider.ResolveChannelID(raw, caseSensitive)
+	if err != nil {
+		return "", err
+	}
+	chn, ok := ch.apiProvider.ChannelByID(id)
+	if !ok {
+		// Handle both C (standard channels) and G (private groups/channels) prefixes
+		if strings.HasPrefix(raw, "#") || strings.HasPrefix(raw, "C") || strings.HasPrefix(raw, "G") {
+			return "", fmt.Errorf("channel %q not found", raw)
 		}
-		return "", fmt.Errorf("channel %q not found", raw)
+		return "", fmt.Errorf("invalid channel format: %q", raw)
 	}
-	// Handle both C (standard channels) and G (private groups/channels) prefixes
-	if strings.HasPrefix(raw, "C") || strings.HasPrefix(raw, "G") {
-		if chn, ok := cms.Channels[raw]; ok {
-			return chn.Name, nil
+
+	switch {
+	case chn.IsMpIM:
+		return "", fmt.Errorf("channel %q is a group DM, search can't be limited to it", raw)
+	case chn.IsIM:
+		user, ok := ch.apiProvider.ResolveUser(chn.User)
+		if !ok {
+			return "", fmt.Errorf("user %q of DM %q not found", chn.User, raw)
 		}
-		return "", fmt.Errorf("channel %q not found", raw)
+		return "@" + user.Name, nil
 	}
-	return "", fmt.Errorf("invalid channel format: %q", raw)
+	return "#" + strings.TrimPrefix(chn.Name, provider.ChannelNamePrefix()), nil
 }
 
-func marshalMessagesToCSV(messages []Message) (*mcp.CallToolResult, error) {
-	csvBytes, err := gocsv.MarshalBytes(&messages)
+func marshalMessagesToCSV(messages []Message, includePermalinks bool) (*mcp.CallToolResult, error) {
+	var (
+		csvBytes []byte
+		err      error
+	)
+	if includePermalinks {
+		rows := make([]messageWithPermalink, 0, len(messages))
+		for _, msg := range messages {
+			rows = append(rows, messageWithPermalink{Message: msg, Permalink: msg.Permalink})
+		}
+		csvBytes, err = gocsv.MarshalBytes(&rows)
+	} else {
+		csvBytes, err = gocsv.MarshalBytes(&messages)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return mcp.NewToolResultText(string(csvBytes)), nil
 }
 
+// getUserInfo returns the names of the author of a message: the one picked
+// by SLACK_MCP_DISPLAY_NAME_PREF, the username by default, and the real
+// name. Users missing from usersMap keep their ID as both.
 func getUserInfo(userID string, usersMap map[string]slack.User) (userName, realName string) {
 	if user, ok := usersMap[userID]; ok {
-		return user.Name, user.RealName
+		return text.UserName(user, text.NameUsername), user.RealName
 	}
 	return userID, userID
 }
@@ -906,14 +1192,9 @@ func (ch *ConversationsHandler) parseParamsToolRenameChannel(request mcp.CallToo
 		return nil, errors.New("channel name must be 80 characters or less")
 	}
 
-	// Convert channel name to ID if necessary
-	if strings.HasPrefix(channel, "#") {
-		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
-		chn, ok := channelsMaps.ChannelsInv[channel]
-		if !ok {
-			return nil, fmt.Errorf("channel %q not found", channel)
-		}
-		channel = channelsMaps.Channels[chn].ID
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
 	}
 
 	return &renameChannelParams{
@@ -933,14 +1214,9 @@ func (ch *ConversationsHandler) parseParamsToolInviteUsers(request mcp.CallToolR
 		return nil, errors.New("users must be a comma-separated string of user IDs")
 	}
 
-	// Convert channel name to ID if necessary
-	if strings.HasPrefix(channel, "#") {
-		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
-		chn, ok := channelsMaps.ChannelsInv[channel]
-		if !ok {
-			return nil, fmt.Errorf("channel %q not found", channel)
-		}
-		channel = channelsMaps.Channels[chn].ID
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse user IDs
@@ -991,14 +1267,9 @@ func (ch *ConversationsHandler) parseParamsToolSetTopic(request mcp.CallToolRequ
 		return nil, errors.New("topic must be a string")
 	}
 
-	// Convert channel name to ID if necessary
-	if strings.HasPrefix(channel, "#") {
-		channelsMaps := ch.apiProvider.ProvideChannelsMaps()
-		chn, ok := channelsMaps.ChannelsInv[channel]
-		if !ok {
-			return nil, fmt.Errorf("channel %q not found", channel)
-		}
-		channel = channelsMaps.Channels[chn].ID
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
 	}
 
 	return &setTopicParams{
@@ -1006,3 +1277,53 @@ func (ch *ConversationsHandler) parseParamsToolSetTopic(request mcp.CallToolRequ
 		topic:   topic,
 	}, nil
 }
+
+func (ch *ConversationsHandler) parseParamsToolMarkRead(request mcp.CallToolRequest) (*markReadParams, error) {
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id must be a string")
+	}
+
+	ts := request.GetString("ts", "")
+	if ts == "" {
+		return nil, errors.New("ts must be a string")
+	}
+	if !strings.Contains(ts, ".") {
+		return nil, errors.New("ts must be a valid timestamp in format 1234567890.123456")
+	}
+
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
+	}
+
+	return &markReadParams{
+		channel: channel,
+		ts:      ts,
+	}, nil
+}
+
+func (ch *ConversationsHandler) parseParamsToolLeaveChannel(request mcp.CallToolRequest) (*leaveChannelParams, error) {
+	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
+	if toolConfig == "" {
+		return nil, errors.New("the conversations_leave tool is disabled unless SLACK_MCP_ADD_MESSAGE_TOOL, which also limits where the MCP can post messages, is set to true, 1, or a comma separated list of channels including this one")
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id must be a string")
+	}
+
+	channel, err := ch.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false))
+	if err != nil {
+		return nil, err
+	}
+
+	if !isChannelAllowed(channel) {
+		return nil, fmt.Errorf("conversations_leave tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	}
+
+	return &leaveChannelParams{
+		channel: channel,
+	}, nil
+}
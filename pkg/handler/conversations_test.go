@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertMessagesFromHistory_PermalinksDisabled(t *testing.T) {
+	// a provider without a client fails any API call, none must be made
+	ch := NewConversationsHandler(&provider.ApiProvider{})
+
+	slackMessages := []slack.Message{
+		{Msg: slack.Msg{User: "U12345678", Text: "hello", Timestamp: "1700000000.000100"}},
+		{Msg: slack.Msg{User: "U12345678", Text: "world", Timestamp: "1700000050.000200", ThreadTimestamp: "1700000000.000100"}},
+	}
+
+	messages := ch.convertMessagesFromHistory(context.Background(), slackMessages, "C12345678", false, false, false, false)
+
+	assert.Len(t, messages, 2)
+	for _, msg := range messages {
+		assert.Empty(t, msg.Permalink)
+	}
+}
+
+func TestMarshalMessagesToCSV_PermalinkColumn(t *testing.T) {
+	messages := []Message{{UserID: "U12345678", Text: "hello", Time: "1700000000.000100", Permalink: "https://example.slack.com/archives/C12345678/p1700000000000100"}}
+
+	res, err := marshalMessagesToCSV(messages, false)
+	assert.NoError(t, err)
+	text := res.Content[0].(mcp.TextContent).Text
+	assert.NotContains(t, text, "Permalink")
+	assert.NotContains(t, text, "https://example.slack.com")
+
+	res, err = marshalMessagesToCSV(messages, true)
+	assert.NoError(t, err)
+	text = res.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, ",Permalink\n")
+	assert.Contains(t, text, ",https://example.slack.com/archives/C12345678/p1700000000000100\n")
+}
+
+func TestConvertMessagesFromHistory_BroadcastReach(t *testing.T) {
+	// the offline provider serves the cached member count without any API call
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+	err := os.WriteFile(cache, []byte(`[{"id": "C12345678", "name": "#general", "memberCount": 120}]`), 0644)
+	assert.NoError(t, err)
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", cache)
+	t.Setenv("SLACK_MCP_USERS_CACHE", filepath.Join(t.TempDir(), "users_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshChannels(context.Background()))
+
+	ch := NewConversationsHandler(p)
+	slackMessages := []slack.Message{
+		{Msg: slack.Msg{User: "U12345678", Text: "<!channel> deploy at 5", Timestamp: "1700000000.000100"}},
+		{Msg: slack.Msg{User: "U12345678", Text: "<!here> standup", Timestamp: "1700000050.000200"}},
+	}
+
+	messages := ch.convertMessagesFromHistory(context.Background(), slackMessages, "C12345678", false, false, true, false)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "@channel (notified ~120) deploy at 5", messages[0].Text)
+	assert.Equal(t, "@here (notified up to ~120) standup", messages[1].Text)
+
+	// without the option the text is processed as before
+	messages = ch.convertMessagesFromHistory(context.Background(), slackMessages, "C12345678", false, false, false, false)
+	assert.Equal(t, "channel deploy at 5", messages[0].Text)
+}
+
+func TestConvertMessagesFromHistory_CollapseQuotedReplies(t *testing.T) {
+	ch := NewConversationsHandler(&provider.ApiProvider{})
+
+	// history is listed newest first
+	slackMessages := []slack.Message{
+		{Msg: slack.Msg{User: "U2", Text: "&gt; Is the build green?\nYes, all green", Timestamp: "1700000050.000200"}},
+		{Msg: slack.Msg{User: "U1", Text: "Is the build green?", Timestamp: "1700000000.000100"}},
+	}
+
+	messages := ch.convertMessagesFromHistory(context.Background(), slackMessages, "C12345678", false, false, false, true)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "Yes, all green", messages[0].Text)
+	assert.Equal(t, "Is the build green?", messages[1].Text)
+
+	messages = ch.convertMessagesFromHistory(context.Background(), slackMessages, "C12345678", false, false, false, false)
+	assert.Contains(t, messages[0].Text, "Is the build green?")
+}
+
+func TestGetUserInfo_NamePref(t *testing.T) {
+	users := map[string]slack.User{
+		"U1": {ID: "U1", Name: "jdoe", RealName: "Jane Doe", Profile: slack.UserProfile{DisplayName: "Jane"}},
+	}
+
+	tests := []struct {
+		pref     string
+		expected string
+	}{
+		{pref: "", expected: "jdoe"},
+		{pref: "display", expected: "Jane"},
+		{pref: "real", expected: "Jane Doe"},
+	}
+	t.Cleanup(func() { text.SetNamePref("") })
+	for _, tt := range tests {
+		text.SetNamePref(tt.pref)
+
+		userName, realName := getUserInfo("U1", users)
+		assert.Equal(t, tt.expected, userName, tt.pref)
+		assert.Equal(t, "Jane Doe", realName, tt.pref)
+	}
+
+	userName, realName := getUserInfo("U2", users)
+	assert.Equal(t, "U2", userName)
+	assert.Equal(t, "U2", realName)
+}
+
+func TestParseParamsToolSearch_RewritesModifiers(t *testing.T) {
+	dir := t.TempDir()
+	users := filepath.Join(dir, "users_cache.json")
+	channels := filepath.Join(dir, "channels_cache.json")
+	assert.NoError(t, os.WriteFile(users, []byte(`[
+		{"id": "U00000001", "name": "jdoe", "profile": {"display_name": "Jane"}},
+		{"id": "U00000002", "name": "janet", "profile": {"display_name": "Janet"}},
+		{"id": "U00000003", "name": "sam.b", "profile": {"display_name": "Sam"}},
+		{"id": "U00000004", "name": "sam.k", "profile": {"display_name": "Sam"}},
+		{"id": "U00000005", "name": "sam.old", "deleted": true, "profile": {"display_name": "Sam"}}
+	]`), 0644))
+	assert.NoError(t, os.WriteFile(channels, []byte(`[
+		{"id": "C12345678", "name": "#general", "memberCount": 3},
+		{"id": "C00000001", "name": "#jdoe", "memberCount": 3},
+		{"id": "D00000001", "name": "@jdoe", "im": true, "user": "U00000001"},
+		{"id": "D00000002", "name": "@janet", "im": true, "user": "U00000002"},
+		{"id": "G00000001", "name": "@mpdm-jdoe--janet-1", "mpim": true, "members": ["U00000001", "U00000002"]}
+	]`), 0644))
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_USERS_CACHE", users)
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", channels)
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshUsers(context.Background()))
+	assert.NoError(t, p.RefreshChannels(context.Background()))
+	ch := NewConversationsHandler(p)
+
+	search := func(query string) (*searchParams, error) {
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]any{"search_query": query}
+		return ch.parseParamsToolSearch(req)
+	}
+	searchIn := func(channel string) (*searchParams, error) {
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]any{"search_query": "deploy", "filter_in_channel": channel}
+		return ch.parseParamsToolSearch(req)
+	}
+
+	params, err := search("deploy from:@Jane in:#general")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy in:#general from:<@U00000001>", params.query)
+
+	// values which aren't names are left alone
+	params, err = search("deploy from:me in:<#C12345678>")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy in:<#C12345678> from:me", params.query)
+
+	_, err = search("from:@nobody")
+	assert.EqualError(t, err, "search modifier from:@nobody: user not found")
+
+	// partial matches are offered, not taken
+	_, err = search("from:@Jan")
+	assert.ErrorContains(t, err, "search modifier from:@Jan: no user has exactly that name, did you mean")
+	assert.ErrorContains(t, err, "@janet (U00000002)")
+
+	// deleted users don't count towards ambiguity
+	_, err = search("from:@Sam")
+	assert.ErrorContains(t, err, "search modifier from:@Sam is ambiguous")
+	assert.ErrorContains(t, err, "@sam.b (U00000003)")
+	assert.ErrorContains(t, err, "@sam.k (U00000004)")
+	assert.NotContains(t, err.Error(), "sam.old")
+
+	// DMs are searched as @user, group DMs have no in: form
+	params, err = searchIn("@janet")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy in:@janet", params.query)
+
+	params, err = searchIn("D00000002")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy in:@janet", params.query)
+
+	_, err = searchIn("G00000001")
+	assert.ErrorContains(t, err, "is a group DM")
+
+	// a bare name matching a channel and a DM isn't guessed
+	_, err = searchIn("jdoe")
+	var ambiguous *provider.AmbiguousChannelError
+	assert.ErrorAs(t, err, &ambiguous)
+
+	params, err = searchIn("#jdoe")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy in:#jdoe", params.query)
+
+	// names differing in case resolve unless case_sensitive is set
+	params, err = search("deploy in:#General")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy in:#general", params.query)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"search_query": "deploy in:#General", "case_sensitive": true}
+	_, err = ch.parseParamsToolSearch(req)
+	var notFound *provider.ChannelNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestConversationsHandler_PermalinkResolve(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+	err := os.WriteFile(cache, []byte(`[{"id": "C12345678", "name": "#general"}]`), 0644)
+	assert.NoError(t, err)
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", cache)
+	t.Setenv("SLACK_MCP_USERS_CACHE", filepath.Join(t.TempDir(), "users_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshChannels(context.Background()))
+
+	ch := NewConversationsHandler(p)
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"permalink": "https://example.slack.com/archives/C12345678/p1700000050000200?thread_ts=1700000000.000100&cid=C12345678",
+	}
+
+	res, err := ch.PermalinkResolveHandler(context.Background(), req)
+	assert.NoError(t, err)
+	text := res.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "https://example.slack.com/,C12345678,#general,1700000050.000200,1700000000.000100")
+
+	req.Params.Arguments = map[string]any{
+		"permalink": "https://example.slack.com/archives/C12345678/p1700000050000200?thread_ts=1700000000.000100&cid=C12345678",
+		"format":    "json",
+	}
+	res, err = ch.PermalinkResolveHandler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"teamURL": "https://example.slack.com/", "channelID": "C12345678", "channelName": "#general", "ts": "1700000050.000200", "threadTs": "1700000000.000100"}]`, res.Content[0].(mcp.TextContent).Text)
+
+	req.Params.Arguments = map[string]any{"permalink": "https://example.slack.com/messages/C12345678"}
+	_, err = ch.PermalinkResolveHandler(context.Background(), req)
+	assert.ErrorIs(t, err, provider.ErrInvalidPermalink)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type Emoji struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	AliasFor string `json:"aliasFor"`
+}
+
+type EmojiHandler struct {
+	apiProvider *provider.ApiProvider
+}
+
+func NewEmojiHandler(apiProvider *provider.ApiProvider) *EmojiHandler {
+	return &EmojiHandler{
+		apiProvider: apiProvider,
+	}
+}
+
+// EmojiListHandler lists the workspace's custom emoji with their image URL,
+// aliases resolved to the image of the emoji they point to.
+func (eh *EmojiHandler) EmojiListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := strings.ToLower(strings.Trim(request.GetString("query", ""), ":"))
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	emoji, err := eh.apiProvider.ListEmoji(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Emoji, 0, len(emoji))
+	for _, e := range emoji {
+		if query != "" && !strings.Contains(e.Name, query) {
+			continue
+		}
+		rows = append(rows, Emoji{Name: e.Name, URL: e.URL, AliasFor: e.AliasFor})
+	}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type File struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Mimetype  string `json:"mimetype"`
+	Size      int    `json:"size"`
+	Permalink string `json:"permalink"`
+	UserID    string `json:"userID"`
+	Created   string `json:"created"`
+}
+
+type FileDownload struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Mimetype string `json:"mimetype"`
+	Size     int    `json:"size"`
+	Path     string `json:"path"`
+	Content  string `json:"content"` // base64, empty when written to Path
+}
+
+type FilesHandler struct {
+	apiProvider *provider.ApiProvider
+}
+
+func NewFilesHandler(apiProvider *provider.ApiProvider) *FilesHandler {
+	return &FilesHandler{
+		apiProvider: apiProvider,
+	}
+}
+
+// FilesListHandler lists files shared in the workspace, optionally only the
+// ones shared in a channel or uploaded by a user.
+func (fh *FilesHandler) FilesListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	limit := request.GetInt("limit", 0)
+	if limit < 0 {
+		return nil, errors.New("limit must not be negative")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+	channel := request.GetString("channel_id", "")
+	if channel != "" {
+		if channel, err = fh.apiProvider.ResolveChannelID(channel, request.GetBool("case_sensitive", false)); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := fh.apiProvider.ListFiles(ctx, provider.FilesOptions{
+		Channel: channel,
+		User:    request.GetString("user", ""),
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalFiles(files, format)
+}
+
+// FilesInfoHandler returns the metadata of a single file.
+func (fh *FilesHandler) FilesInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	fileID := request.GetString("file_id", "")
+	if fileID == "" {
+		return nil, errors.New("file_id must be a string")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fh.apiProvider.GetFileInfo(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalFiles([]provider.FileInfo{file}, format)
+}
+
+// FilesDownloadHandler downloads a file's contents, returned base64 encoded
+// or written to a new file at path inside SLACK_MCP_DOWNLOAD_DIR. Existing
+// files are never overwritten.
+func (fh *FilesHandler) FilesDownloadHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	fileID := request.GetString("file_id", "")
+	if fileID == "" {
+		return nil, errors.New("file_id must be a string")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	path := request.GetString("path", "")
+
+	var (
+		buf  bytes.Buffer
+		info provider.FileInfo
+	)
+	if path == "" {
+		info, err = fh.apiProvider.DownloadFile(ctx, fileID, &buf)
+	} else {
+		path, err = downloadTarget(os.Getenv("SLACK_MCP_DOWNLOAD_DIR"), path)
+		if err == nil {
+			info, err = downloadToPath(ctx, fh.apiProvider, fileID, path)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []FileDownload{{
+		ID:       info.ID,
+		Name:     info.Name,
+		Mimetype: info.Mimetype,
+		Size:     info.Size,
+		Path:     path,
+	}}
+	if path == "" {
+		rows[0].Size = buf.Len()
+		rows[0].Content = base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// downloadTarget returns where a download to path goes: path is relative to
+// dir, the configured download directory, and may neither leave it nor pass
+// through a symlink on the way.
+func downloadTarget(dir, path string) (string, error) {
+	if dir == "" {
+		return "", errors.New("writing downloads to a path is disabled, set SLACK_MCP_DOWNLOAD_DIR to enable it")
+	}
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q must be relative to the download directory", path)
+	}
+	rel := filepath.Clean(path)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside the download directory", path)
+	}
+
+	parent := dir
+	for _, elem := range strings.Split(filepath.Dir(rel), string(filepath.Separator)) {
+		if elem == "." {
+			continue
+		}
+		parent = filepath.Join(parent, elem)
+		fi, err := os.Lstat(parent)
+		if err != nil {
+			return "", fmt.Errorf("failed to check %q: %w", parent, err)
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("path %q passes through the symlink %q", path, parent)
+		}
+	}
+
+	return filepath.Join(dir, rel), nil
+}
+
+// downloadToPath writes the file to path, which must not exist yet. A
+// failed download removes the partial file.
+func downloadToPath(ctx context.Context, p *provider.ApiProvider, fileID, path string) (provider.FileInfo, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return provider.FileInfo{}, fmt.Errorf("failed to create %q: %w", path, err)
+	}
+
+	info, err := p.DownloadFile(ctx, fileID, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return provider.FileInfo{}, err
+	}
+
+	return info, nil
+}
+
+func marshalFiles(files []provider.FileInfo, format string) (*mcp.CallToolResult, error) {
+	rows := make([]File, 0, len(files))
+	for _, f := range files {
+		rows = append(rows, File{
+			ID:        f.ID,
+			Name:      f.Name,
+			Title:     f.Title,
+			Mimetype:  f.Mimetype,
+			Size:      f.Size,
+			Permalink: f.Permalink,
+			UserID:    f.UserID,
+			Created:   f.Created.Format(time.RFC3339),
+		})
+	}
+
+	content, err := marshalRows(&rows, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
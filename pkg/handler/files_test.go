@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadTarget(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "reports"), 0755))
+	assert.NoError(t, os.Symlink(t.TempDir(), filepath.Join(dir, "elsewhere")))
+
+	got, err := downloadTarget(dir, "reports/q3.pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "reports", "q3.pdf"), got)
+
+	got, err = downloadTarget(dir, "./reports/../q3.pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "q3.pdf"), got)
+
+	for _, path := range []string{"/etc/passwd", "../q3.pdf", "reports/../../q3.pdf", ".", "elsewhere/q3.pdf", "missing/q3.pdf"} {
+		_, err := downloadTarget(dir, path)
+		assert.Error(t, err, "path %q", path)
+	}
+
+	_, err = downloadTarget("", "q3.pdf")
+	assert.ErrorContains(t, err, "SLACK_MCP_DOWNLOAD_DIR")
+}
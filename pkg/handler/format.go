@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+// parseFormat returns the output format requested with the format
+// parameter, CSV unless JSON is asked for.
+func parseFormat(request mcp.CallToolRequest) (string, error) {
+	switch format := request.GetString("format", formatCSV); format {
+	case formatCSV, formatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("format must be %q or %q, got %q", formatCSV, formatJSON, format)
+	}
+}
+
+// marshalRows encodes rows, a pointer to a slice of result structs, as CSV
+// with a header row or as a JSON array keyed by their json tags. Both quote
+// embedded commas, quotes and newlines.
+func marshalRows(rows any, format string) (string, error) {
+	if format == formatJSON {
+		b, err := json.Marshal(rows)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal results to JSON: %w", err)
+		}
+		// a nil slice still is an empty result
+		if string(b) == "null" {
+			return "[]", nil
+		}
+		return string(b), nil
+	}
+
+	content, err := gocsv.MarshalString(rows)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal results to CSV: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/stretchr/testify/assert"
+)
+
+// awkwardValues are field values which misalign columns unless quoted.
+var awkwardValues = []string{
+	"Smith, John",
+	`John "JJ" Smith`,
+	"first line\nsecond line",
+	"windows\r\nline end",
+	" leading space",
+	`"`,
+	"",
+}
+
+func TestMarshalRows_CSVRoundTrip(t *testing.T) {
+	for _, v := range awkwardValues {
+		messages := []Message{{UserID: "U1", RealName: v, Text: v + "\n" + v, Time: "1700000000.000100"}}
+
+		content, err := marshalRows(&messages, formatCSV)
+		assert.NoError(t, err)
+
+		// every row must keep the header's column count
+		records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+		assert.NoError(t, err, "value %q", v)
+		if assert.Len(t, records, 2, "value %q", v) {
+			assert.Len(t, records[1], len(records[0]), "value %q", v)
+		}
+
+		var decoded []Message
+		assert.NoError(t, gocsv.UnmarshalString(content, &decoded), "value %q", v)
+		// encoding/csv reads \r\n inside quotes back as \n
+		expected := strings.ReplaceAll(v, "\r\n", "\n")
+		if assert.Len(t, decoded, 1, "value %q", v) {
+			assert.Equal(t, expected, decoded[0].RealName)
+			assert.Equal(t, expected+"\n"+expected, decoded[0].Text)
+			assert.Equal(t, "1700000000.000100", decoded[0].Time)
+		}
+	}
+}
+
+func TestQuoteCSV_RoundTrip(t *testing.T) {
+	for _, policy := range []string{csvQuoteAll, "RealName"} {
+		for _, v := range awkwardValues {
+			users := []UserResolution{{UserID: "U1", RealName: v, DisplayName: v}}
+
+			content, err := marshalRows(&users, formatCSV)
+			assert.NoError(t, err)
+			content, err = quoteCSV(content, policy)
+			assert.NoError(t, err)
+
+			var decoded []UserResolution
+			assert.NoError(t, gocsv.UnmarshalString(content, &decoded), "policy %s, value %q", policy, v)
+			expected := strings.ReplaceAll(v, "\r\n", "\n")
+			if assert.Len(t, decoded, 1) {
+				assert.Equal(t, expected, decoded[0].RealName, "policy %s", policy)
+				assert.Equal(t, expected, decoded[0].DisplayName, "policy %s", policy)
+			}
+		}
+	}
+}
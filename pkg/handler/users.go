@@ -2,26 +2,64 @@ package handler
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
-	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
 )
 
 type UserResolution struct {
+	UserID            string  `json:"userID"`
+	UserName          string  `json:"userName"`
+	RealName          string  `json:"realName"`
+	DisplayName       string  `json:"displayName"`
+	Email             string  `json:"email"`
+	TZ                string  `json:"tz"`
+	TZLabel           string  `json:"tzLabel"`
+	TZOffset          int     `json:"tzOffset"`
+	LocalTime         string  `json:"localTime"`
+	MatchType         string  `json:"matchType"`
+	Score             float64 `json:"score"`
+	IsBot             bool    `json:"isBot"`
+	Deleted           bool    `json:"deleted"`
+	IsRestricted      bool    `json:"isRestricted"`
+	IsUltraRestricted bool    `json:"isUltraRestricted"`
+}
+
+type UserBulkResolution struct {
 	UserID      string `json:"userID"`
 	UserName    string `json:"userName"`
 	RealName    string `json:"realName"`
 	DisplayName string `json:"displayName"`
-	Email       string `json:"email"`
-	MatchType   string `json:"matchType"`
-	IsBot       bool   `json:"isBot"`
+	Status      string `json:"status"`
+}
+
+type UserPresence struct {
+	UserID       string `json:"userID"`
+	UserName     string `json:"userName"`
+	RealName     string `json:"realName"`
+	Presence     string `json:"presence"`
+	StatusText   string `json:"statusText"`
+	StatusEmoji  string `json:"statusEmoji"`
+	TZ           string `json:"tz"`
+	TZOffset     int    `json:"tzOffset"`
+	LocalTime    string `json:"localTime"`
+	OtherMatches string `json:"otherMatches"`
 }
 
+// maxOtherMatches caps the other matching users listed by users_presence.
+const maxOtherMatches = 5
+
 type UsersHandler struct {
 	apiProvider *provider.ApiProvider
 }
@@ -51,164 +89,535 @@ func (uh *UsersHandler) UsersResolveHandler(ctx context.Context, request mcp.Cal
 	}
 
 	searchType := request.GetString("search_type", "auto")
+	excludeDeleted := request.GetBool("exclude_deleted", false)
+	caseSensitive := request.GetBool("case_sensitive", false)
+	quoting := request.GetString("csv_quoting", csvQuoteMinimal)
+	limit := request.GetInt("limit", 0)
+	offset := request.GetInt("offset", 0)
+	if limit < 0 || offset < 0 {
+		return nil, errors.New("limit and offset must not be negative")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := uh.resolveUsers(query, searchType, excludeDeleted, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(matches)
+	page := matches[min(offset, total):]
+	if limit > 0 && limit < len(page) {
+		page = page[:limit]
+	}
+
+	content, err := marshalRows(&page, format)
+	if err != nil {
+		return nil, err
+	}
+	if format == formatCSV {
+		if content, err = quoteCSV(content, quoting); err != nil {
+			return nil, err
+		}
+	}
+
+	result := mcp.NewToolResultText(content)
+	if limit > 0 || offset > 0 {
+		// A separate content block keeps the CSV itself parseable
+		result.Content = append(result.Content, mcp.NewTextContent(pageMetadata(total, offset, len(page))))
+	}
+
+	return result, nil
+}
+
+// pageMetadata describes a page of n results starting at offset out of
+// total, and where the next one starts.
+func pageMetadata(total, offset, n int) string {
+	hasMore := offset+n < total
+	meta := fmt.Sprintf("total: %d, offset: %d, returned: %d, has_more: %t", total, offset, n, hasMore)
+	if hasMore {
+		meta += fmt.Sprintf(", next_offset: %d", offset+n)
+	}
+	return meta
+}
+
+func (uh *UsersHandler) UsersPresenceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := request.GetString("query", "")
+	if query == "" {
+		return nil, errors.New("query must be a non-empty string")
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := uh.resolveUsers(query, "auto", true, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no user matches %q", query)
+	}
+
+	api, err := uh.apiProvider.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	// Presence is looked up for the best match only, one call per user
+	top := matches[0]
+	presence, err := api.GetUserPresenceContext(ctx, top.UserID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := api.GetUserInfoContext(ctx, top.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var others []string
+	for _, m := range matches[1:min(len(matches), maxOtherMatches+1)] {
+		others = append(others, "@"+m.UserName)
+	}
+	if len(matches) > maxOtherMatches+1 {
+		others = append(others, fmt.Sprintf("and %d more", len(matches)-maxOtherMatches-1))
+	}
+
+	result := []UserPresence{{
+		UserID:       user.ID,
+		UserName:     user.Name,
+		RealName:     user.RealName,
+		Presence:     presence.Presence,
+		StatusText:   user.Profile.StatusText,
+		StatusEmoji:  user.Profile.StatusEmoji,
+		TZ:           user.TZ,
+		TZOffset:     user.TZOffset,
+		LocalTime:    userLocalTime(*user, time.Now()),
+		OtherMatches: strings.Join(others, " "),
+	}}
 
+	content, err := marshalRows(&result, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// resolveUsers returns the cached users matching query, best match first.
+// query is a user ID, a mention or a name, with or without "@".
+func (uh *UsersHandler) resolveUsers(query, searchType string, excludeDeleted, caseSensitive bool) ([]UserResolution, error) {
 	// Clean up query
 	query = strings.TrimSpace(query)
 
+	// Unwrap mentions as they appear in message text, e.g. <@U12345678>
+	if strings.HasPrefix(query, "<@") && strings.HasSuffix(query, ">") {
+		query = strings.TrimSuffix(strings.TrimPrefix(query, "<@"), ">")
+	}
+
 	// Remove @ prefix if present
 	if strings.HasPrefix(query, "@") {
 		query = strings.TrimPrefix(query, "@")
 	}
 
+	// No match would pass for an unknown user
+	if err := uh.apiProvider.Available(provider.SubsystemUsers); err != nil {
+		return nil, err
+	}
+
 	// Get all users
 	usersMap := uh.apiProvider.ProvideUsersMap()
 
+	// Short-circuit raw user IDs to a direct lookup
+	if provider.UserIDPattern.MatchString(query) {
+		if user, ok := usersMap.Users[query]; ok {
+			var matches []UserResolution
+			if !excludeDeleted || !user.Deleted {
+				matches = append(matches, newUserResolution(user, "id_exact", 1))
+			}
+			return matches, nil
+		}
+	}
+
 	var matches []UserResolution
-	queryLower := strings.ToLower(query)
 
 	// Search through all users
-	for userID, user := range usersMap.Users {
-
-		var matchType string
-		isMatch := false
+	for _, user := range usersMap.Users {
+		matchType, isMatch, err := matchUser(user, query, searchType, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
 
-		// Check based on search type
-		switch searchType {
-		case "username":
-			if strings.EqualFold(user.Name, query) {
-				isMatch = true
-				matchType = "username_exact"
-			} else if strings.Contains(strings.ToLower(user.Name), queryLower) {
-				isMatch = true
-				matchType = "username_partial"
+		if isMatch {
+			if excludeDeleted && user.Deleted {
+				continue
 			}
 
-		case "display_name":
-			// First try DisplayName
-			if user.Profile.DisplayName != "" {
-				normalizedDisplayName := normalizeString(user.Profile.DisplayName)
-				if strings.EqualFold(normalizedDisplayName, query) {
-					isMatch = true
-					matchType = "display_name_exact"
-				} else if strings.Contains(strings.ToLower(normalizedDisplayName), queryLower) {
-					isMatch = true
-					matchType = "display_name_partial"
-				}
-			}
+			resolution := newUserResolution(user, matchType, matchScore(matchedField(user, matchType), query, caseSensitive))
+			matches = append(matches, resolution)
+		}
+	}
 
-			// Fallback to RealName if DisplayName is empty or no match found
-			// (Slack UI often shows RealName as display name when DisplayName is not set)
-			if !isMatch && user.RealName != "" {
-				normalizedRealName := normalizeString(user.RealName)
-				if strings.EqualFold(normalizedRealName, query) {
-					isMatch = true
-					matchType = "real_name_exact"
-				} else if strings.Contains(strings.ToLower(normalizedRealName), queryLower) {
-					isMatch = true
-					matchType = "real_name_partial"
-				}
+	// Sort matches by priority (exact matches first)
+	return sortUserMatches(matches), nil
+}
+
+const (
+	csvQuoteMinimal = "minimal" // quote fields only when needed, as gocsv does
+	csvQuoteAll     = "all"
+)
+
+// quoteCSV re-encodes content according to policy for strict CSV parsers:
+// "minimal" leaves it as is, "all" quotes every field, anything else is a
+// comma-separated list of the columns to quote, e.g. "email,realName".
+// Columns are matched ignoring case, the header is e.g. RealName.
+func quoteCSV(content, policy string) (string, error) {
+	if policy == "" || policy == csvQuoteMinimal {
+		return content, nil
+	}
+
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("failed to re-read CSV for quoting: %w", err)
+	}
+	if len(records) == 0 {
+		return content, nil
+	}
+
+	quoted := make([]bool, len(records[0]))
+	if policy == csvQuoteAll {
+		for i := range quoted {
+			quoted[i] = true
+		}
+	} else {
+		for _, col := range strings.Split(policy, ",") {
+			col = strings.TrimSpace(col)
+			i := slices.IndexFunc(records[0], func(header string) bool { return strings.EqualFold(header, col) })
+			if i < 0 {
+				return "", fmt.Errorf("csv_quoting: unknown column %q, expected one of %s", col, strings.Join(records[0], ", "))
 			}
+			quoted[i] = true
+		}
+	}
 
-		case "real_name":
-			if user.RealName != "" {
-				normalizedRealName := normalizeString(user.RealName)
-				if strings.EqualFold(normalizedRealName, query) {
-					isMatch = true
-					matchType = "real_name_exact"
-				} else if strings.Contains(strings.ToLower(normalizedRealName), queryLower) {
-					isMatch = true
-					matchType = "real_name_partial"
-				}
+	var b strings.Builder
+	for _, record := range records {
+		for i, field := range record {
+			if i > 0 {
+				b.WriteByte(',')
 			}
+			if quoted[i] || strings.ContainsAny(field, ",\"\r\n") || strings.HasPrefix(field, " ") {
+				b.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `"`)
+			} else {
+				b.WriteString(field)
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String(), nil
+}
+
+// matchUser checks user against query for the given search type and returns
+// the resulting match type. Exact matches ignore case unless caseSensitive is
+// set, partial matches always ignore case.
+func matchUser(user slack.User, query, searchType string, caseSensitive bool) (string, bool, error) {
+	equal := strings.EqualFold
+	if caseSensitive {
+		equal = func(a, b string) bool { return a == b }
+	}
+	queryLower := strings.ToLower(query)
+
+	var matchType string
+	isMatch := false
+
+	// Check based on search type
+	switch searchType {
+	case "username":
+		if equal(user.Name, query) {
+			isMatch = true
+			matchType = "username_exact"
+		} else if strings.Contains(strings.ToLower(user.Name), queryLower) {
+			isMatch = true
+			matchType = "username_partial"
+		}
 
-		case "email":
-			if user.Profile.Email != "" {
-				if strings.EqualFold(user.Profile.Email, query) {
-					isMatch = true
-					matchType = "email_exact"
-				} else if strings.Contains(strings.ToLower(user.Profile.Email), queryLower) {
-					isMatch = true
-					matchType = "email_partial"
-				}
+	case "display_name":
+		// First try DisplayName
+		if user.Profile.DisplayName != "" {
+			normalizedDisplayName := normalizeString(user.Profile.DisplayName)
+			if equal(normalizedDisplayName, query) {
+				isMatch = true
+				matchType = "display_name_exact"
+			} else if strings.Contains(strings.ToLower(normalizedDisplayName), queryLower) {
+				isMatch = true
+				matchType = "display_name_partial"
 			}
+		}
 
-		case "auto":
-			// Try all methods, prioritizing exact matches
-			// Check username exact match
-			if strings.EqualFold(user.Name, query) {
+		// Fallback to RealName if DisplayName is empty or no match found
+		// (Slack UI often shows RealName as display name when DisplayName is not set)
+		if !isMatch && user.RealName != "" {
+			normalizedRealName := normalizeString(user.RealName)
+			if equal(normalizedRealName, query) {
 				isMatch = true
-				matchType = "username_exact"
-			} else if user.Profile.DisplayName != "" && strings.EqualFold(normalizeString(user.Profile.DisplayName), query) {
+				matchType = "real_name_exact"
+			} else if strings.Contains(strings.ToLower(normalizedRealName), queryLower) {
 				isMatch = true
-				matchType = "display_name_exact"
-			} else if user.RealName != "" && strings.EqualFold(normalizeString(user.RealName), query) {
+				matchType = "real_name_partial"
+			}
+		}
+
+	case "real_name":
+		if user.RealName != "" {
+			normalizedRealName := normalizeString(user.RealName)
+			if equal(normalizedRealName, query) {
 				isMatch = true
 				matchType = "real_name_exact"
-			} else if user.Profile.Email != "" && strings.EqualFold(user.Profile.Email, query) {
+			} else if strings.Contains(strings.ToLower(normalizedRealName), queryLower) {
 				isMatch = true
-				matchType = "email_exact"
-			} else {
-				// Try partial matches
-				if strings.Contains(strings.ToLower(user.Name), queryLower) {
-					isMatch = true
-					matchType = "username_partial"
-				} else if user.Profile.DisplayName != "" && strings.Contains(strings.ToLower(normalizeString(user.Profile.DisplayName)), queryLower) {
-					isMatch = true
-					matchType = "display_name_partial"
-				} else if user.RealName != "" && strings.Contains(strings.ToLower(normalizeString(user.RealName)), queryLower) {
-					isMatch = true
-					matchType = "real_name_partial"
-				} else if user.Profile.Email != "" && strings.Contains(strings.ToLower(user.Profile.Email), queryLower) {
-					isMatch = true
-					matchType = "email_partial"
-				}
+				matchType = "real_name_partial"
 			}
+		}
 
-		default:
-			return nil, fmt.Errorf("invalid search_type: %s. Must be one of: username, display_name, real_name, email, auto", searchType)
+	case "email":
+		if user.Profile.Email != "" {
+			if equal(user.Profile.Email, query) {
+				isMatch = true
+				matchType = "email_exact"
+			} else if strings.Contains(strings.ToLower(user.Profile.Email), queryLower) {
+				isMatch = true
+				matchType = "email_partial"
+			}
 		}
 
-		if isMatch {
-			resolution := UserResolution{
-				UserID:      userID,
-				UserName:    user.Name,
-				RealName:    user.RealName,
-				DisplayName: user.Profile.DisplayName,
-				Email:       user.Profile.Email,
-				MatchType:   matchType,
-				IsBot:       user.IsBot,
+	case "auto":
+		// Try all methods, prioritizing exact matches
+		// Check username exact match
+		if equal(user.Name, query) {
+			isMatch = true
+			matchType = "username_exact"
+		} else if user.Profile.DisplayName != "" && equal(normalizeString(user.Profile.DisplayName), query) {
+			isMatch = true
+			matchType = "display_name_exact"
+		} else if user.RealName != "" && equal(normalizeString(user.RealName), query) {
+			isMatch = true
+			matchType = "real_name_exact"
+		} else if user.Profile.Email != "" && equal(user.Profile.Email, query) {
+			isMatch = true
+			matchType = "email_exact"
+		} else {
+			// Try partial matches
+			if strings.Contains(strings.ToLower(user.Name), queryLower) {
+				isMatch = true
+				matchType = "username_partial"
+			} else if user.Profile.DisplayName != "" && strings.Contains(strings.ToLower(normalizeString(user.Profile.DisplayName)), queryLower) {
+				isMatch = true
+				matchType = "display_name_partial"
+			} else if user.RealName != "" && strings.Contains(strings.ToLower(normalizeString(user.RealName)), queryLower) {
+				isMatch = true
+				matchType = "real_name_partial"
+			} else if user.Profile.Email != "" && strings.Contains(strings.ToLower(user.Profile.Email), queryLower) {
+				isMatch = true
+				matchType = "email_partial"
 			}
-			matches = append(matches, resolution)
 		}
+
+	default:
+		return "", false, fmt.Errorf("invalid search_type: %s. Must be one of: username, display_name, real_name, email, auto", searchType)
 	}
+	return matchType, isMatch, nil
+}
 
-	// Sort matches by priority (exact matches first)
-	sortedMatches := sortUserMatches(matches)
+// UsersBulkResolveHandler maps a batch of user IDs to their names using the
+// users cache. IDs missing from the cache are reported with a not_found status.
+func (uh *UsersHandler) UsersBulkResolveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	userIDs, err := parseUserIDs(request.GetString("user_ids", ""))
+	if err != nil {
+		return nil, err
+	}
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
+
+	users := uh.apiProvider.ResolveUsers(userIDs)
+
+	results := make([]UserBulkResolution, 0, len(userIDs))
+	for _, id := range userIDs {
+		user, ok := users[id]
+		if !ok {
+			results = append(results, UserBulkResolution{
+				UserID: id,
+				Status: "not_found",
+			})
+			continue
+		}
+
+		results = append(results, UserBulkResolution{
+			UserID:      id,
+			UserName:    user.Name,
+			RealName:    user.RealName,
+			DisplayName: user.Profile.DisplayName,
+			Status:      "found",
+		})
+	}
 
-	// Convert to CSV
-	csvContent, err := gocsv.MarshalString(&sortedMatches)
+	content, err := marshalRows(&results, format)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal results to CSV: %w", err)
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(content), nil
+}
+
+// parseUserIDs accepts either a JSON array or a comma-separated list of user
+// IDs, optionally written as mentions (<@U12345678>), and returns them
+// de-duplicated in their original order.
+func parseUserIDs(raw string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("user_ids must be a non-empty string")
+	}
+
+	var items []string
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &items); err != nil {
+			return nil, fmt.Errorf("user_ids is not a valid JSON array of strings: %w", err)
+		}
+	} else {
+		items = strings.Split(raw, ",")
+	}
+
+	seen := make(map[string]bool, len(items))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		id := strings.TrimSpace(item)
+		id = strings.TrimSuffix(strings.TrimPrefix(id, "<@"), ">")
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
 	}
 
-	return mcp.NewToolResultText(csvContent), nil
+	if len(ids) == 0 {
+		return nil, errors.New("user_ids must contain at least one user ID")
+	}
+
+	return ids, nil
 }
 
-// sortUserMatches sorts user matches by priority: exact matches first, then partial matches
+// sortUserMatches sorts user matches by priority: active users before deleted
+// ones, and within each group exact matches first, then partial matches
+// ordered by score
 func sortUserMatches(matches []UserResolution) []UserResolution {
 	// Simple priority-based sorting
-	var exactMatches []UserResolution
-	var partialMatches []UserResolution
+	var exactMatches, partialMatches []UserResolution
+	var deletedExactMatches, deletedPartialMatches []UserResolution
 
 	for _, match := range matches {
-		if strings.Contains(match.MatchType, "_exact") {
+		isExact := strings.Contains(match.MatchType, "_exact")
+		switch {
+		case match.Deleted && isExact:
+			deletedExactMatches = append(deletedExactMatches, match)
+		case match.Deleted:
+			deletedPartialMatches = append(deletedPartialMatches, match)
+		case isExact:
 			exactMatches = append(exactMatches, match)
-		} else {
+		default:
 			partialMatches = append(partialMatches, match)
 		}
 	}
 
-	// Combine with exact matches first
+	// Every bucket is ordered, so that results can be paged through
+	for _, bucket := range [][]UserResolution{exactMatches, partialMatches, deletedExactMatches, deletedPartialMatches} {
+		sort.SliceStable(bucket, func(i, j int) bool {
+			if bucket[i].Score != bucket[j].Score {
+				return bucket[i].Score > bucket[j].Score
+			}
+			if bucket[i].UserName != bucket[j].UserName {
+				return bucket[i].UserName < bucket[j].UserName
+			}
+			return bucket[i].UserID < bucket[j].UserID
+		})
+	}
+
+	// Combine with exact matches first, deleted users last
 	result := append(exactMatches, partialMatches...)
+	result = append(result, deletedExactMatches...)
+	result = append(result, deletedPartialMatches...)
 	return result
 }
+
+func newUserResolution(user slack.User, matchType string, score float64) UserResolution {
+	return UserResolution{
+		UserID:            user.ID,
+		UserName:          user.Name,
+		RealName:          user.RealName,
+		DisplayName:       user.Profile.DisplayName,
+		Email:             user.Profile.Email,
+		TZ:                user.TZ,
+		TZLabel:           user.TZLabel,
+		TZOffset:          user.TZOffset,
+		LocalTime:         userLocalTime(user, time.Now()),
+		MatchType:         matchType,
+		Score:             score,
+		IsBot:             user.IsBot,
+		Deleted:           user.Deleted,
+		IsRestricted:      user.IsRestricted,
+		IsUltraRestricted: user.IsUltraRestricted,
+	}
+}
+
+// userLocalTime returns what time it is for user at now, e.g. to tell
+// whether it is a good time to ping them, or "" when their timezone is
+// unknown. See provider.UserLocation for how the zone is chosen.
+func userLocalTime(user slack.User, now time.Time) string {
+	loc, err := provider.UserLocation(user)
+	if err != nil {
+		return ""
+	}
+	return now.In(loc).Format("2006-01-02 15:04 -0700")
+}
+
+// matchedField returns the user field the match type was computed against
+func matchedField(user slack.User, matchType string) string {
+	switch {
+	case strings.HasPrefix(matchType, "username"):
+		return user.Name
+	case strings.HasPrefix(matchType, "display_name"):
+		return normalizeString(user.Profile.DisplayName)
+	case strings.HasPrefix(matchType, "real_name"):
+		return normalizeString(user.RealName)
+	case strings.HasPrefix(matchType, "email"):
+		return user.Profile.Email
+	}
+	return ""
+}
+
+// matchScore rates how well query matches field between 0 and 1, where 1 is
+// an exact match; when caseSensitive, a field differing in case isn't one.
+// Partial matches score by how much of the field the query covers, with a
+// bonus when the field starts with the query.
+func matchScore(field, query string, caseSensitive bool) float64 {
+	if field == query || (!caseSensitive && strings.EqualFold(field, query)) {
+		return 1
+	}
+
+	fieldLower := strings.ToLower(field)
+	queryLower := strings.ToLower(query)
+
+	idx := strings.Index(fieldLower, queryLower)
+	if idx < 0 || fieldLower == "" {
+		return 0
+	}
+
+	score := 0.8 * float64(utf8.RuneCountInString(queryLower)) / float64(utf8.RuneCountInString(fieldLower))
+	if idx == 0 {
+		score += 0.15
+	}
+	return score
+}
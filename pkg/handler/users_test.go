@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchScore(t *testing.T) {
+	assert.Equal(t, 1.0, matchScore("Alice", "alice", false))
+	assert.Equal(t, 0.0, matchScore("bob", "al", false))
+	assert.Equal(t, 0.0, matchScore("", "al", false))
+
+	// a prefix match beats the same query found mid-word
+	assert.Greater(t, matchScore("alex", "al", false), matchScore("sal", "al", false))
+	// a query covering more of the field scores higher
+	assert.Greater(t, matchScore("alf", "al", false), matchScore("alexander", "al", false))
+	// partial matches always stay below an exact match
+	assert.Less(t, matchScore("al1", "al", false), 1.0)
+}
+
+func TestSortUserMatches_PartialByScore(t *testing.T) {
+	matches := []UserResolution{
+		{UserName: "sally", MatchType: "username_partial", Score: matchScore("sally", "al", false)},
+		{UserName: "al", MatchType: "username_exact", Score: 1},
+		{UserName: "alexander", MatchType: "username_partial", Score: matchScore("alexander", "al", false)},
+		{UserName: "alf", MatchType: "username_partial", Score: matchScore("alf", "al", false)},
+	}
+
+	sorted := sortUserMatches(matches)
+
+	var names []string
+	for _, m := range sorted {
+		names = append(names, m.UserName)
+	}
+	assert.Equal(t, []string{"al", "alf", "alexander", "sally"}, names)
+}
+
+func TestParseUserIDs(t *testing.T) {
+	ids, err := parseUserIDs("U1, <@U2>,U1,,U3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U1", "U2", "U3"}, ids)
+
+	ids, err = parseUserIDs(`["U1", "U2"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U1", "U2"}, ids)
+
+	_, err = parseUserIDs(`["U1",`)
+	assert.Error(t, err)
+
+	_, err = parseUserIDs(" , ")
+	assert.Error(t, err)
+}
+
+func TestSortUserMatches_DeletedLast(t *testing.T) {
+	matches := []UserResolution{
+		{UserName: "al-old", MatchType: "username_exact", Score: 1, Deleted: true},
+		{UserName: "alexander", MatchType: "username_partial", Score: matchScore("alexander", "al", false)},
+		{UserName: "alf-old", MatchType: "username_partial", Score: matchScore("alf-old", "al", false), Deleted: true},
+		{UserName: "al", MatchType: "username_exact", Score: 1},
+	}
+
+	sorted := sortUserMatches(matches)
+
+	var names []string
+	for _, m := range sorted {
+		names = append(names, m.UserName)
+	}
+	assert.Equal(t, []string{"al", "alexander", "al-old", "alf-old"}, names)
+}
+
+func TestMatchUser_CaseSensitivity(t *testing.T) {
+	user := slack.User{ID: "U12345678", Name: "alice", RealName: "Alice Smith"}
+
+	matchType, ok, err := matchUser(user, "Alice", "username", false)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "username_exact", matchType)
+
+	// differing case is no longer exact, but still a partial match
+	matchType, ok, err = matchUser(user, "Alice", "username", true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "username_partial", matchType)
+	assert.Less(t, matchScore(user.Name, "Alice", true), 1.0)
+
+	matchType, ok, err = matchUser(user, "alice", "username", true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "username_exact", matchType)
+	assert.Equal(t, 1.0, matchScore(user.Name, "alice", true))
+
+	matchType, _, err = matchUser(user, "alice smith", "real_name", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "real_name_partial", matchType)
+
+	_, _, err = matchUser(user, "alice", "nickname", false)
+	assert.Error(t, err)
+}
+
+func TestQuoteCSV(t *testing.T) {
+	content := "UserID,RealName,Email\nU1,\"Doe, Jane\",jane@example.com\nU2,Bob,\n"
+
+	got, err := quoteCSV(content, "minimal")
+	assert.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	got, err = quoteCSV(content, "all")
+	assert.NoError(t, err)
+	assert.Equal(t, "\"UserID\",\"RealName\",\"Email\"\n\"U1\",\"Doe, Jane\",\"jane@example.com\"\n\"U2\",\"Bob\",\"\"\n", got)
+
+	// listed columns are always quoted, the others only when needed; the
+	// headers are matched ignoring case
+	got, err = quoteCSV(content, "email")
+	assert.NoError(t, err)
+	assert.Equal(t, "UserID,RealName,\"Email\"\nU1,\"Doe, Jane\",\"jane@example.com\"\nU2,Bob,\"\"\n", got)
+
+	_, err = quoteCSV(content, "phone")
+	assert.Error(t, err)
+}
+
+func TestUsersPresenceHandler_Resolution(t *testing.T) {
+	// the offline provider resolves from the users cache and fails API calls
+	cache := filepath.Join(t.TempDir(), "users_cache.json")
+	err := os.WriteFile(cache, []byte(`[
+		{"id": "U00000001", "name": "alice", "real_name": "Alice Smith"},
+		{"id": "U00000002", "name": "alicia", "real_name": "Alicia Jones"}
+	]`), 0644)
+	assert.NoError(t, err)
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_USERS_CACHE", cache)
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", filepath.Join(t.TempDir(), "channels_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshUsers(context.Background()))
+	uh := NewUsersHandler(p)
+
+	matches, err := uh.resolveUsers("@ali", "auto", true, false)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 2)
+
+	matches, err = uh.resolveUsers("<@U00000002>", "auto", true, false)
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "alicia", matches[0].UserName)
+	}
+
+	request := func(query string) mcp.CallToolRequest {
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]any{"query": query}
+		return req
+	}
+
+	_, err = uh.UsersPresenceHandler(context.Background(), request("bob"))
+	assert.EqualError(t, err, `no user matches "bob"`)
+
+	// a match goes on to the API
+	_, err = uh.UsersPresenceHandler(context.Background(), request("alice"))
+	assert.True(t, errors.Is(err, provider.ErrOffline), "expected ErrOffline, got %v", err)
+}
+
+func TestUserLocalTime(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		user     slack.User
+		expected string
+	}{
+		{name: "zone with DST", user: slack.User{TZ: "Europe/Berlin", TZOffset: 3600}, expected: "2024-07-01 14:00 +0200"},
+		{name: "unknown zone falls back to the offset", user: slack.User{TZ: "Mars/Olympus", TZOffset: -5 * 3600}, expected: "2024-07-01 07:00 -0500"},
+		{name: "offset without a zone name", user: slack.User{TZLabel: "Gulf Standard Time", TZOffset: 4 * 3600}, expected: "2024-07-01 16:00 +0400"},
+		{name: "no timezone", user: slack.User{}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, userLocalTime(tt.user, now))
+		})
+	}
+}
+
+func TestUsersResolveHandler_Paging(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "users_cache.json")
+	err := os.WriteFile(cache, []byte(`[
+		{"id": "U00000003", "name": "sam3"},
+		{"id": "U00000001", "name": "sam1"},
+		{"id": "U00000002", "name": "sam2"}
+	]`), 0644)
+	assert.NoError(t, err)
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_USERS_CACHE", cache)
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", filepath.Join(t.TempDir(), "channels_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshUsers(context.Background()))
+	uh := NewUsersHandler(p)
+
+	resolve := func(args map[string]any) []string {
+		var req mcp.CallToolRequest
+		req.Params.Arguments = args
+		res, err := uh.UsersResolveHandler(context.Background(), req)
+		assert.NoError(t, err)
+
+		var texts []string
+		for _, c := range res.Content {
+			texts = append(texts, c.(mcp.TextContent).Text)
+		}
+		return texts
+	}
+
+	first := resolve(map[string]any{"query": "sam", "limit": 2})
+	if assert.Len(t, first, 2) {
+		assert.Contains(t, first[0], "U00000001")
+		assert.Contains(t, first[0], "U00000002")
+		assert.NotContains(t, first[0], "U00000003")
+		assert.Equal(t, "total: 3, offset: 0, returned: 2, has_more: true, next_offset: 2", first[1])
+	}
+
+	second := resolve(map[string]any{"query": "sam", "limit": 2, "offset": 2})
+	if assert.Len(t, second, 2) {
+		assert.Contains(t, second[0], "U00000003")
+		assert.Equal(t, "total: 3, offset: 2, returned: 1, has_more: false", second[1])
+	}
+
+	// without paging the output is the CSV alone
+	assert.Len(t, resolve(map[string]any{"query": "sam"}), 1)
+}
+
+func TestUsersResolveHandler_Formats(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "users_cache.json")
+	err := os.WriteFile(cache, []byte(`[{"id": "U00000001", "name": "jsmith", "real_name": "Smith, John \"JJ\""}]`), 0644)
+	assert.NoError(t, err)
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_USERS_CACHE", cache)
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", filepath.Join(t.TempDir(), "channels_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshUsers(context.Background()))
+	uh := NewUsersHandler(p)
+
+	resolve := func(format string) string {
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]any{"query": "jsmith", "format": format}
+		res, err := uh.UsersResolveHandler(context.Background(), req)
+		assert.NoError(t, err)
+		return res.Content[0].(mcp.TextContent).Text
+	}
+
+	records, err := csv.NewReader(strings.NewReader(resolve("csv"))).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		realName := slices.Index(records[0], "RealName")
+		assert.Equal(t, `Smith, John "JJ"`, records[1][realName])
+	}
+
+	var users []UserResolution
+	assert.NoError(t, json.Unmarshal([]byte(resolve("json")), &users))
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, `Smith, John "JJ"`, users[0].RealName)
+	}
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"query": "jsmith", "format": "xml"}
+	_, err = uh.UsersResolveHandler(context.Background(), req)
+	assert.Error(t, err)
+
+	// the documented csv_quoting example names the columns in camelCase
+	req.Params.Arguments = map[string]any{"query": "jsmith", "csv_quoting": "email,realName"}
+	res, err := uh.UsersResolveHandler(context.Background(), req)
+	if assert.NoError(t, err) {
+		lines := strings.Split(res.Content[0].(mcp.TextContent).Text, "\n")
+		assert.True(t, strings.HasPrefix(lines[0], "UserID,"), "unexpected header %q", lines[0])
+		assert.Contains(t, lines[1], `,"Smith, John ""JJ""",`)
+		assert.Contains(t, lines[1], `,"",`, "expected the empty email to be quoted")
+	}
+}
+
+func TestUsersResolveHandler_UserID(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "users_cache.json")
+	err := os.WriteFile(cache, []byte(`[
+		{"id": "U00000001", "name": "sam1"},
+		{"id": "U00000002", "name": "sam2", "deleted": true}
+	]`), 0644)
+	assert.NoError(t, err)
+
+	t.Setenv("SLACK_MCP_OFFLINE", "true")
+	t.Setenv("SLACK_MCP_USERS_CACHE", cache)
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", filepath.Join(t.TempDir(), "channels_cache.json"))
+
+	p, err := provider.New()
+	assert.NoError(t, err)
+	assert.NoError(t, p.RefreshUsers(context.Background()))
+	uh := NewUsersHandler(p)
+
+	resolve := func(args map[string]any) []UserResolution {
+		var req mcp.CallToolRequest
+		req.Params.Arguments = args
+		args["format"] = "json"
+		res, err := uh.UsersResolveHandler(context.Background(), req)
+		assert.NoError(t, err)
+
+		var users []UserResolution
+		assert.NoError(t, json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &users))
+		return users
+	}
+
+	for _, q := range []string{"U00000001", "<@U00000001>", "@U00000001"} {
+		users := resolve(map[string]any{"query": q})
+		if assert.Len(t, users, 1, q) {
+			assert.Equal(t, "U00000001", users[0].UserID)
+			assert.Equal(t, "id_exact", users[0].MatchType)
+		}
+	}
+
+	// an ID missing from the cache falls through to the name search
+	assert.Empty(t, resolve(map[string]any{"query": "U00000009"}))
+
+	assert.Len(t, resolve(map[string]any{"query": "U00000002"}), 1)
+	assert.Empty(t, resolve(map[string]any{"query": "U00000002", "exclude_deleted": true}))
+}
+
+func TestMarshalRows_EmptyJSON(t *testing.T) {
+	var rows []UserResolution
+	content, err := marshalRows(&rows, formatJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", content)
+}
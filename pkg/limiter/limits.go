@@ -1,26 +1,44 @@
 package limiter
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
-type tier struct {
+type Tier struct {
 	// once every
 	t time.Duration
 	// burst
 	b int
 }
 
-func (t tier) Limiter() *rate.Limiter {
+func (t Tier) Limiter() *rate.Limiter {
 	return rate.NewLimiter(rate.Every(t.t), t.b)
 }
 
 var (
-	// tier1 = tier{t: 1 * time.Minute, b: 2}
-	// tier2 = tier{t: 3 * time.Second, b: 3}
-	Tier2boost = tier{t: 300 * time.Millisecond, b: 5}
-	Tier3      = tier{t: 1200 * time.Millisecond, b: 4}
-	// tier4      = tier{t: 60 * time.Millisecond, b: 5}
+	// tier1 = Tier{t: 1 * time.Minute, b: 2}
+	Tier2      = Tier{t: 3 * time.Second, b: 3}
+	Tier2boost = Tier{t: 300 * time.Millisecond, b: 5}
+	Tier3      = Tier{t: 1200 * time.Millisecond, b: 4}
+	Tier4      = Tier{t: 60 * time.Millisecond, b: 5}
 )
+
+// ParseTier returns the tier with the given name, one of tier2, tier2boost,
+// tier3 or tier4.
+func ParseTier(name string) (Tier, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "tier2":
+		return Tier2, nil
+	case "tier2boost":
+		return Tier2boost, nil
+	case "tier3":
+		return Tier3, nil
+	case "tier4":
+		return Tier4, nil
+	}
+	return Tier{}, fmt.Errorf("unknown rate limit tier %q, must be one of tier2, tier2boost, tier3, tier4", name)
+}
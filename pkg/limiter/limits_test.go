@@ -0,0 +1,25 @@
+package limiter
+
+import "testing"
+
+func TestParseTier(t *testing.T) {
+	tests := map[string]Tier{
+		"tier2":      Tier2,
+		"tier2boost": Tier2boost,
+		"TIER3":      Tier3,
+		" tier4 ":    Tier4,
+	}
+	for name, expected := range tests {
+		got, err := ParseTier(name)
+		if err != nil {
+			t.Errorf("ParseTier(%q) unexpected error: %v", name, err)
+		}
+		if got != expected {
+			t.Errorf("ParseTier(%q) = %+v, expected %+v", name, got, expected)
+		}
+	}
+
+	if _, err := ParseTier("tier9"); err == nil {
+		t.Error("expected an error for an unknown tier")
+	}
+}
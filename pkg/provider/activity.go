@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// activityTTL is how long the latest message of a channel is reused before
+// the channel is probed again.
+const activityTTL = 5 * time.Minute
+
+// activityNow is the clock the activity cache ages against.
+var activityNow = time.Now
+
+type activityEntry struct {
+	latestTS string // empty for a channel without messages
+	probedAt time.Time
+}
+
+// ChannelActivity is a channel with the timestamp of its latest message,
+// empty when it has none.
+type ChannelActivity struct {
+	Channel
+	LatestTS string
+}
+
+// GetRecentlyActiveChannels returns channels ordered by their latest
+// message, most recent first. The latest message is looked up with a
+// conversations.history call of limit 1 and reused for activityTTL. At most
+// maxProbes channels are probed per call, the ones with the most members
+// first, at the configured rate limit tier. Channels left unprobed, or that
+// cannot be read, e.g. by a bot not in them, are omitted.
+func (ap *ApiProvider) GetRecentlyActiveChannels(ctx context.Context, channels []Channel, maxProbes int) ([]ChannelActivity, error) {
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res   []ChannelActivity
+		stale []Channel
+	)
+	now := activityNow()
+	ap.activityMu.Lock()
+	for _, c := range channels {
+		if e, ok := ap.activity[c.ID]; ok && now.Sub(e.probedAt) < activityTTL {
+			res = append(res, ChannelActivity{Channel: c, LatestTS: e.latestTS})
+		} else {
+			stale = append(stale, c)
+		}
+	}
+	ap.activityMu.Unlock()
+
+	slices.SortStableFunc(stale, func(a, b Channel) int {
+		return cmp.Compare(b.MemberCount, a.MemberCount)
+	})
+	stale = stale[:min(len(stale), max(maxProbes, 0))]
+
+	lim := ap.rateTier.Limiter()
+	for i, c := range stale {
+		if i > 0 {
+			if err := lim.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		history, err := client.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: c.ID,
+			Limit:     1,
+		})
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			log.Printf("Failed to fetch the latest message of %s: %v", c.ID, err)
+			continue
+		}
+
+		var latestTS string
+		if len(history.Messages) > 0 {
+			latestTS = history.Messages[0].Timestamp
+		}
+
+		ap.activityMu.Lock()
+		if ap.activity == nil {
+			ap.activity = make(map[string]activityEntry)
+		}
+		ap.activity[c.ID] = activityEntry{latestTS: latestTS, probedAt: activityNow()}
+		ap.activityMu.Unlock()
+
+		res = append(res, ChannelActivity{Channel: c, LatestTS: latestTS})
+	}
+
+	slices.SortFunc(res, func(a, b ChannelActivity) int {
+		return cmp.Or(cmp.Compare(messageTime(b.LatestTS), messageTime(a.LatestTS)), cmp.Compare(a.ID, b.ID))
+	})
+
+	return res, nil
+}
+
+// messageTime returns the seconds of a message timestamp, 0 when there is
+// none.
+func messageTime(ts string) float64 {
+	secs, _ := strconv.ParseFloat(ts, 64)
+	return secs
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestGetRecentlyActiveChannels(t *testing.T) {
+	latest := map[string]string{
+		"C1": `{"ok": true, "messages": [{"type": "message", "ts": "1700000100.000100"}]}`,
+		"C2": `{"ok": true, "messages": [{"type": "message", "ts": "1700000300.000300"}]}`,
+		"C3": `{"ok": true, "messages": []}`,
+		"C4": `{"ok": false, "error": "not_in_channel"}`,
+		"C5": `{"ok": true, "messages": [{"type": "message", "ts": "1700000900.000900"}]}`,
+	}
+	probed := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("limit") != "1" {
+			t.Errorf("expected only the latest message to be fetched, got limit %q", r.Form.Get("limit"))
+		}
+		id := r.Form.Get("channel")
+		probed[id]++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(latest[id]))
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1700001000, 0)
+	activityNow = func() time.Time { return now }
+	t.Cleanup(func() { activityNow = time.Now })
+
+	ap, _ := newTestProvider(0)
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	channels := []Channel{
+		{ID: "C1", MemberCount: 50},
+		{ID: "C2", MemberCount: 40},
+		{ID: "C3", MemberCount: 30},
+		{ID: "C4", MemberCount: 20},
+		{ID: "C5", MemberCount: 10},
+	}
+	ids := func(res []ChannelActivity) []string {
+		var ids []string
+		for _, c := range res {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	// the largest channels are probed first, C5 is beyond the cap
+	res, err := ap.GetRecentlyActiveChannels(context.Background(), channels, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ids(res); len(got) != 3 || got[0] != "C2" || got[1] != "C1" || got[2] != "C3" {
+		t.Errorf("expected C2, C1 and the empty C3, got %v", got)
+	}
+	if probed["C5"] != 0 {
+		t.Errorf("expected C5 not to be probed, got %v", probed)
+	}
+
+	// cached channels cost no probe, leaving room for C5
+	res, err = ap.GetRecentlyActiveChannels(context.Background(), channels, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ids(res); len(got) != 4 || got[0] != "C5" {
+		t.Errorf("expected C5 to come first, got %v", got)
+	}
+	if probed["C1"] != 1 || probed["C2"] != 1 || probed["C4"] != 2 {
+		t.Errorf("expected only the unreadable C4 and C5 to be probed again, got %v", probed)
+	}
+
+	now = now.Add(activityTTL)
+	if _, err := ap.GetRecentlyActiveChannels(context.Background(), channels, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if probed["C1"] != 2 {
+		t.Errorf("expected an expired entry to be probed again, got %v", probed)
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/slack-go/slack"
+)
+
+var ErrScopeMissing = errors.New("token is missing a required scope")
+
+// AdminSearchOptions narrow an AdminSearchChannels query.
+type AdminSearchOptions struct {
+	ChannelTypes []string // "public", "private", "archived", ... empty means all
+	TeamIDs      []string // workspaces to search, empty means the whole org
+	Limit        int      // maximum number of channels returned, 0 means 100
+}
+
+// AdminSearchChannels finds channels across an Enterprise Grid organisation
+// with admin.conversations.search, including channels the user is not in.
+// It returns ErrScopeMissing when the token lacks the admin scope.
+func (ap *ApiProvider) AdminSearchChannels(ctx context.Context, query string, opts AdminSearchOptions) ([]Channel, error) {
+	client, err := ap.ProvideEnterprise()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var (
+		channels []Channel
+		cursor   string
+	)
+	for len(channels) < limit {
+		found, next, err := client.AdminConversationsSearch(ctx, edge.AdminSearchParams{
+			Query:        query,
+			ChannelTypes: opts.ChannelTypes,
+			TeamIDs:      opts.TeamIDs,
+			Limit:        min(limit-len(channels), 20),
+			Cursor:       cursor,
+		})
+		if err != nil {
+			return nil, scopeError("admin.conversations.search", err)
+		}
+
+		for _, c := range found {
+			channels = append(channels, mapChannel(rawChannel{
+				Channel: Channel{
+					ID:          c.ID,
+					Name:        c.Name,
+					Purpose:     c.Purpose,
+					MemberCount: c.MemberCount,
+					IsPrivate:   c.IsPrivate,
+					IsArchived:  c.IsArchived,
+					Created:     c.Created,
+					Creator:     c.CreatorID,
+				},
+				NameNormalized: c.Name,
+			}, nil))
+
+		}
+
+		if next == "" || len(found) == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	if len(channels) > limit {
+		channels = channels[:limit]
+	}
+
+	return channels, nil
+}
+
+// scopeError maps the errors Slack returns when the token may not call
+// method to ErrScopeMissing, for both the edge and the generic client.
+func scopeError(method string, err error) error {
+	var code string
+
+	var apiErr *edge.APIError
+	var slackErr slack.SlackErrorResponse
+	switch {
+	case errors.As(err, &apiErr):
+		code = apiErr.Err
+	case errors.As(err, &slackErr):
+		code = slackErr.Err
+	}
+
+	switch code {
+	case "missing_scope", "not_allowed_token_type", "not_an_admin", "feature_not_enabled":
+		return fmt.Errorf("%w: %s: %s", ErrScopeMissing, method, code)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	slack2 "github.com/rusq/slack"
+)
+
+func TestAdminSearchChannels(t *testing.T) {
+	ap, _ := newTestProvider(0, withEdgeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/admin.conversations.search" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("query") != "incident" || r.Form.Get("search_channel_types") != "public,private" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"conversations": [
+				{"id": "C1", "name": "incident-42", "purpose": "Outage on &lt;db&gt;", "member_count": 12, "created": 1700000000, "creator_id": "U1"},
+				{"id": "C2", "name": "incident-review", "purpose": "", "member_count": 3, "is_private": true, "is_archived": true}
+			],
+			"next_cursor": ""
+		}`))
+	}), slack2.AuthTestResponse{TeamID: "T1"}))
+
+	channels, err := ap.AdminSearchChannels(context.Background(), "incident", AdminSearchOptions{
+		ChannelTypes: []string{"public", "private"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("expected 2 channels, got %+v", channels)
+	}
+
+	c := channels[0]
+	if c.ID != "C1" || c.Name != "#incident-42" || c.MemberCount != 12 || c.Created != 1700000000 || c.Creator != "U1" {
+		t.Errorf("unexpected channel %+v", c)
+	}
+	if c.Purpose != "Outage on <db>" {
+		t.Errorf("expected the purpose to be processed, got %q", c.Purpose)
+	}
+	if !channels[1].IsPrivate || !channels[1].IsArchived {
+		t.Errorf("expected a private archived channel, got %+v", channels[1])
+	}
+}
+
+func TestAdminSearchChannels_ScopeMissing(t *testing.T) {
+	ap, _ := newTestProvider(0, withEdgeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope", "needed": "admin.conversations:read"}`))
+	}), slack2.AuthTestResponse{TeamID: "T1"}))
+
+	_, err := ap.AdminSearchChannels(context.Background(), "incident", AdminSearchOptions{})
+	if !errors.Is(err, ErrScopeMissing) {
+		t.Fatalf("expected ErrScopeMissing, got %v", err)
+	}
+}
@@ -4,18 +4,25 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"maps"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 
 	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	"github.com/korotovsky/slack-mcp-server/pkg/transport"
 	slack2 "github.com/rusq/slack"
 	"github.com/rusq/slackdump/v3/auth"
@@ -26,17 +33,35 @@ var defaultUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537
 var AllChanTypes = []string{"mpim", "im", "public_channel", "private_channel"}
 var PubChanType = "public_channel"
 
-// getCacheDir returns the appropriate cache directory for slack-mcp-server
+// defaultBootCooldown is how long a failed auth.test is remembered, see
+// SLACK_MCP_BOOT_COOLDOWN.
+const defaultBootCooldown = 30 * time.Second
+
+// ArchivedChanType may be passed to GetChannels along with the channel types
+// to include archived channels, which are left out by default.
+var ArchivedChanType = "archived"
+
+// MemberChanType may be passed to GetChannels along with the channel types
+// to list only the channels the user, or bot, is a member of.
+var MemberChanType = "member"
+
+// getCacheDir returns the appropriate cache directory for slack-mcp-server.
+// SLACK_MCP_CACHE_DIR, when set, takes precedence over the user cache dir.
 func getCacheDir() string {
-	cacheDir, err := os.UserCacheDir()
-	if err != nil {
-		// Fallback to current directory if we can't get user cache dir
-		return "."
+	dir := os.Getenv("SLACK_MCP_CACHE_DIR")
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			// Fallback to current directory if we can't get user cache dir
+			log.Printf("Failed to resolve user cache dir: %v; using current directory", err)
+			return "."
+		}
+		dir = filepath.Join(cacheDir, "slack-mcp-server")
 	}
 
-	dir := filepath.Join(cacheDir, "slack-mcp-server")
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		// Fallback to current directory if we can't create cache dir
+		log.Printf("Failed to create cache dir %q: %v; using current directory", dir, err)
 		return "."
 	}
 	return dir
@@ -53,6 +78,8 @@ func normalizeString(s string) string {
 	}, s)
 }
 
+var ErrNoCredentials = errors.New("authentication required: Either SLACK_MCP_XOXC_TOKEN and SLACK_MCP_XOXD_TOKEN (session-based, recommended), SLACK_MCP_XOXP_TOKEN (User OAuth), or SLACK_MCP_XOXB_TOKEN (Bot) environment variables must be provided")
+
 type UsersCache struct {
 	Users               map[string]slack.User `json:"users"`
 	UsersInv            map[string]string     `json:"users_inv"`
@@ -67,7 +94,8 @@ type ChannelsCache struct {
 }
 
 type ApiProvider struct {
-	boot func(ap *ApiProvider) *slack.Client
+	boot  func(ap *ApiProvider) (*slack.Client, error)
+	token string // the Slack token boot authenticates with, see teamCachePath
 
 	authProvider *auth.ValueAuth
 	authResponse *slack2.AuthTestResponse
@@ -75,18 +103,42 @@ type ApiProvider struct {
 	clientGeneric    *slack.Client
 	clientEnterprise *edge.Client
 
+	clientMu     sync.RWMutex // guards the clients and the credentials: boot, token, authProvider, authResponse, isBotToken and scopes
+	bootErr      error        // last boot failure, returned until the cooldown elapses
+	bootFailedAt time.Time    // when bootErr happened
+	tokenMu      sync.Mutex   // serializes RefreshToken
+
+	usersMu             sync.RWMutex
 	users               map[string]slack.User
 	usersInv            map[string]string
 	usersDisplayNameInv map[string]string
 	usersRealNameInv    map[string]string
 	usersEmailInv       map[string]string
 	usersCache          string
+	usersCacheByTeam    bool  // usersCache is keyed by team, see teamCachePath
+	usersErr            error // failure of the last users refresh, see Health
 
-	channels      map[string]Channel
-	channelsInv   map[string]string
-	channelsCache string
+	channelsMu          sync.RWMutex
+	channels            map[string]Channel
+	channelsInv         map[string]string
+	channelsCache       string
+	channelsCacheByTeam bool  // channelsCache is keyed by team, see teamCachePath
+	channelsErr         error // failure of the last channels refresh, see Health
 
-	isBotToken bool // true if using xoxb token (bot has limited access)
+	emojiMu sync.RWMutex
+	emoji   map[string]string // custom emoji name to image URL or "alias:<name>"
+
+	activityMu sync.Mutex
+	activity   map[string]activityEntry // latest message per channel, see GetRecentlyActiveChannels
+
+	gridMu    sync.Mutex
+	gridTeams []GridTeam // workspaces of the Enterprise Grid, see GetGridTeams
+
+	rateTier limiter.Tier // limiter tier used when paging through channels
+
+	isBotToken bool            // true if using xoxb token (bot has limited access), see IsBotToken
+	scopes     map[string]bool // OAuth scopes of the token, nil when unknown, see HasScope
+	offline    bool            // true if serving from the cache files only, see SLACK_MCP_OFFLINE
 }
 
 type Channel struct {
@@ -98,31 +150,100 @@ type Channel struct {
 	IsMpIM      bool     `json:"mpim"`
 	IsIM        bool     `json:"im"`
 	IsPrivate   bool     `json:"private"`
+	IsArchived  bool     `json:"archived"`
+	IsMember    bool     `json:"member"`            // true if the user, or bot, is in the channel
 	User        string   `json:"user,omitempty"`    // User ID for IM channels
 	Members     []string `json:"members,omitempty"` // Member IDs for the channel
+	Created     int64    `json:"created,omitempty"` // Unix time the channel was created
+	Creator     string   `json:"creator,omitempty"` // User ID of the channel creator
+
+	// Slack Connect channels, whose history may be partial for the token
+	IsShared           bool `json:"shared,omitempty"`           // shared with other workspaces
+	IsExtShared        bool `json:"extShared,omitempty"`        // shared with another organization
+	IsPendingExtShared bool `json:"pendingExtShared,omitempty"` // invited to be shared, not accepted yet
+}
+
+func New() (*ApiProvider, error) {
+	// A bad key would otherwise only show as cache misses
+	if _, err := cacheKey(); err != nil {
+		return nil, err
+	}
+
+	// Offline mode serves from the cache files and needs no credentials
+	if isOfflineMode() {
+		return newOffline(), nil
+	}
+
+	return newFromCredentials(os.Getenv)
+}
+
+// newFromCredentials creates an ApiProvider for the credentials getenv
+// returns, looked up by their environment variable names.
+func newFromCredentials(getenv func(string) string) (*ApiProvider, error) {
+	creds, err := credentialsFrom(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	usersCache, usersCacheByTeam := teamCacheFile("SLACK_MCP_USERS_CACHE", "users_cache.json")
+	channelsCache, channelsCacheByTeam := teamCacheFile("SLACK_MCP_CHANNELS_CACHE", creds.channelsCache)
+
+	return &ApiProvider{
+		boot:  creds.boot,
+		token: creds.token,
+
+		users:               make(map[string]slack.User),
+		usersInv:            map[string]string{},
+		usersDisplayNameInv: map[string]string{},
+		usersRealNameInv:    map[string]string{},
+		usersEmailInv:       map[string]string{},
+		usersCache:          usersCache,
+		usersCacheByTeam:    usersCacheByTeam,
+
+		channels:            make(map[string]Channel),
+		channelsInv:         map[string]string{},
+		channelsCache:       channelsCache,
+		channelsCacheByTeam: channelsCacheByTeam,
+
+		rateTier: rateTier(),
+
+		isBotToken: creds.isBotToken,
+	}, nil
 }
 
-func New() *ApiProvider {
+// credentials are what the configured tokens decide about a provider: how
+// its client boots, with which token, whether it acts as a bot and the
+// default name of its channels cache file.
+type credentials struct {
+	boot          func(ap *ApiProvider) (*slack.Client, error)
+	token         string
+	isBotToken    bool
+	channelsCache string
+}
+
+// credentialsFrom picks the credentials getenv returns, looked up by their
+// environment variable names.
+func credentialsFrom(getenv func(string) string) (credentials, error) {
 	var (
 		authProvider auth.ValueAuth
 		err          error
 	)
 
 	// Priority 1: Check for XOXC/XOXD tokens (session-based) - most capable, supports search.messages
-	xoxcToken := os.Getenv("SLACK_MCP_XOXC_TOKEN")
-	xoxdToken := os.Getenv("SLACK_MCP_XOXD_TOKEN")
+	xoxcToken := getenv("SLACK_MCP_XOXC_TOKEN")
+	xoxdToken := getenv("SLACK_MCP_XOXD_TOKEN")
 
 	if xoxcToken != "" && xoxdToken != "" {
 		authProvider, err = auth.NewValueAuth(xoxcToken, xoxdToken)
 		if err != nil {
-			panic(err)
+			return credentials{}, err
 		}
 
-		return newWithXOXC(authProvider)
+		return credentials{boot: bootXOXC(authProvider), token: xoxcToken, channelsCache: "channels_cache_v2.json"}, nil
 	}
 
 	// Priority 2: Check for XOXP token (User OAuth) - supports search.messages
-	xoxpToken := os.Getenv("SLACK_MCP_XOXP_TOKEN")
+	xoxpToken := getenv("SLACK_MCP_XOXP_TOKEN")
 	if xoxpToken != "" {
 		// Validate that the token is actually a user token (xoxp-)
 		if strings.HasPrefix(xoxpToken, "xoxb-") {
@@ -130,24 +251,24 @@ func New() *ApiProvider {
 			// Treat it as a bot token
 			authProvider, err = auth.NewValueAuth(xoxpToken, "")
 			if err != nil {
-				panic(err)
+				return credentials{}, err
 			}
-			return newWithXOXB(authProvider)
+			return credentials{boot: bootOAuth(authProvider), token: xoxpToken, isBotToken: true, channelsCache: "channels_cache.json"}, nil
 		}
 		if strings.HasPrefix(xoxpToken, "xoxc-") {
-			panic("SLACK_MCP_XOXP_TOKEN contains a session token (xoxc-). Please use SLACK_MCP_XOXC_TOKEN and SLACK_MCP_XOXD_TOKEN for session-based authentication.")
+			return credentials{}, errors.New("SLACK_MCP_XOXP_TOKEN contains a session token (xoxc-). Please use SLACK_MCP_XOXC_TOKEN and SLACK_MCP_XOXD_TOKEN for session-based authentication")
 		}
 
 		authProvider, err = auth.NewValueAuth(xoxpToken, "")
 		if err != nil {
-			panic(err)
+			return credentials{}, err
 		}
 
-		return newWithXOXP(authProvider)
+		return credentials{boot: bootOAuth(authProvider), token: xoxpToken, channelsCache: "channels_cache.json"}, nil
 	}
 
 	// Priority 3: Check for XOXB token (Bot) - limited access, no search.messages
-	xoxbToken := os.Getenv("SLACK_MCP_XOXB_TOKEN")
+	xoxbToken := getenv("SLACK_MCP_XOXB_TOKEN")
 	if xoxbToken != "" {
 		// Validate that the token is actually a bot token (xoxb-)
 		if strings.HasPrefix(xoxbToken, "xoxp-") {
@@ -156,233 +277,229 @@ func New() *ApiProvider {
 
 		authProvider, err = auth.NewValueAuth(xoxbToken, "")
 		if err != nil {
-			panic(err)
+			return credentials{}, err
 		}
 
+		// Bot tokens have limited access compared to user tokens: they
+		// cannot use search.messages and only see the channels the bot has
+		// been invited to
 		log.Printf("Using Bot token authentication (xoxb). Note: Bot tokens cannot use search.messages API.")
-		return newWithXOXB(authProvider)
+		return credentials{boot: bootOAuth(authProvider), token: xoxbToken, isBotToken: true, channelsCache: "channels_cache.json"}, nil
 	}
 
-	panic("Authentication required: Either SLACK_MCP_XOXC_TOKEN and SLACK_MCP_XOXD_TOKEN (session-based, recommended), SLACK_MCP_XOXP_TOKEN (User OAuth), or SLACK_MCP_XOXB_TOKEN (Bot) environment variables must be provided")
+	return credentials{}, ErrNoCredentials
 }
 
-func newWithXOXP(authProvider auth.ValueAuth) *ApiProvider {
-	usersCache := os.Getenv("SLACK_MCP_USERS_CACHE")
-	if usersCache == "" {
-		cacheDir := getCacheDir()
-		usersCache = filepath.Join(cacheDir, "users_cache.json")
-	}
+// bootOAuth boots the client of an OAuth token, xoxp or xoxb.
+func bootOAuth(authProvider auth.ValueAuth) func(ap *ApiProvider) (*slack.Client, error) {
+	return func(ap *ApiProvider) (*slack.Client, error) {
+		scopes := &scopeRecorder{client: rateLimitRetryClient()}
+		api := slack.New(authProvider.SlackToken(), slack.OptionHTTPClient(scopes))
+		if err := ap.authenticate(authProvider.SlackToken(), api.AuthTest, scopes.Scopes); err != nil {
+			return nil, err
+		}
+		ap.authProvider = &authProvider
 
-	channelsCache := os.Getenv("SLACK_MCP_CHANNELS_CACHE")
-	if channelsCache == "" {
-		cacheDir := getCacheDir()
-		channelsCache = filepath.Join(cacheDir, "channels_cache.json")
+		return api, nil
 	}
+}
 
-	return &ApiProvider{
-		boot: func(ap *ApiProvider) *slack.Client {
-			api := slack.New(authProvider.SlackToken())
-			res, err := api.AuthTest()
-			if err != nil {
-				panic(err)
-			} else {
-				ap.authProvider = &authProvider
-				ap.authResponse = &slack2.AuthTestResponse{
-					URL:          res.URL,
-					Team:         res.Team,
-					User:         res.User,
-					TeamID:       res.TeamID,
-					UserID:       res.UserID,
-					EnterpriseID: res.EnterpriseID,
-					BotID:        res.BotID,
-				}
-				log.Printf("Authenticated as: %s\n", res)
-			}
-
-			return api
-		},
-
-		users:               make(map[string]slack.User),
-		usersInv:            map[string]string{},
-		usersDisplayNameInv: map[string]string{},
-		usersRealNameInv:    map[string]string{},
-		usersEmailInv:       map[string]string{},
-		usersCache:          usersCache,
+// bootXOXC boots the client of a session token, xoxc with its xoxd cookie.
+func bootXOXC(authProvider auth.ValueAuth) func(ap *ApiProvider) (*slack.Client, error) {
+	return func(ap *ApiProvider) (*slack.Client, error) {
+		api := slack.New(authProvider.SlackToken(),
+			withHTTPClientOption(authProvider.Cookies()),
+		)
+		if err := ap.authenticate(authProvider.SlackToken(), api.AuthTest, nil); err != nil {
+			return nil, err
+		}
+		ap.authProvider = &authProvider
+
+		// Note: We intentionally do NOT use withTeamEndpointOption here.
+		// Using team-specific endpoints (e.g., https://mono-corporation.slack.com/api/)
+		// breaks search.messages API which requires https://slack.com/api/
+		// The default slack.com endpoint works for all API calls including search.
+		api = slack.New(authProvider.SlackToken(),
+			withHTTPClientOption(authProvider.Cookies()),
+		)
 
-		channels:      make(map[string]Channel),
-		channelsInv:   map[string]string{},
-		channelsCache: channelsCache,
+		return api, nil
 	}
 }
 
-// newWithXOXB creates an ApiProvider for bot tokens (xoxb).
-// Bot tokens have limited access compared to user tokens:
-// - Cannot use search.messages API
-// - Can only access channels the bot has been invited to
-func newWithXOXB(authProvider auth.ValueAuth) *ApiProvider {
-	usersCache := os.Getenv("SLACK_MCP_USERS_CACHE")
-	if usersCache == "" {
-		cacheDir := getCacheDir()
-		usersCache = filepath.Join(cacheDir, "users_cache.json")
-	}
-
-	channelsCache := os.Getenv("SLACK_MCP_CHANNELS_CACHE")
-	if channelsCache == "" {
-		cacheDir := getCacheDir()
-		channelsCache = filepath.Join(cacheDir, "channels_cache.json")
+// ProvideGeneric returns the Web API client, booting it on first use.
+// Concurrent first calls wait for a single boot, a failed one is returned
+// to all of them until the cooldown elapses.
+func (ap *ApiProvider) ProvideGeneric() (*slack.Client, error) {
+	ap.clientMu.RLock()
+	cached := ap.clientGeneric
+	ap.clientMu.RUnlock()
+	if cached != nil {
+		return cached, nil
 	}
 
-	return &ApiProvider{
-		boot: func(ap *ApiProvider) *slack.Client {
-			api := slack.New(authProvider.SlackToken())
-			res, err := api.AuthTest()
-			if err != nil {
-				panic(err)
-			} else {
-				ap.authProvider = &authProvider
-				ap.authResponse = &slack2.AuthTestResponse{
-					URL:          res.URL,
-					Team:         res.Team,
-					User:         res.User,
-					TeamID:       res.TeamID,
-					UserID:       res.UserID,
-					EnterpriseID: res.EnterpriseID,
-					BotID:        res.BotID,
-				}
-				log.Printf("Authenticated as bot: %s\n", res)
-			}
+	ap.clientMu.Lock()
+	defer ap.clientMu.Unlock()
 
-			return api
-		},
+	return ap.provideGeneric()
+}
 
-		users:               make(map[string]slack.User),
-		usersInv:            map[string]string{},
-		usersDisplayNameInv: map[string]string{},
-		usersRealNameInv:    map[string]string{},
-		usersEmailInv:       map[string]string{},
-		usersCache:          usersCache,
+// provideGeneric is ProvideGeneric, the caller must hold clientMu.
+func (ap *ApiProvider) provideGeneric() (*slack.Client, error) {
+	if ap.offline {
+		dir := fixturesDir()
+		if dir == "" {
+			return nil, ErrOffline
+		}
+		if ap.clientGeneric == nil {
+			ap.clientGeneric = newReplayClient(dir)
+		}
+		return ap.clientGeneric, nil
+	}
 
-		channels:      make(map[string]Channel),
-		channelsInv:   map[string]string{},
-		channelsCache: channelsCache,
+	if ap.clientGeneric == nil {
+		// Don't hammer auth.test while the workspace is failing
+		if ap.bootErr != nil && time.Since(ap.bootFailedAt) < bootCooldown() {
+			return nil, ap.bootErr
+		}
 
-		isBotToken: true, // Mark as bot token
+		client, err := ap.boot(ap)
+		if err != nil {
+			ap.bootErr, ap.bootFailedAt = err, time.Now()
+			return nil, err
+		}
+		ap.clientGeneric, ap.bootErr = client, nil
 	}
+
+	return ap.clientGeneric, nil
 }
 
-func newWithXOXC(authProvider auth.ValueAuth) *ApiProvider {
-	usersCache := os.Getenv("SLACK_MCP_USERS_CACHE")
-	if usersCache == "" {
-		cacheDir := getCacheDir()
-		usersCache = filepath.Join(cacheDir, "users_cache.json")
+func (ap *ApiProvider) ProvideEnterprise() (*edge.Client, error) {
+	if ap.offline {
+		return nil, ErrOffline
 	}
 
-	channelsCache := os.Getenv("SLACK_MCP_CHANNELS_CACHE")
-	if channelsCache == "" {
-		cacheDir := getCacheDir()
-		channelsCache = filepath.Join(cacheDir, "channels_cache_v2.json")
+	ap.clientMu.RLock()
+	cached := ap.clientEnterprise
+	ap.clientMu.RUnlock()
+	if cached != nil {
+		return cached, nil
 	}
 
-	return &ApiProvider{
-		boot: func(ap *ApiProvider) *slack.Client {
-			api := slack.New(authProvider.SlackToken(),
-				withHTTPClientOption(authProvider.Cookies()),
-			)
-			res, err := api.AuthTest()
-			if err != nil {
-				panic(err)
-			} else {
-				ap.authProvider = &authProvider
-				ap.authResponse = &slack2.AuthTestResponse{
-					URL:          res.URL,
-					Team:         res.Team,
-					User:         res.User,
-					TeamID:       res.TeamID,
-					UserID:       res.UserID,
-					EnterpriseID: res.EnterpriseID,
-					BotID:        res.BotID,
-				}
-				log.Printf("Authenticated as: %s\n", res)
-			}
+	ap.clientMu.Lock()
+	defer ap.clientMu.Unlock()
 
-			// Note: We intentionally do NOT use withTeamEndpointOption here.
-			// Using team-specific endpoints (e.g., https://mono-corporation.slack.com/api/)
-			// breaks search.messages API which requires https://slack.com/api/
-			// The default slack.com endpoint works for all API calls including search.
-			api = slack.New(authProvider.SlackToken(),
-				withHTTPClientOption(authProvider.Cookies()),
-			)
-
-			return api
-		},
-
-		users:               make(map[string]slack.User),
-		usersInv:            map[string]string{},
-		usersDisplayNameInv: map[string]string{},
-		usersRealNameInv:    map[string]string{},
-		usersEmailInv:       map[string]string{},
-		usersCache:          usersCache,
+	if ap.clientEnterprise == nil {
+		// The edge client is built from the auth.test response of the boot
+		if _, err := ap.provideGeneric(); err != nil {
+			return nil, err
+		}
 
-		channels:      make(map[string]Channel),
-		channelsInv:   map[string]string{},
-		channelsCache: channelsCache,
+		client, err := edge.NewWithInfo(ap.authResponse, ap.authProvider,
+			withHTTPClientEdgeOption(ap.authProvider.Cookies()),
+		)
+		if err != nil {
+			return nil, err
+		}
+		ap.clientEnterprise = client
 	}
+
+	return ap.clientEnterprise, nil
 }
 
-func (ap *ApiProvider) ProvideGeneric() (*slack.Client, error) {
-	if ap.clientGeneric == nil {
-		ap.clientGeneric = ap.boot(ap)
+// teamCachePath returns the cache file at path, keyed by the team of the
+// token when byTeam so that switching tokens between workspaces does not
+// mix up their caches. The team is remembered next to the cache, only a
+// token seen for the first time boots the client to learn it: a cached
+// workspace keeps loading while Slack is unreachable.
+func (ap *ApiProvider) teamCachePath(path string, byTeam bool) (string, error) {
+	if !byTeam {
+		return path, nil
+	}
+	if res := ap.authTest(); res != nil {
+		return teamCacheName(path, res.TeamID), nil
 	}
 
-	return ap.clientGeneric, nil
-}
+	ap.clientMu.RLock()
+	token := ap.token
+	ap.clientMu.RUnlock()
 
-func (ap *ApiProvider) ProvideEnterprise() (*edge.Client, error) {
-	if ap.clientEnterprise == nil {
-		ap.clientEnterprise, _ = edge.NewWithInfo(ap.authResponse, ap.authProvider,
-			withHTTPClientEdgeOption(ap.authProvider.Cookies()),
-		)
+	teams := filepath.Join(filepath.Dir(path), teamsCacheName)
+	if teamID, ok := loadTeamID(teams, token); ok {
+		return teamCacheName(path, teamID), nil
 	}
 
-	return ap.clientEnterprise, nil
+	if _, err := ap.ProvideGeneric(); err != nil {
+		return "", err
+	}
+	teamID := ap.authTest().TeamID
+	saveTeamID(teams, token, teamID)
+	return teamCacheName(path, teamID), nil
 }
 
+// RefreshUsers loads the users cache, from its file or from Slack. A
+// failure is recorded for Health, cached users keep being served.
 func (ap *ApiProvider) RefreshUsers(ctx context.Context) error {
-	if data, err := ioutil.ReadFile(ap.usersCache); err == nil {
-		var cachedUsers []slack.User
-		if err := json.Unmarshal(data, &cachedUsers); err != nil {
-			log.Printf("Failed to unmarshal %s: %v; will refetch", ap.usersCache, err)
-		} else {
-			for _, u := range cachedUsers {
-				ap.users[u.ID] = u
-				ap.usersInv[u.Name] = u.ID
+	return ap.recordUsersRefresh(ap.refreshUsers(ctx, false))
+}
 
-				// Add display name mapping (normalized)
-				if u.Profile.DisplayName != "" {
-					normalizedDisplayName := normalizeString(u.Profile.DisplayName)
-					ap.usersDisplayNameInv[normalizedDisplayName] = u.ID
-				}
+// ForceRefreshUsers fetches the users from Slack even when the cache file
+// could be loaded, e.g. to pick up someone who just joined, and rewrites it.
+// No user is dropped: users.list keeps listing deactivated users, flagged
+// as deleted, and users of other organizations fetched one by one with
+// users.info stay cached.
+func (ap *ApiProvider) ForceRefreshUsers(ctx context.Context) error {
+	if ap.offline {
+		return ErrOffline
+	}
+	return ap.recordUsersRefresh(ap.refreshUsers(ctx, true))
+}
 
-				// Add real name mapping (normalized)
-				if u.RealName != "" {
-					normalizedRealName := normalizeString(u.RealName)
-					ap.usersRealNameInv[normalizedRealName] = u.ID
-				}
+func (ap *ApiProvider) recordUsersRefresh(err error) error {
+	ap.usersMu.Lock()
+	ap.usersErr = err
+	ap.usersMu.Unlock()
 
-				// Add email mapping
-				if u.Profile.Email != "" {
-					ap.usersEmailInv[u.Profile.Email] = u.ID
-				}
+	return err
+}
+
+func (ap *ApiProvider) refreshUsers(ctx context.Context, force bool) error {
+	usersCache, err := ap.teamCachePath(ap.usersCache, ap.usersCacheByTeam)
+	if err != nil {
+		return err
+	}
+
+	var salvaged []slack.User // decoded from a damaged or outdated cache file
+	if data, err := readCacheFile(usersCache); err == nil && !force {
+		cachedUsers, version, err := decodeCache[slack.User](data)
+		if err != nil {
+			log.Printf("Failed to unmarshal %s: %v; will refetch", usersCache, err)
+			// a mistyped field fails the whole file, not the users around it
+			salvaged = slices.DeleteFunc(cachedUsers, func(u slack.User) bool { return u.ID == "" })
+		} else if version < usersCacheVersion && !ap.offline {
+			log.Printf("Users cache %q has schema version %d, %d is current; will refetch", usersCache, version, usersCacheVersion)
+			salvaged = cachedUsers
+		} else {
+			ap.usersMu.Lock()
+			for _, u := range cachedUsers {
+				ap.indexUser(u)
 			}
-			log.Printf("Loaded %d users from cache %q", len(cachedUsers), ap.usersCache)
+			ap.usersMu.Unlock()
+			log.Printf("Loaded %d users from cache %q", len(cachedUsers), usersCache)
 			return nil
 		}
 	}
 
+	if ap.offline {
+		ap.indexSalvagedUsers(usersCache, salvaged)
+		return fmt.Errorf("%w: users cache %q could not be loaded", ErrOffline, usersCache)
+	}
+
 	optionLimit := slack.GetUsersOptionLimit(1000)
 
 	client, err := ap.ProvideGeneric()
 	if err != nil {
+		ap.indexSalvagedUsers(usersCache, salvaged)
 		return err
 	}
 
@@ -390,91 +507,196 @@ func (ap *ApiProvider) RefreshUsers(ctx context.Context) error {
 		optionLimit,
 	)
 	if err != nil {
-		log.Printf("Failed to fetch users: %v", err)
+		log.Printf("Failed to fetch users: %s", transport.Redact(err.Error()))
+		ap.indexSalvagedUsers(usersCache, salvaged)
 		return err
 	}
 
+	ap.usersMu.Lock()
 	for _, user := range users {
-		ap.users[user.ID] = user
-		ap.usersInv[user.Name] = user.ID
-
-		// Add display name mapping (normalized)
-		if user.Profile.DisplayName != "" {
-			normalizedDisplayName := normalizeString(user.Profile.DisplayName)
-			ap.usersDisplayNameInv[normalizedDisplayName] = user.ID
-		}
-
-		// Add real name mapping (normalized)
-		if user.RealName != "" {
-			normalizedRealName := normalizeString(user.RealName)
-			ap.usersRealNameInv[normalizedRealName] = user.ID
-		}
-
-		// Add email mapping
-		if user.Profile.Email != "" {
-			ap.usersEmailInv[user.Profile.Email] = user.ID
-		}
+		ap.indexUser(user)
 	}
+	ap.usersMu.Unlock()
 
-	if data, err := json.MarshalIndent(users, "", "  "); err != nil {
+	if usersCache == "" {
+		log.Printf("Cached %d users in memory", len(users))
+	} else if data, err := encodeCache(usersCacheVersion, users); err != nil {
 		log.Printf("Failed to marshal users for cache: %v", err)
 	} else {
-		if err := ioutil.WriteFile(ap.usersCache, data, 0644); err != nil {
-			log.Printf("Failed to write cache file %q: %v", ap.usersCache, err)
+		if err := writeCacheFile(usersCache, data, 0644); err != nil {
+			log.Printf("Failed to write cache file %q: %v", usersCache, err)
 		} else {
-			log.Printf("Wrote %d users to cache %q", len(users), ap.usersCache)
+			log.Printf("Wrote %d users to cache %q", len(users), usersCache)
 		}
 	}
 
 	return nil
 }
 
+// indexSalvagedUsers serves the users decoded from a damaged cache file
+// while a refetch fails, unless users were loaded before: a partial cache
+// beats none, not a complete one.
+func (ap *ApiProvider) indexSalvagedUsers(path string, users []slack.User) {
+	if len(users) == 0 {
+		return
+	}
+
+	ap.usersMu.Lock()
+	defer ap.usersMu.Unlock()
+
+	if len(ap.users) > 0 {
+		return
+	}
+	for _, u := range users {
+		ap.indexUser(u)
+	}
+	log.Printf("Serving %d users salvaged from cache %q until a refetch succeeds", len(users), path)
+}
+
+// indexUser adds u to the users cache and its lookup maps, the caller must
+// hold usersMu.
+func (ap *ApiProvider) indexUser(u slack.User) {
+	ap.users[u.ID] = u
+	ap.usersInv[u.Name] = u.ID
+
+	// Add display name mapping (normalized)
+	if u.Profile.DisplayName != "" {
+		normalizedDisplayName := normalizeString(u.Profile.DisplayName)
+		ap.usersDisplayNameInv[normalizedDisplayName] = u.ID
+	}
+
+	// Add real name mapping (normalized)
+	if u.RealName != "" {
+		normalizedRealName := normalizeString(u.RealName)
+		ap.usersRealNameInv[normalizedRealName] = u.ID
+	}
+
+	// Add email mapping
+	if u.Profile.Email != "" {
+		ap.usersEmailInv[u.Profile.Email] = u.ID
+	}
+}
+
+// RefreshChannels loads the channels cache, from its file or from Slack. A
+// failure is recorded for Health, cached channels keep being served.
 func (ap *ApiProvider) RefreshChannels(ctx context.Context) error {
-	if data, err := ioutil.ReadFile(ap.channelsCache); err == nil {
-		var cachedChannels []Channel
-		if err := json.Unmarshal(data, &cachedChannels); err != nil {
-			log.Printf("Failed to unmarshal %s: %v; will refetch", ap.channelsCache, err)
+	return ap.recordChannelsRefresh(ap.refreshChannels(ctx, false))
+}
+
+// ForceRefreshChannels fetches the channels from Slack even when the cache
+// file could be loaded, and rewrites it. Channels Slack no longer lists are
+// dropped, see GetChannels.
+func (ap *ApiProvider) ForceRefreshChannels(ctx context.Context) error {
+	if ap.offline {
+		return ErrOffline
+	}
+	return ap.recordChannelsRefresh(ap.refreshChannels(ctx, true))
+}
+
+func (ap *ApiProvider) recordChannelsRefresh(err error) error {
+	ap.channelsMu.Lock()
+	ap.channelsErr = err
+	ap.channelsMu.Unlock()
+
+	return err
+}
+
+func (ap *ApiProvider) refreshChannels(ctx context.Context, force bool) error {
+	channelsCache, err := ap.teamCachePath(ap.channelsCache, ap.channelsCacheByTeam)
+	if err != nil {
+		return err
+	}
+
+	var outdated []Channel // decoded from a cache file of an older schema
+	if data, err := readCacheFile(channelsCache); err == nil && !force {
+		cachedChannels, version, err := decodeCache[Channel](data)
+		if err != nil {
+			log.Printf("Failed to unmarshal %s: %v; will refetch", channelsCache, err)
+		} else if version < channelsCacheVersion && !ap.offline {
+			log.Printf("Channels cache %q has schema version %d, %d is current; will refetch", channelsCache, version, channelsCacheVersion)
+			outdated = cachedChannels
 		} else {
-			// Re-map channels with current users cache to ensure DM names are populated
-			usersMap := ap.ProvideUsersMap().Users
-			for _, c := range cachedChannels {
-				// For IM channels, re-generate the name and purpose using current users cache
-				if c.IsIM {
-					// Re-map the channel to get updated user name if available
-					remappedChannel := mapChannel(
-						c.ID, "", "", c.Topic, c.Purpose,
-						c.User, c.Members, c.MemberCount,
-						c.IsIM, c.IsMpIM, c.IsPrivate,
-						usersMap,
-					)
-					ap.channels[c.ID] = remappedChannel
-					ap.channelsInv[remappedChannel.Name] = c.ID
-				} else {
-					ap.channels[c.ID] = c
-					ap.channelsInv[c.Name] = c.ID
-				}
-			}
-			log.Printf("Loaded %d channels from cache %q (DM names re-mapped)", len(cachedChannels), ap.channelsCache)
+			ap.indexCachedChannels(cachedChannels)
+			log.Printf("Loaded %d channels from cache %q (DM names re-mapped)", len(cachedChannels), channelsCache)
 			return nil
 		}
 	}
 
-	channels := ap.GetChannels(ctx, AllChanTypes)
+	if ap.offline {
+		return fmt.Errorf("%w: channels cache %q could not be loaded", ErrOffline, channelsCache)
+	}
+
+	channels, err := ap.GetChannels(ctx, append(slices.Clone(AllChanTypes), ArchivedChanType))
+	if err != nil {
+		// the outdated entries beat none, not the ones fetched meanwhile
+		if len(outdated) > 0 {
+			cached := ap.ProvideChannelsMaps().Channels
+			outdated = slices.DeleteFunc(outdated, func(c Channel) bool {
+				_, ok := cached[c.ID]
+				return ok
+			})
+			ap.indexCachedChannels(outdated)
+			log.Printf("Serving %d channels from the outdated cache %q until a refetch succeeds", len(outdated), channelsCache)
+		}
+		return err
+	}
+
+	writeChannelsCache(channelsCache, channels)
+
+	return nil
+}
 
-	if data, err := json.MarshalIndent(channels, "", "  "); err != nil {
+// writeChannelsCache persists channels to the cache file at path, failures
+// are only logged: the channels stay cached in memory.
+func writeChannelsCache(path string, channels []Channel) {
+	if path == "" {
+		log.Printf("Cached %d channels in memory", len(channels))
+	} else if data, err := encodeCache(channelsCacheVersion, channels); err != nil {
 		log.Printf("Failed to marshal channels for cache: %v", err)
 	} else {
-		if err := ioutil.WriteFile(ap.channelsCache, data, 0644); err != nil {
-			log.Printf("Failed to write cache file %q: %v", ap.channelsCache, err)
+		if err := writeCacheFile(path, data, 0644); err != nil {
+			log.Printf("Failed to write cache file %q: %v", path, err)
 		} else {
-			log.Printf("Wrote %d channels to cache %q", len(channels), ap.channelsCache)
+			log.Printf("Wrote %d channels to cache %q", len(channels), path)
 		}
 	}
+}
 
-	return nil
+// indexCachedChannels adds channels read from a cache file to the channels
+// cache. The names of DMs are re-mapped with the current users cache, the
+// configured name prefixes re-applied.
+func (ap *ApiProvider) indexCachedChannels(cachedChannels []Channel) {
+	usersMap := ap.ProvideUsersMap().Users
+
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	for _, c := range cachedChannels {
+		// For IM channels, re-generate the name and purpose using current users cache
+		if c.IsIM {
+			// Re-map the channel to get updated user name if available
+			remappedChannel := mapChannel(rawChannel{Channel: c}, usersMap)
+			ap.channels[c.ID] = remappedChannel
+			ap.channelsInv[remappedChannel.Name] = c.ID
+		} else {
+			// Re-apply the configured name prefix, it may have changed
+			// since the cache was written
+			if c.IsMpIM {
+				c.Name = DMNamePrefix() + bareChannelName(c.Name)
+			} else {
+				c.Name = ChannelNamePrefix() + bareChannelName(c.Name)
+			}
+			ap.channels[c.ID] = c
+			ap.channelsInv[c.Name] = c.ID
+		}
+	}
 }
 
-func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) []Channel {
+// GetChannels fetches all channels into the cache and returns the cached
+// ones of the given types. Once the listing is complete the cached channels
+// it did not include, e.g. deleted ones, are dropped. On failure the pages
+// fetched so far stay cached and the error is returned.
+func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) ([]Channel, error) {
 	if len(channelTypes) == 0 {
 		channelTypes = AllChanTypes
 	}
@@ -482,7 +704,7 @@ func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) [
 	params := &slack.GetConversationsParameters{
 		Types:           AllChanTypes,
 		Limit:           999,
-		ExcludeArchived: true,
+		ExcludeArchived: false, // cached with IsArchived, filtered below
 	}
 
 	var (
@@ -495,90 +717,111 @@ func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) [
 
 	clientGeneric, err := ap.ProvideGeneric()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	clientE, err := ap.ProvideEnterprise()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	lim := limiter.Tier2boost.Limiter()
+	enterprise := ap.authTest().EnterpriseID != ""
+	fetchBudget := mpimMemberFetchLimit()
+	fetchMembers := fetchMembersEnabled()
+	lim := ap.rateTier.Limiter()
+	listed := make(map[string]bool)
 	for {
-		if ap.authResponse.EnterpriseID == "" {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// only this page is cached below, the previous ones already are
+		chans = chans[:0]
+		if !enterprise {
 			chans1, nextcur, err = clientGeneric.GetConversationsContext(ctx, params)
 			if err != nil {
-				log.Printf("Failed to fetch channels: %v", err)
-				break
+				log.Printf("Failed to fetch channels: %s", transport.Redact(err.Error()))
+				return nil, err
 			}
+			fetchBudget -= ap.fetchMpimMembers(ctx, mpimMembers(chans1), fetchBudget)
+			usersMap := ap.ProvideUsersMap().Users
 			for _, channel := range chans1 {
-				ch := mapChannel(
-					channel.ID,
-					channel.Name,
-					channel.NameNormalized,
-					channel.Topic.Value,
-					channel.Purpose.Value,
-					channel.User,
-					channel.Members,
-					channel.NumMembers,
-					channel.IsIM,
-					channel.IsMpIM,
-					channel.IsPrivate,
-					ap.ProvideUsersMap().Users,
-				)
+				ch := mapChannel(fromSlackChannel(channel), usersMap)
 				chans = append(chans, ch)
 			}
-			if err := lim.Wait(ctx); err != nil {
-				return nil
-			}
 		} else {
-			chans2, _, err = clientE.GetConversationsContext(ctx, nil)
+			chans2, nextcur, err = clientE.GetConversationsContext(ctx, &slack2.GetConversationsParameters{Cursor: params.Cursor})
 			if err != nil {
-				log.Printf("Failed to fetch channels: %v", err)
-				break
+				log.Printf("Failed to fetch channels: %s", transport.Redact(err.Error()))
+				return nil, err
 			}
+			var members [][]string
+			for _, channel := range chans2 {
+				if channel.IsMpIM {
+					members = append(members, channel.Members)
+				}
+			}
+			fetchBudget -= ap.fetchMpimMembers(ctx, members, fetchBudget)
+			usersMap := ap.ProvideUsersMap().Users
 			for _, channel := range chans2 {
 				if params.ExcludeArchived && channel.IsArchived {
 					continue
 				}
 
-				ch := mapChannel(
-					channel.ID,
-					channel.Name,
-					channel.NameNormalized,
-					channel.Topic.Value,
-					channel.Purpose.Value,
-					channel.User,
-					channel.Members,
-					channel.NumMembers,
-					channel.IsIM,
-					channel.IsMpIM,
-					channel.IsPrivate,
-					ap.ProvideUsersMap().Users,
-				)
+				ch := mapChannel(fromEdgeChannel(channel), usersMap)
 				chans = append(chans, ch)
 			}
-			if err := lim.Wait(ctx); err != nil {
-				return nil
-			}
 		}
 
+		if fetchMembers {
+			ap.fillMembers(ctx, chans)
+		}
+
+		ap.channelsMu.Lock()
 		for _, ch := range chans {
+			// a renamed channel must not keep resolving by its old name
+			if old, ok := ap.channels[ch.ID]; ok && old.Name != ch.Name && ap.channelsInv[old.Name] == ch.ID {
+				delete(ap.channelsInv, old.Name)
+			}
 			ap.channels[ch.ID] = ch
 			ap.channelsInv[ch.Name] = ch.ID
+			listed[ch.ID] = true
 		}
+		ap.channelsMu.Unlock()
 
 		if nextcur == "" {
 			log.Printf("channels fetch exhausted")
+			ap.pruneChannels(listed)
+			break
+		}
+		// a client ignoring the cursor would serve the same page forever
+		if nextcur == params.Cursor {
+			log.Printf("channels cursor %q repeated, stopping", nextcur)
 			break
 		}
 
 		params.Cursor = nextcur
+
+		if err := lim.Wait(ctx); err != nil {
+			return nil, err
+		}
 	}
 
+	includeArchived := slices.Contains(channelTypes, ArchivedChanType)
+	memberOnly := slices.Contains(channelTypes, MemberChanType)
+
+	ap.channelsMu.RLock()
+	defer ap.channelsMu.RUnlock()
+
 	var res []Channel
 	for _, t := range channelTypes {
 		for _, channel := range ap.channels {
+			if channel.IsArchived && !includeArchived {
+				continue
+			}
+			if memberOnly && !channel.IsMember {
+				continue
+			}
 			if t == "public_channel" && !channel.IsPrivate {
 				res = append(res, channel)
 			}
@@ -594,26 +837,114 @@ func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) [
 		}
 	}
 
-	return res
+	return res, nil
+}
+
+// pruneChannels drops the cached channels that are not listed, after a
+// complete listing: Slack no longer lists deleted channels, nor the ones
+// the token lost access to.
+func (ap *ApiProvider) pruneChannels(listed map[string]bool) {
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	for id, ch := range ap.channels {
+		if listed[id] {
+			continue
+		}
+		delete(ap.channels, id)
+		if ap.channelsInv[ch.Name] == id {
+			delete(ap.channelsInv, ch.Name)
+		}
+	}
 }
 
+// ProvideUsersMap returns a snapshot of the users cache, safe to read while
+// the cache is refreshed.
 func (ap *ApiProvider) ProvideUsersMap() *UsersCache {
+	ap.usersMu.RLock()
+	defer ap.usersMu.RUnlock()
+
 	return &UsersCache{
-		Users:               ap.users,
-		UsersInv:            ap.usersInv,
-		UsersDisplayNameInv: ap.usersDisplayNameInv,
-		UsersRealNameInv:    ap.usersRealNameInv,
-		UsersEmailInv:       ap.usersEmailInv,
+		Users:               maps.Clone(ap.users),
+		UsersInv:            maps.Clone(ap.usersInv),
+		UsersDisplayNameInv: maps.Clone(ap.usersDisplayNameInv),
+		UsersRealNameInv:    maps.Clone(ap.usersRealNameInv),
+		UsersEmailInv:       maps.Clone(ap.usersEmailInv),
 	}
 }
 
+// ResolveUser returns the cached user with the given ID.
+func (ap *ApiProvider) ResolveUser(id string) (slack.User, bool) {
+	ap.usersMu.RLock()
+	defer ap.usersMu.RUnlock()
+
+	u, ok := ap.users[id]
+	return u, ok
+}
+
+// ResolveUsers returns the cached users for the given IDs, looked up under a
+// single read-lock acquisition. Unknown IDs are omitted from the result, so
+// it is cheaper than calling ResolveUser once per mention when rendering a
+// whole transcript.
+func (ap *ApiProvider) ResolveUsers(ids []string) map[string]slack.User {
+	res := make(map[string]slack.User, len(ids))
+
+	ap.usersMu.RLock()
+	defer ap.usersMu.RUnlock()
+
+	for _, id := range ids {
+		if u, ok := ap.users[id]; ok {
+			res[id] = u
+		}
+	}
+
+	return res
+}
+
+// ProvideChannelsMaps returns a snapshot of the channels cache, safe to read
+// while the cache is refreshed.
 func (ap *ApiProvider) ProvideChannelsMaps() *ChannelsCache {
+	ap.channelsMu.RLock()
+	defer ap.channelsMu.RUnlock()
+
 	return &ChannelsCache{
-		Channels:    ap.channels,
-		ChannelsInv: ap.channelsInv,
+		Channels:    maps.Clone(ap.channels),
+		ChannelsInv: maps.Clone(ap.channelsInv),
 	}
 }
 
+// bootCooldown returns how long a failed boot is remembered before auth.test
+// is tried again, configured with SLACK_MCP_BOOT_COOLDOWN as a Go duration.
+func bootCooldown() time.Duration {
+	v := os.Getenv("SLACK_MCP_BOOT_COOLDOWN")
+	if v == "" {
+		return defaultBootCooldown
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("Invalid SLACK_MCP_BOOT_COOLDOWN %q, using %s", v, defaultBootCooldown)
+		return defaultBootCooldown
+	}
+	return d
+}
+
+// rateTier returns the limiter tier set with SLACK_MCP_RATE_TIER, falling
+// back to tier2boost when it is unset or invalid.
+func rateTier() limiter.Tier {
+	name := os.Getenv("SLACK_MCP_RATE_TIER")
+	if name == "" {
+		return limiter.Tier2boost
+	}
+
+	tier, err := limiter.ParseTier(name)
+	if err != nil {
+		log.Printf("%v, using tier2boost", err)
+		return limiter.Tier2boost
+	}
+	return tier
+}
+
 func withHTTPClientOption(cookies []*http.Cookie) func(c *slack.Client) {
 	return func(c *slack.Client) {
 		slack.OptionHTTPClient(provideHTTPClient(cookies))(c)
@@ -626,6 +957,15 @@ func withHTTPClientEdgeOption(cookies []*http.Cookie) func(c *edge.Client) {
 	}
 }
 
+// rateLimitRetryClient is the HTTP client of xoxp and xoxb tokens: the
+// proxy, CA and client certificate settings of provideHTTPClient, without
+// its session cookies and browser user agents.
+func rateLimitRetryClient() *http.Client {
+	return &http.Client{
+		Transport: withFixtureRecording(transport.NewRetry(withHTTPDebug(provideHTTPTransport()), maxRetries())),
+	}
+}
+
 func withTeamEndpointOption(url string) slack.Option {
 	return func(c *slack.Client) {
 		slack.OptionAPIURL(url + "api/")(c)
@@ -633,11 +973,41 @@ func withTeamEndpointOption(url string) slack.Option {
 }
 
 func provideHTTPClient(cookies []*http.Cookie) *http.Client {
+	client := &http.Client{
+		Transport: withFixtureRecording(transport.NewRetry(
+			withHTTPDebug(transport.New(
+				provideHTTPTransport(),
+				sessionUserAgent(),
+				cookies,
+			)),
+			maxRetries(),
+		)),
+	}
+
+	return client
+}
+
+// sessionUserAgent is the User-Agent sent with the session cookies, one of
+// SLACK_MCP_USER_AGENT picked at random on first use and kept for the life
+// of the process: a browser session does not change its User-Agent between
+// requests.
+var sessionUserAgent = sync.OnceValue(func() string {
+	uas := transport.SplitUserAgents(os.Getenv("SLACK_MCP_USER_AGENT"))
+	if len(uas) == 0 {
+		return defaultUA
+	}
+	return uas[rand.IntN(len(uas))]
+})
+
+// provideHTTPTransport returns the transport configured by SLACK_MCP_PROXY,
+// SLACK_MCP_SERVER_CA, SLACK_MCP_SERVER_CA_INSECURE and the client
+// certificate variables.
+func provideHTTPTransport() *http.Transport {
 	var proxy func(*http.Request) (*url.URL, error)
 	if proxyURL := os.Getenv("SLACK_MCP_PROXY"); proxyURL != "" {
 		parsed, err := url.Parse(proxyURL)
 		if err != nil {
-			log.Fatalf("Failed to parse proxy URL: %v", err)
+			log.Fatalf("Failed to parse proxy URL: %s", transport.Redact(err.Error()))
 		}
 
 		proxy = http.ProxyURL(parsed)
@@ -669,105 +1039,214 @@ func provideHTTPClient(cookies []*http.Cookie) *http.Client {
 		insecure = true
 	}
 
-	customHTTPTransport := &http.Transport{
+	certs, err := clientCertificates()
+	if err != nil {
+		log.Fatalf("Failed to load the client certificate: %v", err)
+	}
+
+	return &http.Transport{
 		Proxy: proxy,
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: insecure,
 			RootCAs:            rootCAs,
+			Certificates:       certs,
 		},
 	}
+}
 
-	userAgent := defaultUA
-	if os.Getenv("SLACK_MCP_USER_AGENT") != "" {
-		userAgent = os.Getenv("SLACK_MCP_USER_AGENT")
+// withHTTPDebug logs every attempt made through roundTripper when
+// SLACK_MCP_HTTP_DEBUG is set, with tokens and cookies redacted.
+func withHTTPDebug(roundTripper http.RoundTripper) http.RoundTripper {
+	switch os.Getenv("SLACK_MCP_HTTP_DEBUG") {
+	case "", "0", "false":
+		return roundTripper
 	}
+	return transport.NewDebug(roundTripper)
+}
 
-	client := &http.Client{
-		Transport: transport.New(
-			customHTTPTransport,
-			userAgent,
-			cookies,
-		),
+// clientCertificates loads the certificate presented to mTLS-terminating
+// proxies from SLACK_MCP_CLIENT_CERT and SLACK_MCP_CLIENT_KEY, which must be
+// set together. It returns nil when neither is set.
+func clientCertificates() ([]tls.Certificate, error) {
+	certFile := os.Getenv("SLACK_MCP_CLIENT_CERT")
+	keyFile := os.Getenv("SLACK_MCP_CLIENT_KEY")
+
+	switch {
+	case certFile == "" && keyFile == "":
+		return nil, nil
+	case certFile == "":
+		return nil, errors.New("SLACK_MCP_CLIENT_KEY is set without SLACK_MCP_CLIENT_CERT")
+	case keyFile == "":
+		return nil, errors.New("SLACK_MCP_CLIENT_CERT is set without SLACK_MCP_CLIENT_KEY")
 	}
 
-	return client
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%q and %q: %w", certFile, keyFile, err)
+	}
+	return []tls.Certificate{cert}, nil
 }
 
-func mapChannel(
-	id, name, nameNormalized, topic, purpose, user string,
-	members []string,
-	numMembers int,
-	isIM, isMpIM, isPrivate bool,
-	usersMap map[string]slack.User,
-) Channel {
-	channelName := name
-	finalPurpose := purpose
-	finalTopic := topic
-	finalMemberCount := numMembers
+// rawChannelText reports whether SLACK_MCP_RAW_CHANNEL_TEXT asks for channel
+// topics and purposes to be kept exactly as Slack returns them.
+func rawChannelText() bool {
+	switch os.Getenv("SLACK_MCP_RAW_CHANNEL_TEXT") {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
 
-	var userID string
-	if isIM {
-		finalMemberCount = 2
-		userID = user // Store the user ID for later re-mapping
+// purposeName names u in DM and group DM purposes, by real name unless
+// SLACK_MCP_DISPLAY_NAME_PREF says otherwise, falling back to the user ID.
+func purposeName(u slack.User) string {
+	if name := text.UserName(u, text.NameReal); name != "" {
+		return name
+	}
+	return u.ID
+}
+
+// rawChannel is a conversation as Slack returns it, before mapChannel
+// prefixes its name and resolves its topic, purpose and DM peer.
+type rawChannel struct {
+	Channel
+	NameNormalized string
+}
+
+func fromSlackChannel(c slack.Channel) rawChannel {
+	return rawChannel{
+		Channel: Channel{
+			ID:          c.ID,
+			Name:        c.Name,
+			Topic:       c.Topic.Value,
+			Purpose:     c.Purpose.Value,
+			MemberCount: c.NumMembers,
+			IsMpIM:      c.IsMpIM,
+			IsIM:        c.IsIM,
+			IsPrivate:   c.IsPrivate,
+			IsArchived:  c.IsArchived,
+			IsMember:    c.IsMember,
+			User:        c.User,
+			Members:     c.Members,
+			Created:     int64(c.Created),
+			Creator:     c.Creator,
+
+			IsShared:           c.IsShared,
+			IsExtShared:        c.IsExtShared,
+			IsPendingExtShared: c.IsPendingExtShared,
+		},
+		NameNormalized: c.NameNormalized,
+	}
+}
+
+func fromEdgeChannel(c slack2.Channel) rawChannel {
+	return rawChannel{
+		Channel: Channel{
+			ID:          c.ID,
+			Name:        c.Name,
+			Topic:       c.Topic.Value,
+			Purpose:     c.Purpose.Value,
+			MemberCount: c.NumMembers,
+			IsMpIM:      c.IsMpIM,
+			IsIM:        c.IsIM,
+			IsPrivate:   c.IsPrivate,
+			IsArchived:  c.IsArchived,
+			IsMember:    c.IsMember,
+			User:        c.User,
+			Members:     c.Members,
+			Created:     int64(c.Created),
+			Creator:     c.Creator,
+
+			IsShared:           c.IsShared,
+			IsExtShared:        c.IsExtShared,
+			IsPendingExtShared: c.IsPendingExtShared,
+		},
+		NameNormalized: c.NameNormalized,
+	}
+}
+
+func mapChannel(raw rawChannel, usersMap map[string]slack.User) Channel {
+	ch := raw.Channel
+
+	if !rawChannelText() {
+		ch.Purpose = text.ProcessInlineText(raw.Purpose, usersMap)
+		ch.Topic = text.ProcessInlineText(raw.Topic, usersMap)
+	}
+
+	if ch.IsIM {
+		ch.MemberCount = 2
 
 		// If user field is empty but we have members, try to extract from members
-		if userID == "" && len(members) > 0 {
+		if ch.User == "" && len(ch.Members) > 0 {
 			// For IM channels, members should contain the other user's ID
 			// Try each member to find a valid user in the users map
-			for _, memberID := range members {
+			for _, memberID := range ch.Members {
 				if _, ok := usersMap[memberID]; ok {
-					userID = memberID
+					ch.User = memberID
 					break
 				}
 			}
 		}
 
-		if u, ok := usersMap[userID]; ok {
-			channelName = "@" + u.Name
-			finalPurpose = "DM with " + u.RealName
-		} else if userID != "" {
-			channelName = "@" + userID
-			finalPurpose = "DM with " + userID
+		if u, ok := usersMap[ch.User]; ok {
+			ch.Name = DMNamePrefix() + u.Name
+			ch.Purpose = "DM with " + purposeName(u)
+		} else if ch.User != "" {
+			ch.Name = DMNamePrefix() + ch.User
+			ch.Purpose = "DM with " + ch.User
 		} else {
-			channelName = "@"
-			finalPurpose = "DM with "
-		}
-		finalTopic = ""
-	} else if isMpIM {
-		if len(members) > 0 {
-			finalMemberCount = len(members)
-			var userNames []string
-			for _, uid := range members {
+			ch.Name = DMNamePrefix()
+			ch.Purpose = "DM with "
+		}
+		ch.Topic = ""
+	} else if ch.IsMpIM {
+		if len(ch.Members) > 0 {
+			ch.MemberCount = len(ch.Members)
+			var (
+				userNames []string
+				unknown   int
+			)
+			for _, uid := range ch.Members {
 				if u, ok := usersMap[uid]; ok {
-					userNames = append(userNames, u.RealName)
+					userNames = append(userNames, purposeName(u))
 				} else {
-					userNames = append(userNames, uid)
+					unknown++
 				}
 			}
-			channelName = "@" + nameNormalized
-			finalPurpose = "Group DM with " + strings.Join(userNames, ", ")
-			finalTopic = ""
+			switch unknown {
+			case 0:
+			case 1:
+				userNames = append(userNames, "1 unknown user")
+			default:
+				userNames = append(userNames, fmt.Sprintf("%d unknown users", unknown))
+			}
+			ch.Name = DMNamePrefix() + raw.NameNormalized
+			ch.Purpose = "Group DM with " + strings.Join(userNames, ", ")
+			ch.Topic = ""
 		}
 	} else {
-		channelName = "#" + nameNormalized
+		ch.Name = ChannelNamePrefix() + raw.NameNormalized
 	}
 
-	return Channel{
-		ID:          id,
-		Name:        channelName,
-		Topic:       finalTopic,
-		Purpose:     finalPurpose,
-		MemberCount: finalMemberCount,
-		IsIM:        isIM,
-		IsMpIM:      isMpIM,
-		IsPrivate:   isPrivate,
-		User:        userID,
-		Members:     members,
-	}
+	// DMs are only ever listed to their members
+	ch.IsMember = ch.IsMember || ch.IsIM || ch.IsMpIM
+	return ch
 }
 
 // IsBotToken returns true if the provider is using a bot token (xoxb).
 // Bot tokens have limited access and cannot use search.messages API.
 func (ap *ApiProvider) IsBotToken() bool {
+	ap.clientMu.RLock()
+	defer ap.clientMu.RUnlock()
+
 	return ap.isBotToken
 }
+
+// authTest returns the auth.test response of the current credentials, nil
+// before the boot.
+func (ap *ApiProvider) authTest() *slack2.AuthTestResponse {
+	ap.clientMu.RLock()
+	defer ap.clientMu.RUnlock()
+
+	return ap.authResponse
+}
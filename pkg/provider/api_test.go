@@ -0,0 +1,354 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	slack2 "github.com/rusq/slack"
+	"github.com/rusq/slackdump/v3/auth"
+	"github.com/slack-go/slack"
+)
+
+// testProviderOption configures the provider newTestProvider returns.
+type testProviderOption func(ap *ApiProvider)
+
+// newTestProvider returns a provider with empty caches holding n generated
+// users, user0 to user<n-1>, configured further with opts.
+func newTestProvider(n int, opts ...testProviderOption) (*ApiProvider, []string) {
+	ap := &ApiProvider{
+		users:               make(map[string]slack.User, n),
+		usersInv:            map[string]string{},
+		usersDisplayNameInv: map[string]string{},
+		usersRealNameInv:    map[string]string{},
+		usersEmailInv:       map[string]string{},
+		channels:            map[string]Channel{},
+		channelsInv:         map[string]string{},
+	}
+
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("U%08d", i)
+		ap.users[id] = slack.User{ID: id, Name: fmt.Sprintf("user%d", i)}
+		ids = append(ids, id)
+	}
+
+	for _, opt := range opts {
+		opt(ap)
+	}
+
+	return ap, ids
+}
+
+// withWebAPI points the Web API client at a test server answering with
+// handler. GetChannels can run against it, the workspace is not a grid.
+func withWebAPI(t *testing.T, handler http.Handler) testProviderOption {
+	return func(ap *ApiProvider) {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+		if ap.authResponse == nil {
+			ap.authResponse = &slack2.AuthTestResponse{}
+		}
+		if ap.clientEnterprise == nil {
+			ap.clientEnterprise = &edge.Client{}
+		}
+	}
+}
+
+// withEdgeAPI points the edge client at a test server answering with
+// handler, authenticated as info with a session token.
+func withEdgeAPI(t *testing.T, handler http.Handler, info slack2.AuthTestResponse) testProviderOption {
+	return func(ap *ApiProvider) {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		authProvider, err := auth.NewValueAuth("xoxc-test", "xoxd-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		info.URL = srv.URL + "/"
+		client, err := edge.NewWithInfo(&info, authProvider)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ap.authResponse = &info
+		ap.clientEnterprise = client
+	}
+}
+
+// withBootAPI leaves the provider to boot lazily with token against a test
+// server answering with handler.
+func withBootAPI(t *testing.T, token string, handler http.Handler) testProviderOption {
+	return func(ap *ApiProvider) {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		authProvider, err := auth.NewValueAuth(token, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ap.isBotToken = strings.HasPrefix(token, "xoxb-")
+		ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+			api := slack.New(token, slack.OptionAPIURL(srv.URL+"/"))
+			if err := ap.authenticate(token, api.AuthTest, nil); err != nil {
+				return nil, err
+			}
+			ap.authProvider = &authProvider
+			return api, nil
+		}
+	}
+}
+
+// withUsers adds users to the users cache and its lookup maps.
+func withUsers(users ...slack.User) testProviderOption {
+	return func(ap *ApiProvider) {
+		for _, u := range users {
+			ap.indexUser(u)
+		}
+	}
+}
+
+// withChannels adds channels to the channels cache, by ID and by name.
+func withChannels(channels ...Channel) testProviderOption {
+	return func(ap *ApiProvider) {
+		for _, c := range channels {
+			ap.channels[c.ID] = c
+			ap.channelsInv[c.Name] = c.ID
+		}
+	}
+}
+
+// withCacheFiles keeps the users and channels cache files in dir.
+func withCacheFiles(dir string) testProviderOption {
+	return func(ap *ApiProvider) {
+		ap.usersCache = filepath.Join(dir, "users_cache.json")
+		ap.channelsCache = filepath.Join(dir, "channels_cache.json")
+	}
+}
+
+func TestResolveUsers(t *testing.T) {
+	ap, ids := newTestProvider(3)
+
+	got := ap.ResolveUsers(append(ids[:2:2], "UUNKNOWN"))
+	if len(got) != 2 {
+		t.Fatalf("expected 2 resolved users, got %d", len(got))
+	}
+	for _, id := range ids[:2] {
+		if got[id].ID != id {
+			t.Errorf("expected user %q to be resolved, got %+v", id, got[id])
+		}
+	}
+	if _, ok := got["UUNKNOWN"]; ok {
+		t.Errorf("expected unknown user to be omitted")
+	}
+}
+
+func TestGetCacheDir_EnvOverride(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "cache")
+	t.Setenv("SLACK_MCP_CACHE_DIR", dir)
+
+	if got := getCacheDir(); got != dir {
+		t.Fatalf("getCacheDir() = %q, expected %q", got, dir)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		t.Fatalf("expected cache dir %q to be created, err: %v", dir, err)
+	}
+}
+
+func BenchmarkResolveUserPerID(b *testing.B) {
+	ap, ids := newTestProvider(1000)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for _, id := range ids {
+				_, _ = ap.ResolveUser(id)
+			}
+		}
+	})
+}
+
+func BenchmarkResolveUsersBatched(b *testing.B) {
+	ap, ids := newTestProvider(1000)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = ap.ResolveUsers(ids)
+		}
+	})
+}
+
+func TestTokenType(t *testing.T) {
+	tests := []struct {
+		token    string
+		isBot    bool
+		expected string
+	}{
+		{token: "xoxp-1-2", expected: TokenTypeUser},
+		{token: "xoxb-1-2", expected: TokenTypeBot},
+		{token: "xoxp-1-2", isBot: true, expected: TokenTypeBot},
+		{token: "xoxc-1-2", expected: TokenTypeSession},
+	}
+	for _, tt := range tests {
+		if got := tokenType(tt.token, tt.isBot); got != tt.expected {
+			t.Errorf("tokenType(%q, %v) = %q, expected %q", tt.token, tt.isBot, got, tt.expected)
+		}
+	}
+}
+
+func TestProvideGeneric_BootFailure(t *testing.T) {
+	bootErr := errors.New("auth.test failed: 503")
+	ap, _ := newTestProvider(0)
+	ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+		return nil, bootErr
+	}
+
+	if _, err := ap.ProvideGeneric(); !errors.Is(err, bootErr) {
+		t.Errorf("expected the boot error, got %v", err)
+	}
+	if _, err := ap.ProvideEnterprise(); !errors.Is(err, bootErr) {
+		t.Errorf("expected the boot error from the enterprise client, got %v", err)
+	}
+}
+
+func TestNew_NoCredentials(t *testing.T) {
+	for _, env := range []string{"SLACK_MCP_OFFLINE", "SLACK_MCP_XOXC_TOKEN", "SLACK_MCP_XOXD_TOKEN", "SLACK_MCP_XOXP_TOKEN", "SLACK_MCP_XOXB_TOKEN"} {
+		t.Setenv(env, "")
+	}
+
+	if _, err := New(); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestProvideGeneric_BootCooldown(t *testing.T) {
+	t.Setenv("SLACK_MCP_BOOT_COOLDOWN", "1h")
+
+	var boots int
+	ap, _ := newTestProvider(0)
+	ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+		boots++
+		if boots == 1 {
+			return nil, errors.New("auth.test failed: 503 Service Unavailable")
+		}
+		return slack.New("xoxp-test"), nil
+	}
+
+	if _, err := ap.ProvideGeneric(); err == nil {
+		t.Fatal("expected the first boot to fail")
+	}
+
+	// within the cooldown the failure is returned without booting again
+	if _, err := ap.ProvideGeneric(); err == nil || boots != 1 {
+		t.Fatalf("expected the cached failure, got %v after %d boots", err, boots)
+	}
+
+	// once it elapsed, boot is retried and its success is kept
+	ap.bootFailedAt = time.Now().Add(-2 * time.Hour)
+	if client, err := ap.ProvideGeneric(); err != nil || client == nil {
+		t.Fatalf("expected the retried boot to succeed, got %v", err)
+	}
+	if _, err := ap.ProvideGeneric(); err != nil || boots != 2 {
+		t.Fatalf("expected the client to be cached, got %v after %d boots", err, boots)
+	}
+}
+
+func TestProvideGeneric_ConcurrentFirstCallsBootOnce(t *testing.T) {
+	t.Setenv("SLACK_MCP_BOOT_COOLDOWN", "1h")
+
+	for _, fail := range []bool{false, true} {
+		var boots atomic.Int32
+		ap, _ := newTestProvider(0)
+		ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+			boots.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			if fail {
+				return nil, errors.New("auth.test failed: 503 Service Unavailable")
+			}
+			return slack.New("xoxp-test"), nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = ap.ProvideGeneric()
+			}()
+		}
+		wg.Wait()
+
+		if got := boots.Load(); got != 1 {
+			t.Errorf("fail=%v: expected a single boot, got %d", fail, got)
+		}
+	}
+}
+
+func TestProvideMaps_ReturnCopies(t *testing.T) {
+	ap, ids := newTestProvider(2)
+	ap.usersInv = map[string]string{"user0": ids[0]}
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general"}}
+	ap.channelsInv = map[string]string{"#general": "C1"}
+
+	users := ap.ProvideUsersMap()
+	delete(users.Users, ids[0])
+	users.Users["U_INJECTED"] = slack.User{ID: "U_INJECTED"}
+	users.UsersInv["user0"] = "U_INJECTED"
+
+	if _, ok := ap.ResolveUser(ids[0]); !ok {
+		t.Error("deleting from the returned users map must not touch the cache")
+	}
+	if _, ok := ap.ResolveUser("U_INJECTED"); ok {
+		t.Error("adding to the returned users map must not touch the cache")
+	}
+	if ap.usersInv["user0"] != ids[0] {
+		t.Error("the returned lookup maps must be copies too")
+	}
+
+	channels := ap.ProvideChannelsMaps()
+	channels.Channels["C1"] = Channel{ID: "C1", Name: "#renamed"}
+	channels.ChannelsInv["#renamed"] = "C1"
+
+	if ap.channels["C1"].Name != "#general" || len(ap.channelsInv) != 1 {
+		t.Error("mutating the returned channels maps must not touch the cache")
+	}
+}
+
+func TestProvideHTTPClient_KeepsOneUserAgent(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+	}))
+	defer srv.Close()
+
+	for range 2 {
+		client := provideHTTPClient(nil)
+		for range 3 {
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+		}
+	}
+
+	for _, ua := range got {
+		if ua == "" || ua != got[0] {
+			t.Fatalf("expected one User-Agent for the session, got %q", got)
+		}
+	}
+}
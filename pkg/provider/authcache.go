@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+// authCacheNow is the clock the auth cache ages against.
+var authCacheNow = time.Now
+
+// authCacheEntry is the auth.test outcome persisted between runs.
+type authCacheEntry struct {
+	TokenHash string                  `json:"token_hash"`
+	WrittenAt time.Time               `json:"written_at"`
+	Response  slack2.AuthTestResponse `json:"response"`
+	Scopes    []string                `json:"scopes,omitempty"`
+}
+
+// authCacheTTL returns how long an auth.test response is reused across
+// runs, configured with SLACK_MCP_AUTH_CACHE_TTL as a Go duration. It is 0,
+// disabling the cache, by default: a revoked token would otherwise pass
+// boot until the TTL elapses.
+func authCacheTTL() time.Duration {
+	v := os.Getenv("SLACK_MCP_AUTH_CACHE_TTL")
+	if v == "" {
+		return 0
+	}
+
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl < 0 {
+		log.Printf("Invalid SLACK_MCP_AUTH_CACHE_TTL %q, not caching auth.test", v)
+		return 0
+	}
+	return ttl
+}
+
+// authCachePath returns the file the auth.test response is persisted to.
+func authCachePath() string {
+	return filepath.Join(getCacheDir(), "auth_cache.json")
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authenticate records who token belongs to on ap. Within the cache TTL the
+// persisted response of a previous run with the same token is reused,
+// otherwise authTest is called and, unless scopes is nil, the token's scopes
+// it saw are recorded. Both are persisted for the next run.
+func (ap *ApiProvider) authenticate(token string, authTest func() (*slack.AuthTestResponse, error), scopes func() map[string]bool) error {
+	ttl := authCacheTTL()
+	if memoryCacheMode() {
+		ttl = 0 // never persisted
+	}
+	var path string
+	if ttl > 0 {
+		path = authCachePath()
+		if entry, ok := loadAuthCache(path, token, ttl); ok {
+			ap.authResponse = &entry.Response
+			if scopes != nil && entry.Scopes != nil {
+				ap.scopes = make(map[string]bool, len(entry.Scopes))
+				for _, s := range entry.Scopes {
+					ap.scopes[s] = true
+				}
+			}
+			log.Printf("Authenticated as %s on %s (cached %s ago)", entry.Response.User, entry.Response.Team, authCacheNow().Sub(entry.WrittenAt).Round(time.Second))
+			return nil
+		}
+	}
+
+	res, err := authTest()
+	if err != nil {
+		return fmt.Errorf("auth.test failed: %w", err)
+	}
+	ap.authResponse = &slack2.AuthTestResponse{
+		URL:          res.URL,
+		Team:         res.Team,
+		User:         res.User,
+		TeamID:       res.TeamID,
+		UserID:       res.UserID,
+		EnterpriseID: res.EnterpriseID,
+		BotID:        res.BotID,
+	}
+	if ap.isBotToken {
+		log.Printf("Authenticated as bot: %s\n", res)
+	} else {
+		log.Printf("Authenticated as: %s\n", res)
+	}
+
+	if scopes != nil {
+		ap.recordScopes(scopes())
+	}
+
+	if ttl > 0 {
+		writeAuthCache(path, token, ap.authResponse, ap.scopes)
+	}
+
+	return nil
+}
+
+// loadAuthCache returns the persisted entry when it was written for token
+// less than ttl ago.
+func loadAuthCache(path, token string, ttl time.Duration) (authCacheEntry, bool) {
+	data, err := readCacheFile(path)
+	if err != nil {
+		return authCacheEntry{}, false
+	}
+
+	var entry authCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Printf("Failed to unmarshal %s: %v; will call auth.test", path, err)
+		return authCacheEntry{}, false
+	}
+
+	// a different token, or an expired entry, is simply overwritten later
+	if entry.TokenHash != tokenHash(token) || authCacheNow().Sub(entry.WrittenAt) >= ttl {
+		return authCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func writeAuthCache(path, token string, res *slack2.AuthTestResponse, scopes map[string]bool) {
+	entry := authCacheEntry{
+		TokenHash: tokenHash(token),
+		WrittenAt: authCacheNow(),
+		Response:  *res,
+	}
+	if scopes != nil {
+		entry.Scopes = make([]string, 0, len(scopes))
+		for s := range scopes {
+			entry.Scopes = append(entry.Scopes, s)
+		}
+		sort.Strings(entry.Scopes)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		log.Printf("Failed to marshal auth.test for cache: %v", err)
+		return
+	}
+	// it identifies the workspace and user, keep it private
+	if err := writeCacheFile(path, data, 0600); err != nil {
+		log.Printf("Failed to write cache file %q: %v", path, err)
+	}
+}
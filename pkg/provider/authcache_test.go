@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestAuthenticate_DiskCache(t *testing.T) {
+	t.Setenv("SLACK_MCP_CACHE_DIR", t.TempDir())
+	t.Setenv("SLACK_MCP_AUTH_CACHE_TTL", "1h")
+
+	clock := time.Unix(1700000000, 0)
+	authCacheNow = func() time.Time { return clock }
+	t.Cleanup(func() { authCacheNow = time.Now })
+
+	var calls int
+	authTest := func() (*slack.AuthTestResponse, error) {
+		calls++
+		return &slack.AuthTestResponse{User: "alice", Team: "Acme", TeamID: "T1", UserID: "U1"}, nil
+	}
+
+	boot := func(token string) *ApiProvider {
+		t.Helper()
+		ap, _ := newTestProvider(0)
+		if err := ap.authenticate(token, authTest, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return ap
+	}
+
+	boot("xoxp-1")
+	if calls != 1 {
+		t.Fatalf("expected the first boot to call auth.test, got %d calls", calls)
+	}
+
+	// a later run within the TTL skips the round-trip
+	clock = clock.Add(30 * time.Minute)
+	ap := boot("xoxp-1")
+	if calls != 1 {
+		t.Errorf("expected the cached response to be used, got %d calls", calls)
+	}
+	if ap.authResponse.TeamID != "T1" || ap.authResponse.UserID != "U1" {
+		t.Errorf("unexpected cached response %+v", ap.authResponse)
+	}
+
+	// another token does not get it
+	boot("xoxp-2")
+	if calls != 2 {
+		t.Errorf("expected a changed token to call auth.test, got %d calls", calls)
+	}
+
+	// nor does the same token once the TTL elapsed
+	clock = clock.Add(2 * time.Hour)
+	boot("xoxp-2")
+	if calls != 3 {
+		t.Errorf("expected an expired entry to call auth.test, got %d calls", calls)
+	}
+}
+
+func TestAuthenticate_CacheDisabledByDefault(t *testing.T) {
+	t.Setenv("SLACK_MCP_CACHE_DIR", t.TempDir())
+	t.Setenv("SLACK_MCP_AUTH_CACHE_TTL", "")
+
+	var calls int
+	authTest := func() (*slack.AuthTestResponse, error) {
+		calls++
+		return &slack.AuthTestResponse{User: "alice"}, nil
+	}
+
+	for range 2 {
+		ap, _ := newTestProvider(0)
+		if err := ap.authenticate("xoxp-1", authTest, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected every boot to call auth.test, got %d calls", calls)
+	}
+}
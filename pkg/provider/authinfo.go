@@ -0,0 +1,63 @@
+package provider
+
+import "strings"
+
+const (
+	TokenTypeUser    = "xoxp"
+	TokenTypeBot     = "xoxb"
+	TokenTypeSession = "xoxc"
+	TokenTypeOffline = "offline"
+)
+
+// AuthInfo describes who the provider is authenticated as.
+type AuthInfo struct {
+	TokenType    string
+	Team         string
+	TeamID       string
+	URL          string
+	User         string
+	UserID       string
+	BotID        string
+	EnterpriseID string
+}
+
+// AuthInfo returns the token type and the auth.test details of the current
+// credentials, booting the Slack client if that has not happened yet.
+func (ap *ApiProvider) AuthInfo() (AuthInfo, error) {
+	if ap.offline {
+		return AuthInfo{TokenType: TokenTypeOffline}, nil
+	}
+
+	if _, err := ap.ProvideGeneric(); err != nil {
+		return AuthInfo{}, err
+	}
+
+	ap.clientMu.RLock()
+	defer ap.clientMu.RUnlock()
+
+	info := AuthInfo{TokenType: tokenType(ap.authProvider.SlackToken(), ap.isBotToken)}
+	if r := ap.authResponse; r != nil {
+		info.Team = r.Team
+		info.TeamID = r.TeamID
+		info.URL = r.URL
+		info.User = r.User
+		info.UserID = r.UserID
+		info.BotID = r.BotID
+		info.EnterpriseID = r.EnterpriseID
+	}
+
+	return info, nil
+}
+
+// tokenType derives the token type from its prefix, a bot token given in
+// SLACK_MCP_XOXP_TOKEN still counts as a bot token.
+func tokenType(token string, isBot bool) string {
+	switch {
+	case isBot || strings.HasPrefix(token, "xoxb-"):
+		return TokenTypeBot
+	case strings.HasPrefix(token, "xoxc-"):
+		return TokenTypeSession
+	default:
+		return TokenTypeUser
+	}
+}
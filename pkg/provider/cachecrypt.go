@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrInvalidCacheKey is returned by New when SLACK_MCP_CACHE_KEY is set but
+// is not a base64 encoded AES-256 key.
+var ErrInvalidCacheKey = errors.New("SLACK_MCP_CACHE_KEY must be 32 base64 encoded bytes")
+
+// cacheKey returns the key the cache files are encrypted with, read from
+// SLACK_MCP_CACHE_KEY, or nil when they are kept in plaintext.
+func cacheKey() ([]byte, error) {
+	v := os.Getenv("SLACK_MCP_CACHE_KEY")
+	if v == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCacheKey, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w, got %d bytes", ErrInvalidCacheKey, len(key))
+	}
+	return key, nil
+}
+
+// encryptCache seals data with AES-GCM, the random nonce is prepended.
+func encryptCache(key, data []byte) ([]byte, error) {
+	gcm, err := newCacheGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// decryptCache opens data sealed by encryptCache. It fails for plaintext
+// files, e.g. written before the key was set, and for a different key.
+func decryptCache(key, data []byte) ([]byte, error) {
+	gcm, err := newCacheGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func newCacheGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheEncryption_RoundTrip(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users.list":
+			_, _ = w.Write([]byte(`{"ok": true, "members": [{"id": "U1", "name": "alice", "profile": {"email": "alice@example.com"}}]}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true}], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})
+
+	t.Setenv("SLACK_MCP_CACHE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)))
+	dir := t.TempDir()
+
+	ap, _ := newTestProvider(0, withCacheFiles(dir), withWebAPI(t, handler))
+	if err := ap.RefreshUsers(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing users: %v", err)
+	}
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing channels: %v", err)
+	}
+
+	for _, name := range []string{"users_cache.json", "channels_cache.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+		if bytes.Contains(data, []byte("alice")) || bytes.Contains(data, []byte("general")) {
+			t.Errorf("expected %s to be encrypted, got %q", name, data)
+		}
+	}
+
+	// a fresh provider without a client can only be served from the files
+	reloaded, _ := newTestProvider(0, withCacheFiles(dir))
+	reloaded.offline = true
+	if err := reloaded.RefreshUsers(context.Background()); err != nil {
+		t.Fatalf("unexpected error reloading users: %v", err)
+	}
+	if err := reloaded.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error reloading channels: %v", err)
+	}
+	if u := reloaded.users["U1"]; u.Profile.Email != "alice@example.com" {
+		t.Errorf("expected U1 to be decrypted, got %+v", u)
+	}
+	if _, ok := reloaded.channels["C1"]; !ok {
+		t.Errorf("expected C1 to be decrypted")
+	}
+
+	// another key cannot read them
+	t.Setenv("SLACK_MCP_CACHE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+	other, _ := newTestProvider(0, withCacheFiles(dir))
+	other.offline = true
+	if err := other.RefreshUsers(context.Background()); !errors.Is(err, ErrOffline) {
+		t.Errorf("expected the users cache not to be readable with another key, got %v", err)
+	}
+}
+
+func TestNew_InvalidCacheKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "short", key: base64.StdEncoding.EncodeToString(make([]byte, 16))},
+		{name: "not base64", key: "not a key!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SLACK_MCP_CACHE_KEY", tt.key)
+			t.Setenv("SLACK_MCP_OFFLINE", "true")
+
+			if _, err := New(); !errors.Is(err, ErrInvalidCacheKey) {
+				t.Errorf("expected ErrInvalidCacheKey, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// memoryCacheMode reports whether SLACK_MCP_CACHE_MODE is "memory": users,
+// channels, emoji and auth.test are then kept in memory only and nothing is
+// read from or written to the cache directory, which is not even created.
+// The default "disk" mode persists them between runs.
+func memoryCacheMode() bool {
+	switch v := os.Getenv("SLACK_MCP_CACHE_MODE"); v {
+	case "", "disk":
+		return false
+	case "memory":
+		return true
+	default:
+		log.Printf("Invalid SLACK_MCP_CACHE_MODE %q, using disk", v)
+		return false
+	}
+}
+
+// cacheFile returns the path of a cache file, as set in the env variable or
+// named name in the cache directory. It is empty in memory mode, which
+// refreshUsers and refreshChannels take as not to touch the disk.
+func cacheFile(env, name string) string {
+	if memoryCacheMode() {
+		return ""
+	}
+	if path := os.Getenv(env); path != "" {
+		return path
+	}
+	return filepath.Join(getCacheDir(), name)
+}
+
+// teamCacheFile is cacheFile for the users and channels caches. It also
+// reports whether the file is the default one, which is keyed by the team
+// of the token once that is known, see teamCachePath.
+func teamCacheFile(env, name string) (string, bool) {
+	path := cacheFile(env, name)
+	return path, path != "" && os.Getenv(env) == ""
+}
+
+// teamCacheName inserts teamID into the name of the cache file at path,
+// e.g. users_cache.json becomes users_cache_T12345.json.
+func teamCacheName(path, teamID string) string {
+	if teamID == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_" + teamID + ext
+}
+
+// teamsCacheName is the file, next to the users and channels caches, that
+// remembers the team of the tokens they were keyed by, see teamCachePath.
+const teamsCacheName = "teams_cache.json"
+
+// loadTeamID returns the team remembered for token in the teams cache at
+// path.
+func loadTeamID(path, token string) (string, bool) {
+	teamID := readTeamsCache(path)[tokenHash(token)]
+	return teamID, teamID != ""
+}
+
+// saveTeamID remembers teamID as the team of token in the teams cache at
+// path.
+func saveTeamID(path, token, teamID string) {
+	if teamID == "" {
+		return
+	}
+	teams := readTeamsCache(path)
+	if teams == nil {
+		teams = make(map[string]string)
+	}
+	teams[tokenHash(token)] = teamID
+
+	data, err := json.MarshalIndent(teams, "", "  ")
+	if err != nil {
+		log.Printf("Failed to marshal teams for cache: %v", err)
+		return
+	}
+	if err := writeCacheFile(path, data, 0600); err != nil {
+		log.Printf("Failed to write cache file %q: %v", path, err)
+	}
+}
+
+// readTeamsCache returns the teams in the teams cache at path by token
+// hash, nil when it cannot be read.
+func readTeamsCache(path string) map[string]string {
+	data, err := readCacheFile(path)
+	if err != nil {
+		return nil
+	}
+	var teams map[string]string
+	if err := json.Unmarshal(data, &teams); err != nil {
+		log.Printf("Failed to unmarshal %s: %v; will call auth.test", path, err)
+		return nil
+	}
+	return teams
+}
+
+// cacheTeamID returns the team teamCacheName inserted into the name of the
+// cache file at path, which is named name when not keyed by team. It is
+// empty for a file that is not keyed by team.
+func cacheTeamID(path, name string) string {
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "_"
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, prefix) || !strings.HasSuffix(base, ext) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(base, prefix), ext)
+}
+
+// newestCacheFile returns the most recently written cache file matching
+// one of patterns in the cache directory, empty when there is none.
+func newestCacheFile(patterns ...string) string {
+	var (
+		newest  string
+		modTime time.Time
+	)
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(getCacheDir(), pattern))
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err == nil && fi.ModTime().After(modTime) {
+				newest, modTime = m, fi.ModTime()
+			}
+		}
+	}
+	return newest
+}
+
+// errMemoryCache is returned reading a cache file in memory mode.
+var errMemoryCache = errors.New("cache files are disabled in memory mode")
+
+// readCacheFile returns the contents of a cache file, decrypted when
+// SLACK_MCP_CACHE_KEY is set.
+func readCacheFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, errMemoryCache
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := cacheKey()
+	if err != nil || key == nil {
+		return data, err
+	}
+	if data, err = decryptCache(key, data); err != nil {
+		log.Printf("Failed to decrypt %s: %v; will refetch", path, err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// writeCacheFile writes a cache file, encrypted when SLACK_MCP_CACHE_KEY is
+// set.
+func writeCacheFile(path string, data []byte, perm os.FileMode) error {
+	key, err := cacheKey()
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		if data, err = encryptCache(key, data); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path, data, perm)
+}
@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+func TestMemoryCacheMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok": true, "user": "alice", "team": "acme"}`))
+		case "/users.list":
+			_, _ = w.Write([]byte(`{"ok": true, "members": [{"id": "U1", "name": "alice"}]}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true}], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	t.Setenv("SLACK_MCP_OFFLINE", "")
+	t.Setenv("SLACK_MCP_XOXC_TOKEN", "")
+	t.Setenv("SLACK_MCP_XOXD_TOKEN", "")
+	t.Setenv("SLACK_MCP_XOXB_TOKEN", "")
+	t.Setenv("SLACK_MCP_XOXP_TOKEN", "xoxp-test")
+	t.Setenv("SLACK_MCP_CACHE_MODE", "memory")
+	t.Setenv("SLACK_MCP_CACHE_DIR", cacheDir)
+	t.Setenv("SLACK_MCP_USERS_CACHE", "")
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", "")
+	t.Setenv("SLACK_MCP_AUTH_CACHE_TTL", "1h")
+
+	ap, err := New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ap.usersCache != "" || ap.channelsCache != "" {
+		t.Errorf("expected no cache files, got %q and %q", ap.usersCache, ap.channelsCache)
+	}
+
+	client := slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	if err := ap.authenticate("xoxp-test", client.AuthTest, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ap.clientGeneric = client
+	ap.clientEnterprise = &edge.Client{}
+
+	if err := ap.RefreshUsers(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing users: %v", err)
+	}
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing channels: %v", err)
+	}
+
+	if _, ok := ap.ProvideUsersMap().Users["U1"]; !ok {
+		t.Errorf("expected U1 to be cached in memory")
+	}
+	if _, ok := ap.ProvideChannelsMaps().Channels["C1"]; !ok {
+		t.Errorf("expected C1 to be cached in memory")
+	}
+
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Errorf("expected the cache dir not to be created, got %v", err)
+	}
+}
+
+func TestMemoryCacheMode_Invalid(t *testing.T) {
+	t.Setenv("SLACK_MCP_CACHE_MODE", "tmpfs")
+	if memoryCacheMode() {
+		t.Errorf("expected an invalid mode to fall back to disk")
+	}
+}
+
+func TestCacheFiles_KeyedByTeam(t *testing.T) {
+	team := "T1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok": true, "user": "alice", "team": "acme", "team_id": "` + team + `"}`))
+		case "/users.list":
+			_, _ = w.Write([]byte(`{"ok": true, "members": [{"id": "U1", "name": "alice"}]}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true}], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	t.Setenv("SLACK_MCP_OFFLINE", "")
+	t.Setenv("SLACK_MCP_XOXC_TOKEN", "")
+	t.Setenv("SLACK_MCP_XOXD_TOKEN", "")
+	t.Setenv("SLACK_MCP_XOXB_TOKEN", "")
+	t.Setenv("SLACK_MCP_XOXP_TOKEN", "xoxp-test")
+	t.Setenv("SLACK_MCP_CACHE_MODE", "")
+	t.Setenv("SLACK_MCP_CACHE_DIR", cacheDir)
+	t.Setenv("SLACK_MCP_USERS_CACHE", "")
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", "")
+	t.Setenv("SLACK_MCP_AUTH_CACHE_TTL", "")
+
+	refresh := func() {
+		t.Helper()
+		t.Setenv("SLACK_MCP_XOXP_TOKEN", "xoxp-"+team)
+		ap, err := New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client := slack.New("xoxp-"+team, slack.OptionAPIURL(srv.URL+"/"))
+		ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+			return client, ap.authenticate("xoxp-"+team, client.AuthTest, nil)
+		}
+		ap.clientEnterprise = &edge.Client{}
+
+		if err := ap.RefreshUsers(context.Background()); err != nil {
+			t.Fatalf("unexpected error refreshing users: %v", err)
+		}
+		if err := ap.RefreshChannels(context.Background()); err != nil {
+			t.Fatalf("unexpected error refreshing channels: %v", err)
+		}
+	}
+
+	refresh()
+	team = "T2"
+	refresh()
+
+	for _, name := range []string{"users_cache_T1.json", "channels_cache_T1.json", "users_cache_T2.json", "channels_cache_T2.json"} {
+		if _, err := os.Stat(filepath.Join(cacheDir, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "users_cache.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no cache shared by both teams, got %v", err)
+	}
+
+	// a known token finds its team's caches without booting, e.g. while
+	// Slack is unreachable
+	t.Setenv("SLACK_MCP_XOXP_TOKEN", "xoxp-T1")
+	ap, err := New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+		return nil, errors.New("slack is unreachable")
+	}
+	if err := ap.RefreshUsers(context.Background()); err != nil {
+		t.Fatalf("expected the cached users to load without booting, got %v", err)
+	}
+	if _, ok := ap.ProvideUsersMap().Users["U1"]; !ok {
+		t.Errorf("expected the users of T1 from the cache")
+	}
+
+	if got := teamCacheName("/cache/channels_cache_v2.json", "T3"); got != "/cache/channels_cache_v2_T3.json" {
+		t.Errorf("expected the version suffix to be kept, got %q", got)
+	}
+}
+
+func TestForceRefresh_BypassesCacheFiles(t *testing.T) {
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users.list":
+			fetches++
+			_, _ = w.Write([]byte(`{"ok": true, "members": [{"id": "U1", "name": "alice"}, {"id": "U2", "name": "bob"}]}`))
+		case "/conversations.list":
+			fetches++
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C2", "name": "new", "name_normalized": "new", "is_channel": true}], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ap, _ := newTestProvider(0)
+	ap.usersCache = filepath.Join(dir, "users_cache.json")
+	ap.channelsCache = filepath.Join(dir, "channels_cache.json")
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	if err := os.WriteFile(ap.usersCache, []byte(`{"schema_version": 1, "entries": [{"id": "U1", "name": "alice"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ap.channelsCache, []byte(`{"schema_version": 1, "entries": [{"id": "C1", "name": "#general"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := ap.RefreshUsers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ap.RefreshChannels(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fetches != 0 {
+		t.Fatalf("expected the cache files to be served, got %d fetches", fetches)
+	}
+
+	if err := ap.ForceRefreshUsers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ap.ForceRefreshChannels(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := ap.ResolveUser("U2"); !ok {
+		t.Error("expected the user who joined to be cached")
+	}
+	if _, ok := ap.ProvideChannelsMaps().Channels["C2"]; !ok {
+		t.Error("expected the new channel to be cached")
+	}
+	if _, ok := ap.ProvideChannelsMaps().Channels["C1"]; ok {
+		t.Error("expected the channel Slack no longer lists to be dropped")
+	}
+	if _, err := ap.ResolveChannelID("#general", false); err == nil {
+		t.Error("expected the dropped channel to stop resolving by name")
+	}
+
+	data, err := os.ReadFile(ap.usersCache)
+	if err != nil || !strings.Contains(string(data), `"U2"`) {
+		t.Errorf("expected the users cache file to be rewritten, got %s (%v)", data, err)
+	}
+	data, err = os.ReadFile(ap.channelsCache)
+	if err != nil || !strings.Contains(string(data), `"C2"`) || strings.Contains(string(data), `"C1"`) {
+		t.Errorf("expected the channels cache file to be rewritten without C1, got %s (%v)", data, err)
+	}
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// The schema versions of the users and channels cache files. Bump one when
+// what is cached changes, e.g. a field is added to Channel, so that files
+// written before are refetched rather than loaded with zero values.
+const (
+	usersCacheVersion    = 1
+	channelsCacheVersion = 1
+)
+
+// versionedCache is the layout of the users and channels cache files.
+type versionedCache[T any] struct {
+	SchemaVersion int `json:"schema_version"`
+	Entries       []T `json:"entries"`
+}
+
+// decodeCache decodes a cache file and returns its schema version. Files
+// written before the version was embedded hold a bare list, version 0.
+func decodeCache[T any](data []byte) ([]T, int, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var entries []T
+		err := json.Unmarshal(trimmed, &entries)
+		return entries, 0, err
+	}
+
+	var c versionedCache[T]
+	err := json.Unmarshal(data, &c)
+	return c.Entries, c.SchemaVersion, err
+}
+
+// encodeCache encodes entries as a cache file of the given schema version.
+func encodeCache[T any](version int, entries []T) ([]byte, error) {
+	return json.MarshalIndent(versionedCache[T]{SchemaVersion: version, Entries: entries}, "", "  ")
+}
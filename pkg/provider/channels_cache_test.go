@@ -0,0 +1,403 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	slack2 "github.com/rusq/slack"
+	"github.com/rusq/slackdump/v3/auth"
+	"github.com/slack-go/slack"
+)
+
+func TestRefreshChannels_CachesArchivedAndCreator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.list" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("exclude_archived") == "true" {
+			t.Errorf("expected archived channels to be fetched")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"channels": [
+				{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true, "num_members": 10},
+				{"id": "C2", "name": "old-project", "name_normalized": "old-project", "is_channel": true, "is_archived": true, "num_members": 3, "created": 1600000000, "creator": "U1"}
+			],
+			"response_metadata": {"next_cursor": ""}
+		}`))
+	}))
+	defer srv.Close()
+
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = cache
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, err := ap.GetChannels(context.Background(), []string{"public_channel"}); err != nil || len(got) != 1 || got[0].ID != "C1" {
+		t.Errorf("expected archived channels to be left out by default, got %+v, %v", got, err)
+	}
+	got, err := ap.GetChannels(context.Background(), []string{"public_channel", ArchivedChanType})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected archived channels when requested, got %+v", got)
+	}
+
+	// the flag must survive a reload from the cache file
+	reloaded, _ := newTestProvider(0)
+	reloaded.channelsCache = cache
+	if err := reloaded.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := reloaded.channels["C2"]; !c.IsArchived || c.Name != "#old-project" {
+		t.Errorf("expected C2 to be loaded as archived, got %+v", c)
+	}
+	if c := reloaded.channels["C2"]; c.Created != 1600000000 || c.Creator != "U1" {
+		t.Errorf("expected C2 creation time and creator to be cached, got %+v", c)
+	}
+	if c := reloaded.channels["C1"]; c.IsArchived {
+		t.Errorf("expected C1 not to be archived, got %+v", c)
+	}
+}
+
+func TestGetChannels_MemberOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"channels": [
+				{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true, "is_member": true},
+				{"id": "C2", "name": "random", "name_normalized": "random", "is_channel": true},
+				{"id": "D1", "is_im": true, "user": "U1"}
+			],
+			"response_metadata": {"next_cursor": ""}
+		}`))
+	}))
+	defer srv.Close()
+
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = cache
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ap.GetChannels(context.Background(), []string{"public_channel", MemberChanType})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, c := range got {
+		if c.ID == "C2" {
+			t.Errorf("expected C2, which the user is not in, to be left out, got %+v", c)
+		}
+	}
+	if !ap.channels["D1"].IsMember {
+		t.Errorf("expected a DM to count as joined, got %+v", ap.channels["D1"])
+	}
+
+	reloaded, _ := newTestProvider(0)
+	reloaded.channelsCache = cache
+	if err := reloaded.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reloaded.channels["C1"].IsMember || reloaded.channels["C2"].IsMember {
+		t.Errorf("expected membership to be cached, got %+v", reloaded.channels)
+	}
+}
+
+func TestRefreshChannels_CachesSharedFlags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"channels": [
+				{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true},
+				{"id": "C2", "name": "ext-acme", "name_normalized": "ext-acme", "is_channel": true, "is_shared": true, "is_ext_shared": true},
+				{"id": "C3", "name": "ext-pending", "name_normalized": "ext-pending", "is_channel": true, "is_pending_ext_shared": true}
+			],
+			"response_metadata": {"next_cursor": ""}
+		}`))
+	}))
+	defer srv.Close()
+
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = cache
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, _ := newTestProvider(0)
+	reloaded.channelsCache = cache
+	if err := reloaded.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c := reloaded.channels["C1"]; c.IsShared || c.IsExtShared || c.IsPendingExtShared {
+		t.Errorf("expected C1 not to be shared, got %+v", c)
+	}
+	if c := reloaded.channels["C2"]; !c.IsShared || !c.IsExtShared || c.IsPendingExtShared {
+		t.Errorf("expected C2 to be shared externally, got %+v", c)
+	}
+	if c := reloaded.channels["C3"]; c.IsShared || c.IsExtShared || !c.IsPendingExtShared {
+		t.Errorf("expected C3 to be pending external sharing, got %+v", c)
+	}
+}
+
+func TestGetChannels_PagesCachedOnce(t *testing.T) {
+	var ap *ApiProvider
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true}], "response_metadata": {"next_cursor": "page2"}}`))
+			return
+		}
+
+		// C1 is joined while the second page is fetched, caching page 1
+		// again would revert that
+		ap.cacheChannel(Channel{ID: "C1", Name: "#general", IsMember: true})
+		_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C2", "name": "random", "name_normalized": "random", "is_channel": true}], "response_metadata": {"next_cursor": ""}}`))
+	}))
+	defer srv.Close()
+
+	ap, _ = newTestProvider(0)
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	got, err := ap.GetChannels(context.Background(), []string{"public_channel"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 2 || len(got) != 2 {
+		t.Fatalf("expected 2 channels from 2 pages, got %d from %d", len(got), calls)
+	}
+	if !ap.channels["C1"].IsMember {
+		t.Errorf("expected page 1 not to be cached again with page 2, got %+v", ap.channels["C1"])
+	}
+}
+
+func TestGetChannels_EnterprisePages(t *testing.T) {
+	var boots int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/client.userBoot":
+			boots++
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		case "/api/im.list":
+			_, _ = w.Write([]byte(`{"ok": true, "ims": []}`))
+		case "/api/search.modules.channels":
+			if r.Form.Get("cursor") == "page2" {
+				_, _ = w.Write([]byte(`{"ok": true, "items": [{"id": "C2", "name": "random", "name_normalized": "random", "member_count": 3}], "pagination": {}}`))
+			} else {
+				_, _ = w.Write([]byte(`{"ok": true, "items": [{"id": "C1", "name": "general", "name_normalized": "general", "member_count": 10}], "pagination": {"next_cursor": "page2"}}`))
+			}
+		case "/api/client.counts":
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		case "/api/conversations.genericInfo":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": []}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	authProvider, err := auth.NewValueAuth("xoxc-test", "xoxd-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &slack2.AuthTestResponse{URL: srv.URL + "/", TeamID: "T1", EnterpriseID: "E1"}
+	clientE, err := edge.NewWithInfo(info, authProvider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ap, _ := newTestProvider(0)
+	ap.authResponse = info
+	ap.clientGeneric = slack.New("xoxc-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = clientE
+
+	got, err := ap.GetChannels(context.Background(), []string{"public_channel"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := map[string]bool{}
+	for _, c := range got {
+		ids[c.ID] = true
+	}
+	if len(got) != 2 || !ids["C1"] || !ids["C2"] {
+		t.Errorf("expected the channels of both pages, got %+v", got)
+	}
+	if boots != 1 {
+		t.Errorf("expected the listing to terminate after one round, got %d", boots)
+	}
+}
+
+// cancelAfterResponse cancels once a whole response was read, like an MCP
+// client giving up while a page is being processed.
+type cancelAfterResponse struct {
+	cancel context.CancelFunc
+}
+
+func (c cancelAfterResponse) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	c.cancel()
+	return res, nil
+}
+
+func TestGetChannels_CancelledAfterFirstPage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true}], "response_metadata": {"next_cursor": "page2"}}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test",
+		slack.OptionAPIURL(srv.URL+"/"),
+		slack.OptionHTTPClient(&http.Client{Transport: cancelAfterResponse{cancel}}),
+	)
+	ap.clientEnterprise = &edge.Client{}
+
+	if _, err := ap.GetChannels(ctx, []string{"public_channel"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancellation to be returned, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected no page to be fetched after the cancellation, got %d requests", calls)
+	}
+	if _, ok := ap.channels["C1"]; !ok {
+		t.Errorf("expected the first page to stay cached, got %+v", ap.channels)
+	}
+}
+
+func TestRefreshChannels_ReturnsFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope"}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = filepath.Join(t.TempDir(), "channels_cache.json")
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	if _, err := ap.GetChannels(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "missing_scope") {
+		t.Errorf("expected the Slack error, got %v", err)
+	}
+
+	if err := ap.RefreshChannels(context.Background()); err == nil {
+		t.Fatal("expected the refresh to fail")
+	}
+	if _, err := os.Stat(ap.channelsCache); !os.IsNotExist(err) {
+		t.Errorf("expected no cache file to be written, got %v", err)
+	}
+}
+
+func TestRefreshChannels_MigratesOutdatedCache(t *testing.T) {
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true, "is_archived": true, "created": 1500000000}], "response_metadata": {"next_cursor": ""}}`))
+	}))
+	defer srv.Close()
+
+	// written before the schema version was embedded, without the archived
+	// flag or the creation time
+	const outdated = `[{"id": "C1", "name": "#general"}]`
+
+	newProvider := func(cache string) *ApiProvider {
+		ap, _ := newTestProvider(0)
+		ap.channelsCache = cache
+		ap.authResponse = &slack2.AuthTestResponse{}
+		ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+		ap.clientEnterprise = &edge.Client{}
+		return ap
+	}
+
+	cache := writeFixture(t, "channels_cache.json", outdated)
+	ap := newProvider(cache)
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := ap.channels["C1"]; !c.IsArchived || c.Created != 1500000000 {
+		t.Errorf("expected the outdated cache to be refetched, got %+v", c)
+	}
+
+	data, err := os.ReadFile(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channels, version, err := decodeCache[Channel](data)
+	if err != nil || version != channelsCacheVersion || len(channels) != 1 || !channels[0].IsArchived {
+		t.Errorf("expected the cache to be rewritten at version %d, got version %d: %s (%v)", channelsCacheVersion, version, data, err)
+	}
+
+	// the outdated entries are served while the refetch fails
+	up = false
+	ap = newProvider(writeFixture(t, "channels_cache.json", outdated))
+	if err := ap.RefreshChannels(context.Background()); err == nil {
+		t.Fatal("expected the refetch to fail")
+	}
+	if err := ap.Available(SubsystemChannels); err != nil {
+		t.Errorf("expected the outdated channels to be served, got %v", err)
+	}
+}
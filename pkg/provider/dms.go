@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// slackbotUserID is the fixed user ID of Slackbot in every workspace.
+const slackbotUserID = "USLACKBOT"
+
+// GetAppDMChannels returns the IM channels held with an app rather than a
+// person. For bot tokens every IM is the app's own "Messages" tab, for user
+// tokens these are the IMs whose counterpart is a bot or app user.
+func (ap *ApiProvider) GetAppDMChannels(ctx context.Context) ([]Channel, error) {
+	if len(ap.ProvideChannelsMaps().Channels) == 0 {
+		// GetChannels fills the channels cache as a side effect
+		if _, err := ap.GetChannels(ctx, []string{"im"}); err != nil {
+			return nil, err
+		}
+	}
+	channels := ap.ProvideChannelsMaps().Channels
+
+	var ims []Channel
+	var userIDs []string
+	for _, c := range channels {
+		if c.IsIM {
+			ims = append(ims, c)
+			userIDs = append(userIDs, c.User)
+		}
+	}
+	users := ap.ResolveUsers(userIDs)
+
+	isBot := ap.IsBotToken()
+	var res []Channel
+	for _, c := range ims {
+		u, ok := users[c.User]
+		if isBot || c.User == slackbotUserID || (ok && (u.IsBot || u.IsAppUser)) {
+			res = append(res, c)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res, nil
+}
+
+// GetMPIMsWithUser returns the group DMs the user referenced by userRef, an
+// ID or a username with or without "@", takes part in. Group DMs cached
+// without their members are enriched via conversations.members first.
+func (ap *ApiProvider) GetMPIMsWithUser(ctx context.Context, userRef string) ([]Channel, error) {
+	userID, err := ap.resolveUserRef(userRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ap.ProvideChannelsMaps().Channels) == 0 {
+		// GetChannels fills the channels cache as a side effect
+		if _, err := ap.GetChannels(ctx, []string{"mpim"}); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []Channel
+	for _, c := range ap.ProvideChannelsMaps().Channels {
+		if !c.IsMpIM {
+			continue
+		}
+
+		if len(c.Members) == 0 {
+			members, err := ap.conversationMembers(ctx, c.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch members of %s: %w", c.ID, err)
+			}
+			c.Members = members
+
+			ap.channelsMu.Lock()
+			ap.channels[c.ID] = c
+			ap.channelsMu.Unlock()
+		}
+
+		if slices.Contains(c.Members, userID) {
+			res = append(res, c)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res, nil
+}
+
+// resolveUserRef converts a user ID or username to the user ID.
+func (ap *ApiProvider) resolveUserRef(ref string) (string, error) {
+	if err := ap.Available(SubsystemUsers); err != nil {
+		return "", err
+	}
+
+	ap.usersMu.RLock()
+	defer ap.usersMu.RUnlock()
+
+	if _, ok := ap.users[ref]; ok {
+		return ref, nil
+	}
+	if id, ok := ap.usersInv[strings.TrimPrefix(ref, "@")]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("user %q not found", ref)
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// dmsTestUsers and dmsTestChannels are the DMs of a user with people, a
+// bot, a workflow and Slackbot.
+var (
+	dmsTestUsers = []slack.User{
+		{ID: "U1", Name: "alice"},
+		{ID: "U2", Name: "bob"},
+		{ID: "B1", Name: "deploybot", IsBot: true},
+		{ID: "A1", Name: "workflow", IsAppUser: true},
+	}
+	dmsTestChannels = []Channel{
+		{ID: "D1", Name: "@alice", IsIM: true, User: "U1"},
+		{ID: "D2", Name: "@deploybot", IsIM: true, User: "B1"},
+		{ID: "D3", Name: "@workflow", IsIM: true, User: "A1"},
+		{ID: "D4", Name: "@slackbot", IsIM: true, User: slackbotUserID},
+		{ID: "G1", Name: "@mpdm-alice--bob-1", IsMpIM: true, Members: []string{"U1", "U2", "B1"}},
+		{ID: "C1", Name: "#general"},
+	}
+)
+
+func TestGetAppDMChannels_UserToken(t *testing.T) {
+	ap, _ := newTestProvider(0, withUsers(dmsTestUsers...), withChannels(dmsTestChannels...))
+
+	channels, err := ap.GetAppDMChannels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, c := range channels {
+		ids = append(ids, c.ID)
+	}
+	if len(ids) != 3 || ids[0] != "D2" || ids[1] != "D3" || ids[2] != "D4" {
+		t.Errorf("expected app DMs [D2 D3 D4], got %v", ids)
+	}
+}
+
+func TestGetAppDMChannels_BotToken(t *testing.T) {
+	ap, _ := newTestProvider(0, withUsers(dmsTestUsers...), withChannels(dmsTestChannels...))
+	ap.isBotToken = true
+
+	channels, err := ap.GetAppDMChannels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// every IM of a bot token is a conversation with the app itself
+	if len(channels) != 4 {
+		t.Errorf("expected all 4 IMs for a bot token, got %d", len(channels))
+	}
+	for _, c := range channels {
+		if !c.IsIM {
+			t.Errorf("expected only IM channels, got %+v", c)
+		}
+	}
+}
+
+func TestGetMPIMsWithUser(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.members" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("channel") != "G3" {
+			t.Errorf("expected members of G3 only to be fetched, got %q", r.Form.Get("channel"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "members": ["U2", "B1", "A1"], "response_metadata": {"next_cursor": ""}}`))
+	})
+
+	ap, _ := newTestProvider(0,
+		withUsers(dmsTestUsers...),
+		withChannels(dmsTestChannels...),
+		withChannels(
+			Channel{ID: "G2", Name: "@mpdm-alice--deploybot-1", IsMpIM: true, Members: []string{"U1", "B1"}},
+			Channel{ID: "G3", Name: "@mpdm-bob--deploybot--workflow-1", IsMpIM: true},
+		),
+		withWebAPI(t, handler),
+	)
+
+	tests := []struct {
+		ref      string
+		expected []string
+	}{
+		{ref: "U1", expected: []string{"G1", "G2"}},
+		{ref: "@bob", expected: []string{"G1", "G3"}},
+		{ref: "A1", expected: []string{"G3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			channels, err := ap.GetMPIMsWithUser(context.Background(), tt.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ids []string
+			for _, c := range channels {
+				ids = append(ids, c.ID)
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+
+	if _, err := ap.GetMPIMsWithUser(context.Background(), "@nobody"); err == nil {
+		t.Error("expected an error for an unknown user")
+	}
+}
@@ -0,0 +1,85 @@
+package edge
+
+import (
+	"context"
+	"runtime/trace"
+	"strconv"
+	"strings"
+)
+
+// admin.* API
+
+// AdminConversation is a channel as returned by admin.conversations.search.
+type AdminConversation struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Purpose         string   `json:"purpose"`
+	MemberCount     int      `json:"member_count"`
+	Created         int64    `json:"created"`
+	CreatorID       string   `json:"creator_id"`
+	IsPrivate       bool     `json:"is_private"`
+	IsArchived      bool     `json:"is_archived"`
+	IsExtShared     bool     `json:"is_ext_shared"`
+	InternalTeamIDs []string `json:"internal_team_ids"`
+}
+
+// AdminSearchParams are the parameters of admin.conversations.search.
+type AdminSearchParams struct {
+	Query        string
+	ChannelTypes []string // i.e. "private", "public", "archived"
+	TeamIDs      []string
+	Limit        int
+	Cursor       string
+}
+
+type adminConversationsSearchResponse struct {
+	baseResponse
+	Conversations []AdminConversation `json:"conversations"`
+	NextCursor    string              `json:"next_cursor"`
+}
+
+// AdminConversationsSearch searches channels across the organisation,
+// including those the user is not a member of.  It requires an Enterprise
+// Grid token with the admin.conversations:read scope.
+func (cl *Client) AdminConversationsSearch(ctx context.Context, p AdminSearchParams) ([]AdminConversation, string, error) {
+	ctx, task := trace.NewTask(ctx, "AdminConversationsSearch")
+	defer task.End()
+	trace.Logf(ctx, "params", "query=%q cursor=%q", p.Query, p.Cursor)
+
+	form := values(struct {
+		BaseRequest
+		Query              string `json:"query,omitempty"`
+		SearchChannelTypes string `json:"search_channel_types,omitempty"`
+		TeamIDs            string `json:"team_ids,omitempty"`
+		Limit              string `json:"limit,omitempty"`
+		Cursor             string `json:"cursor,omitempty"`
+	}{
+		BaseRequest:        BaseRequest{Token: cl.token},
+		Query:              p.Query,
+		SearchChannelTypes: strings.Join(p.ChannelTypes, ","),
+		TeamIDs:            strings.Join(p.TeamIDs, ","),
+		Limit:              limitString(p.Limit),
+		Cursor:             p.Cursor,
+	}, true)
+
+	const ep = "admin.conversations.search"
+	resp, err := cl.PostForm(ctx, ep, form)
+	if err != nil {
+		return nil, "", err
+	}
+	var r adminConversationsSearchResponse
+	if err := cl.ParseResponse(&r, resp); err != nil {
+		return nil, "", err
+	}
+	if err := r.validate(ep); err != nil {
+		return nil, "", err
+	}
+	return r.Conversations, r.NextCursor, nil
+}
+
+func limitString(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
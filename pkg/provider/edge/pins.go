@@ -0,0 +1,102 @@
+package edge
+
+import (
+	"context"
+	"runtime/trace"
+
+	"github.com/rusq/slack"
+)
+
+// pins.* and stars.* API
+//
+// slack-go drops who pinned or starred an item and when, these calls keep it.
+
+// PinnedItem is an item of pins.list.
+type PinnedItem struct {
+	Type      string         `json:"type"`
+	Channel   string         `json:"channel"`
+	Created   int64          `json:"created"`
+	CreatedBy string         `json:"created_by"`
+	Message   *slack.Message `json:"message,omitempty"`
+	File      *slack.File    `json:"file,omitempty"`
+}
+
+type pinsListResponse struct {
+	baseResponse
+	Items []PinnedItem `json:"items"`
+}
+
+// PinsList returns the items pinned to a channel.
+func (cl *Client) PinsList(ctx context.Context, channelID string) ([]PinnedItem, error) {
+	ctx, task := trace.NewTask(ctx, "PinsList")
+	defer task.End()
+	trace.Logf(ctx, "params", "channelID=%q", channelID)
+
+	form := values(struct {
+		BaseRequest
+		Channel string `json:"channel"`
+	}{
+		BaseRequest: BaseRequest{Token: cl.token},
+		Channel:     channelID,
+	}, true)
+
+	const ep = "pins.list"
+	resp, err := cl.PostForm(ctx, ep, form)
+	if err != nil {
+		return nil, err
+	}
+	var r pinsListResponse
+	if err := cl.ParseResponse(&r, resp); err != nil {
+		return nil, err
+	}
+	if err := r.validate(ep); err != nil {
+		return nil, err
+	}
+	return r.Items, nil
+}
+
+// StarredItem is an item of stars.list, the saved items of the user.
+type StarredItem struct {
+	Type       string         `json:"type"`
+	Channel    string         `json:"channel"`
+	DateCreate int64          `json:"date_create"`
+	Message    *slack.Message `json:"message,omitempty"`
+	File       *slack.File    `json:"file,omitempty"`
+}
+
+type starsListResponse struct {
+	baseResponse
+	Items []StarredItem `json:"items"`
+}
+
+// StarsList returns a page of the items starred by the user, it is not
+// available to bot tokens.
+func (cl *Client) StarsList(ctx context.Context, cursor string, limit int) ([]StarredItem, string, error) {
+	ctx, task := trace.NewTask(ctx, "StarsList")
+	defer task.End()
+	trace.Logf(ctx, "params", "cursor=%q limit=%d", cursor, limit)
+
+	form := values(struct {
+		BaseRequest
+		Cursor string `json:"cursor,omitempty"`
+		Limit  string `json:"limit,omitempty"`
+	}{
+		BaseRequest: BaseRequest{Token: cl.token},
+		Cursor:      cursor,
+		Limit:       limitString(limit),
+	}, true)
+
+	const ep = "stars.list"
+	resp, err := cl.PostForm(ctx, ep, form)
+	if err != nil {
+		return nil, "", err
+	}
+	var r starsListResponse
+	if err := cl.ParseResponse(&r, resp); err != nil {
+		return nil, "", err
+	}
+	if err := r.validate(ep); err != nil {
+		return nil, "", err
+	}
+	return r.Items, r.ResponseMetadata.NextCursor, nil
+}
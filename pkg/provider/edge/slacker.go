@@ -13,6 +13,8 @@ var ErrParameterMissing = errors.New("required parameter missing")
 // High level functions that wrap low level calls to webclient API to return
 // the data in the format close to the Slack API.
 
+// GetConversationsContext returns all conversations of the user. It pages
+// through each source itself, so the returned cursor is always empty.
 func (cl *Client) GetConversationsContext(ctx context.Context, _ *slack.GetConversationsParameters) (channels []slack.Channel, _ string, err error) {
 	type result struct {
 		Channels []slack.Channel
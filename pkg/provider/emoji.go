@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultEmojiCacheTTL = 24 * time.Hour
+	emojiAliasPrefix     = "alias:"
+	maxEmojiAliasDepth   = 8
+)
+
+// emojiCachePath returns the file the custom emoji list is persisted to.
+func emojiCachePath() string {
+	if path := os.Getenv("SLACK_MCP_EMOJI_CACHE"); path != "" {
+		return path
+	}
+	return filepath.Join(getCacheDir(), "emoji_cache.json")
+}
+
+// emojiCacheTTL returns how long the persisted emoji list stays fresh,
+// configured with SLACK_MCP_EMOJI_CACHE_TTL as a Go duration.
+func emojiCacheTTL() time.Duration {
+	v := os.Getenv("SLACK_MCP_EMOJI_CACHE_TTL")
+	if v == "" {
+		return defaultEmojiCacheTTL
+	}
+
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl < 0 {
+		log.Printf("Invalid SLACK_MCP_EMOJI_CACHE_TTL %q, using %s", v, defaultEmojiCacheTTL)
+		return defaultEmojiCacheTTL
+	}
+	return ttl
+}
+
+// RefreshEmoji loads the workspace's custom emoji, mapping each name to its
+// image URL or to "alias:<name>". The list is read from the cache file while
+// it is younger than the TTL, otherwise it is fetched with emoji.list and
+// written back. In memory cache mode it is always fetched.
+func (ap *ApiProvider) RefreshEmoji(ctx context.Context) error {
+	memory := memoryCacheMode() && !ap.offline
+	var path string
+	if !memory {
+		path = emojiCachePath()
+	}
+
+	if fi, err := os.Stat(path); !memory && err == nil && (ap.offline || time.Since(fi.ModTime()) < emojiCacheTTL()) {
+		if data, err := os.ReadFile(path); err == nil {
+			var cached map[string]string
+			if err := json.Unmarshal(data, &cached); err != nil {
+				log.Printf("Failed to unmarshal %s: %v; will refetch", path, err)
+			} else {
+				ap.setEmoji(cached)
+				log.Printf("Loaded %d custom emoji from cache %q", len(cached), path)
+				return nil
+			}
+		}
+	}
+
+	if ap.offline {
+		return fmt.Errorf("%w: emoji cache %q could not be loaded", ErrOffline, path)
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return err
+	}
+
+	emoji, err := client.GetEmojiContext(ctx)
+	if err != nil {
+		log.Printf("Failed to fetch emoji: %v", err)
+		return err
+	}
+	ap.setEmoji(emoji)
+
+	if memory {
+		log.Printf("Cached %d custom emoji in memory", len(emoji))
+	} else if data, err := json.MarshalIndent(emoji, "", "  "); err != nil {
+		log.Printf("Failed to marshal emoji for cache: %v", err)
+	} else {
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			log.Printf("Failed to write cache file %q: %v", path, err)
+		} else {
+			log.Printf("Wrote %d custom emoji to cache %q", len(emoji), path)
+		}
+	}
+
+	return nil
+}
+
+func (ap *ApiProvider) setEmoji(emoji map[string]string) {
+	ap.emojiMu.Lock()
+	defer ap.emojiMu.Unlock()
+
+	ap.emoji = emoji
+}
+
+// GetEmojiURL returns the image URL of a custom emoji, name may be wrapped
+// in colons. Aliases are followed to the emoji they point to; aliases of
+// standard emoji have no image and report false.
+func (ap *ApiProvider) GetEmojiURL(name string) (string, bool) {
+	ap.emojiMu.RLock()
+	defer ap.emojiMu.RUnlock()
+
+	name = strings.Trim(name, ":")
+	for range maxEmojiAliasDepth {
+		v, ok := ap.emoji[name]
+		if !ok {
+			return "", false
+		}
+		target, isAlias := strings.CutPrefix(v, emojiAliasPrefix)
+		if !isAlias {
+			return v, true
+		}
+		name = target
+	}
+
+	// an alias cycle, or a chain too long to be legitimate
+	return "", false
+}
+
+// Emoji is a custom emoji. For aliases AliasFor names the emoji pointed to
+// and URL is the image it resolves to, empty for aliases of standard emoji.
+type Emoji struct {
+	Name     string
+	URL      string
+	AliasFor string
+}
+
+// ListEmoji returns the custom emoji sorted by name, loading them first if
+// the emoji watcher has not done so yet.
+func (ap *ApiProvider) ListEmoji(ctx context.Context) ([]Emoji, error) {
+	ap.emojiMu.RLock()
+	loaded := ap.emoji != nil
+	ap.emojiMu.RUnlock()
+
+	if !loaded {
+		if err := ap.RefreshEmoji(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ap.emojiMu.RLock()
+	names := make([]string, 0, len(ap.emoji))
+	aliases := make(map[string]string)
+	for name, v := range ap.emoji {
+		names = append(names, name)
+		if target, ok := strings.CutPrefix(v, emojiAliasPrefix); ok {
+			aliases[name] = target
+		}
+	}
+	ap.emojiMu.RUnlock()
+	sort.Strings(names)
+
+	res := make([]Emoji, 0, len(names))
+	for _, name := range names {
+		url, _ := ap.GetEmojiURL(name)
+		res = append(res, Emoji{Name: name, URL: url, AliasFor: aliases[name]})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestGetEmojiURL(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	ap.setEmoji(map[string]string{
+		"partyparrot": "https://emoji.slack-edge.com/T1/partyparrot/abc.gif",
+		"parrot":      "alias:partyparrot",
+		"pp":          "alias:parrot",
+		"yay":         "alias:tada",
+		"loop-a":      "alias:loop-b",
+		"loop-b":      "alias:loop-a",
+	})
+
+	tests := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{name: "partyparrot", expected: "https://emoji.slack-edge.com/T1/partyparrot/abc.gif", ok: true},
+		{name: ":parrot:", expected: "https://emoji.slack-edge.com/T1/partyparrot/abc.gif", ok: true},
+		{name: "pp", expected: "https://emoji.slack-edge.com/T1/partyparrot/abc.gif", ok: true},
+		{name: "yay", ok: false},    // alias of a standard emoji
+		{name: "loop-a", ok: false}, // alias cycle
+		{name: "unknown", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := ap.GetEmojiURL(tt.name)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("GetEmojiURL(%q) = %q, %v, expected %q, %v", tt.name, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestRefreshEmoji_CacheTTL(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "emoji": {"shipit": "https://emoji.slack-edge.com/T1/shipit/1.png", "ship": "alias:shipit"}}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "emoji.json")
+	t.Setenv("SLACK_MCP_EMOJI_CACHE", path)
+	t.Setenv("SLACK_MCP_EMOJI_CACHE_TTL", "1h")
+
+	ap, _ := newTestProvider(0)
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	if err := ap.RefreshEmoji(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url, ok := ap.GetEmojiURL("ship"); !ok || url != "https://emoji.slack-edge.com/T1/shipit/1.png" {
+		t.Errorf("unexpected alias resolution %q, %v", url, ok)
+	}
+
+	// a fresh cache file is used as is
+	if err := ap.RefreshEmoji(context.Background()); err != nil || calls != 1 {
+		t.Fatalf("expected the cache to be used, got %d calls, %v", calls, err)
+	}
+
+	// an expired one is refetched
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := ap.RefreshEmoji(context.Background()); err != nil || calls != 2 {
+		t.Fatalf("expected the expired cache to be refetched, got %d calls, %v", calls, err)
+	}
+}
+
+func TestListEmoji_LoadsOnFirstUse(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "emoji": {"shipit": "https://emoji.slack-edge.com/T1/shipit/1.png", "ship": "alias:shipit", "yay": "alias:tada"}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("SLACK_MCP_EMOJI_CACHE", filepath.Join(t.TempDir(), "emoji.json"))
+
+	ap, _ := newTestProvider(0)
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	got, err := ap.ListEmoji(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Emoji{
+		{Name: "ship", URL: "https://emoji.slack-edge.com/T1/shipit/1.png", AliasFor: "shipit"},
+		{Name: "shipit", URL: "https://emoji.slack-edge.com/T1/shipit/1.png"},
+		{Name: "yay", AliasFor: "tada"},
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("ListEmoji() = %+v, expected %+v", got, expected)
+	}
+
+	// loaded emoji are not fetched again
+	if _, err := ap.ListEmoji(context.Background()); err != nil || calls != 1 {
+		t.Errorf("expected a single emoji.list call, got %d, %v", calls, err)
+	}
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	// filesPageSize is the files.list page size, the most Slack accepts.
+	filesPageSize = 200
+	// defaultMaxFileBytes caps downloads unless SLACK_MCP_MAX_FILE_BYTES
+	// says otherwise.
+	defaultMaxFileBytes = 10 << 20
+)
+
+// ErrFileTooLarge is returned for downloads exceeding SLACK_MCP_MAX_FILE_BYTES.
+var ErrFileTooLarge = errors.New("file exceeds the download size limit")
+
+// maxFileBytes returns how many bytes a file download may hold, read from
+// SLACK_MCP_MAX_FILE_BYTES.
+func maxFileBytes() int64 {
+	v := os.Getenv("SLACK_MCP_MAX_FILE_BYTES")
+	if v == "" {
+		return defaultMaxFileBytes
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid SLACK_MCP_MAX_FILE_BYTES %q, using %d", v, defaultMaxFileBytes)
+		return defaultMaxFileBytes
+	}
+	return n
+}
+
+// FileInfo is the metadata of a file shared in the workspace.
+type FileInfo struct {
+	ID        string
+	Name      string
+	Title     string
+	Mimetype  string
+	Size      int
+	Permalink string
+	UserID    string
+	Channels  []string
+	Created   time.Time
+}
+
+// FilesOptions narrow a ListFiles query, empty fields don't filter.
+type FilesOptions struct {
+	Channel string // channel ID or name with or without its prefix
+	User    string // user ID or username with or without "@"
+	Limit   int    // maximum number of files returned, 0 means 100
+}
+
+// ListFiles returns the files visible to the token, newest first, paging
+// through files.list at the configured rate limit tier. It returns
+// ErrScopeMissing when the token lacks files:read.
+func (ap *ApiProvider) ListFiles(ctx context.Context, opts FilesOptions) ([]FileInfo, error) {
+	client, err := ap.filesClient("files.list")
+	if err != nil {
+		return nil, err
+	}
+
+	params := slack.ListFilesParameters{Limit: filesPageSize}
+	if opts.Channel != "" {
+		if params.Channel, err = ap.ResolveChannelID(opts.Channel, false); err != nil {
+			return nil, err
+		}
+	}
+	if opts.User != "" {
+		if params.User, err = ap.resolveUserRef(opts.User); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	params.Limit = min(limit, filesPageSize)
+
+	lim := ap.rateTier.Limiter()
+
+	var files []FileInfo
+	for {
+		page, next, err := client.ListFilesContext(ctx, params)
+		if err != nil {
+			return nil, scopeError("files.list", err)
+		}
+		for _, f := range page {
+			files = append(files, fileInfo(f))
+		}
+
+		if len(files) >= limit {
+			return files[:limit], nil
+		}
+		if next == nil || next.Cursor == "" || len(page) == 0 {
+			return files, nil
+		}
+		params.Cursor = next.Cursor
+
+		if err := lim.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GetFileInfo returns the metadata of a single file.
+func (ap *ApiProvider) GetFileInfo(ctx context.Context, fileID string) (FileInfo, error) {
+	client, err := ap.filesClient("files.info")
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	f, _, _, err := client.GetFileInfoContext(ctx, fileID, 0, 0)
+	if err != nil {
+		return FileInfo{}, scopeError("files.info", err)
+	}
+
+	return fileInfo(*f), nil
+}
+
+// DownloadFile writes the contents of a file to w through the booted client,
+// so the token and, for session tokens, the cookies, CA and proxy apply.
+// Files larger than SLACK_MCP_MAX_FILE_BYTES fail with ErrFileTooLarge,
+// before the download when files.info reports the size and otherwise as
+// soon as the limit is crossed; w may have been written to then.
+func (ap *ApiProvider) DownloadFile(ctx context.Context, fileID string, w io.Writer) (FileInfo, error) {
+	client, err := ap.filesClient("files.info")
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	f, _, _, err := client.GetFileInfoContext(ctx, fileID, 0, 0)
+	if err != nil {
+		return FileInfo{}, scopeError("files.info", err)
+	}
+
+	limit := maxFileBytes()
+	if int64(f.Size) > limit {
+		return FileInfo{}, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrFileTooLarge, fileID, f.Size, limit)
+	}
+
+	url := f.URLPrivateDownload
+	if url == "" {
+		url = f.URLPrivate
+	}
+	if err := client.GetFileContext(ctx, url, &limitedWriter{w: w, n: limit}); err != nil {
+		if errors.Is(err, ErrFileTooLarge) {
+			return FileInfo{}, fmt.Errorf("%w: %s is over %d bytes", ErrFileTooLarge, fileID, limit)
+		}
+		return FileInfo{}, err
+	}
+
+	return fileInfo(*f), nil
+}
+
+// limitedWriter fails with ErrFileTooLarge once more than n bytes are
+// written, unlike io.LimitedReader which would truncate silently.
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > lw.n {
+		return 0, ErrFileTooLarge
+	}
+	n, err := lw.w.Write(p)
+	lw.n -= int64(n)
+	return n, err
+}
+
+// filesClient boots the client for method and fails early when the token is
+// known to lack files:read, which bot tokens are often not granted.
+func (ap *ApiProvider) filesClient(method string) (*slack.Client, error) {
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ap.HasScope("files:read") {
+		if ap.IsBotToken() {
+			return nil, fmt.Errorf("%w: %s needs files:read, add it to the bot token scopes and reinstall the app", ErrScopeMissing, method)
+		}
+		return nil, fmt.Errorf("%w: %s needs files:read", ErrScopeMissing, method)
+	}
+
+	return client, nil
+}
+
+func fileInfo(f slack.File) FileInfo {
+	return FileInfo{
+		ID:        f.ID,
+		Name:      f.Name,
+		Title:     f.Title,
+		Mimetype:  f.Mimetype,
+		Size:      f.Size,
+		Permalink: f.Permalink,
+		UserID:    f.User,
+		Channels:  f.Channels,
+		Created:   f.Created.Time().UTC(),
+	}
+}
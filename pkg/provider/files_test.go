@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestListFiles(t *testing.T) {
+	var forms []string
+	ap, _ := newTestProvider(0,
+		withUsers(slack.User{ID: "U1", Name: "alice"}),
+		withChannels(Channel{ID: "C1", Name: "#general"}),
+		withWebAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/files.list" {
+				t.Errorf("unexpected path %q", r.URL.Path)
+			}
+			_ = r.ParseForm()
+			forms = append(forms, r.Form.Get("channel")+"/"+r.Form.Get("user")+"/"+r.Form.Get("cursor"))
+
+			w.Header().Set("Content-Type", "application/json")
+			if r.Form.Get("cursor") == "" {
+				_, _ = w.Write([]byte(`{"ok": true, "files": [
+					{"id": "F1", "name": "report.pdf", "title": "Report", "mimetype": "application/pdf", "size": 1024, "permalink": "https://example.slack.com/files/U1/F1/report.pdf", "user": "U1", "created": 1700000000}
+				], "response_metadata": {"next_cursor": "page2"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok": true, "files": [{"id": "F2", "name": "notes.txt", "user": "U1"}], "response_metadata": {"next_cursor": ""}}`))
+		})),
+	)
+
+	files, err := ap.ListFiles(context.Background(), FilesOptions{Channel: "#general", User: "@alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || files[0].ID != "F1" || files[1].ID != "F2" {
+		t.Fatalf("expected F1 and F2, got %+v", files)
+	}
+	if f := files[0]; f.Name != "report.pdf" || f.Mimetype != "application/pdf" || f.Size != 1024 || f.Created.Unix() != 1700000000 {
+		t.Errorf("unexpected file metadata %+v", f)
+	}
+	if !slices.Equal(forms, []string{"C1/U1/", "C1/U1/page2"}) {
+		t.Errorf("expected the filters to be resolved and paged through, got %v", forms)
+	}
+
+	// the limit stops paging early
+	forms = nil
+	if files, err := ap.ListFiles(context.Background(), FilesOptions{Limit: 1}); err != nil || len(files) != 1 || len(forms) != 1 {
+		t.Errorf("expected a single file from a single page, got %+v over %v, %v", files, forms, err)
+	}
+}
+
+func TestListFiles_MissingScope(t *testing.T) {
+	var calls int
+	ap, _ := newTestProvider(0,
+		withUsers(slack.User{ID: "U1", Name: "alice"}),
+		withChannels(Channel{ID: "C1", Name: "#general"}),
+		withWebAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope", "needed": "files:read"}`))
+		})),
+	)
+
+	// scopes unknown, Slack's missing_scope is mapped
+	if _, err := ap.ListFiles(context.Background(), FilesOptions{}); !errors.Is(err, ErrScopeMissing) {
+		t.Errorf("expected ErrScopeMissing from the API error, got %v", err)
+	}
+
+	// scopes known, the call is not made at all
+	ap.isBotToken = true
+	ap.scopes = map[string]bool{"channels:history": true}
+	if _, err := ap.GetFileInfo(context.Background(), "F1"); !errors.Is(err, ErrScopeMissing) || calls != 1 {
+		t.Errorf("expected ErrScopeMissing without a call, got %v after %d calls", err, calls)
+	}
+}
+
+// downloadTestHandler serves files.info for a file of the given reported
+// size and its download, which is body.
+func downloadTestHandler(t *testing.T, size int, body string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files.info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"ok": true, "file": {"id": "F1", "name": "notes.txt", "mimetype": "text/plain", "size": %d, "url_private_download": "http://%s/download/F1/notes.txt"}}`, size, r.Host)
+	})
+	mux.HandleFunc("/download/F1/notes.txt", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxp-test" {
+			t.Errorf("expected the download to be authenticated, got %q", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(body))
+	})
+	return mux
+}
+
+func TestDownloadFile(t *testing.T) {
+	ap, _ := newTestProvider(0, withWebAPI(t, downloadTestHandler(t, 11, "hello world")))
+
+	var buf bytes.Buffer
+	info, err := ap.DownloadFile(context.Background(), "F1", &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "hello world" || info.Name != "notes.txt" {
+		t.Errorf("unexpected download %q of %+v", buf.String(), info)
+	}
+}
+
+func TestDownloadFile_SizeCap(t *testing.T) {
+	t.Setenv("SLACK_MCP_MAX_FILE_BYTES", "5")
+
+	// refused upfront when files.info reports the size
+	ap, _ := newTestProvider(0, withWebAPI(t, downloadTestHandler(t, 11, "hello world")))
+	if _, err := ap.DownloadFile(context.Background(), "F1", io.Discard); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge from the reported size, got %v", err)
+	}
+
+	// and while downloading when it understates it
+	ap, _ = newTestProvider(0, withWebAPI(t, downloadTestHandler(t, 3, "hello world")))
+	if _, err := ap.DownloadFile(context.Background(), "F1", io.Discard); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge while downloading, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNotEnterprise is returned for Enterprise Grid operations on a
+// workspace that is not part of a grid.
+var ErrNotEnterprise = errors.New("not an Enterprise Grid workspace")
+
+// GridTeam is a workspace of the Enterprise Grid the token belongs to.
+type GridTeam struct {
+	ID     string
+	Name   string
+	Domain string
+	URL    string
+}
+
+// GetGridTeams returns the workspaces of the Enterprise Grid the token
+// belongs to, as listed by client.userBoot, ordered by name. The list is
+// fetched once and then served from memory.
+func (ap *ApiProvider) GetGridTeams(ctx context.Context) ([]GridTeam, error) {
+	ap.gridMu.Lock()
+	defer ap.gridMu.Unlock()
+
+	if ap.gridTeams != nil {
+		return slices.Clone(ap.gridTeams), nil
+	}
+
+	client, err := ap.ProvideEnterprise()
+	if err != nil {
+		return nil, err
+	}
+	info := ap.authTest()
+	if info.EnterpriseID == "" {
+		return nil, fmt.Errorf("%w: %s is a single workspace, there are no other teams to list", ErrNotEnterprise, info.Team)
+	}
+
+	boot, err := client.ClientUserBoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the teams of %s: %w", info.EnterpriseID, err)
+	}
+
+	teams := make([]GridTeam, 0, len(boot.Workspaces))
+	for _, w := range boot.Workspaces {
+		teams = append(teams, GridTeam{ID: w.ID, Name: w.Name, Domain: w.Domain, URL: w.URL})
+	}
+	slices.SortFunc(teams, func(a, b GridTeam) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	ap.gridTeams = teams
+
+	return slices.Clone(teams), nil
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	slack2 "github.com/rusq/slack"
+)
+
+// gridTestHandler serves client.userBoot listing two workspaces, counting
+// the calls in boots.
+func gridTestHandler(t *testing.T, boots *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/client.userBoot" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		*boots++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "workspaces": [
+			{"id": "T2", "name": "Sales", "domain": "acme-sales", "url": "https://acme-sales.slack.com/"},
+			{"id": "T1", "name": "Engineering", "domain": "acme-eng", "url": "https://acme-eng.slack.com/"}
+		]}`))
+	}
+}
+
+func TestGetGridTeams(t *testing.T) {
+	var boots int
+	ap, _ := newTestProvider(0, withEdgeAPI(t, gridTestHandler(t, &boots), slack2.AuthTestResponse{Team: "Acme", TeamID: "T1", EnterpriseID: "E1"}))
+
+	for range 2 {
+		teams, err := ap.GetGridTeams(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(teams) != 2 || teams[0].ID != "T1" || teams[1].Domain != "acme-sales" {
+			t.Errorf("expected both teams ordered by name, got %+v", teams)
+		}
+	}
+	if boots != 1 {
+		t.Errorf("expected the teams to be fetched once, got %d calls", boots)
+	}
+}
+
+func TestGetGridTeams_NotEnterprise(t *testing.T) {
+	var boots int
+	ap, _ := newTestProvider(0, withEdgeAPI(t, gridTestHandler(t, &boots), slack2.AuthTestResponse{Team: "Acme", TeamID: "T1"}))
+
+	_, err := ap.GetGridTeams(context.Background())
+	if !errors.Is(err, ErrNotEnterprise) {
+		t.Errorf("expected ErrNotEnterprise, got %v", err)
+	}
+	if boots != 0 {
+		t.Errorf("expected no call for a single workspace, got %d", boots)
+	}
+}
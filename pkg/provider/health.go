@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSubsystemUnavailable is returned by operations relying on a cache which
+// could not be loaded, the rest of the provider keeps serving meanwhile.
+var ErrSubsystemUnavailable = errors.New("subsystem unavailable")
+
+const (
+	SubsystemUsers    = "users"
+	SubsystemChannels = "channels"
+)
+
+// SubsystemHealth is the state of one cache. Err is the failure of its last
+// refresh, it is kept only while the cache has nothing to serve.
+type SubsystemHealth struct {
+	Name  string
+	Ready bool
+	Size  int
+	Err   error
+}
+
+// Health reports which caches the provider serves from.
+type Health struct {
+	Users    SubsystemHealth
+	Channels SubsystemHealth
+}
+
+// Degraded reports whether any subsystem is unavailable.
+func (h Health) Degraded() bool {
+	return !h.Users.Ready || !h.Channels.Ready
+}
+
+// Health returns the state of the users and channels caches. A cache which
+// failed to refresh still counts as ready when it holds entries, e.g. the
+// ones loaded before a failing re-fetch.
+func (ap *ApiProvider) Health() Health {
+	ap.usersMu.RLock()
+	users := subsystemHealth(SubsystemUsers, len(ap.users), ap.usersErr)
+	ap.usersMu.RUnlock()
+
+	ap.channelsMu.RLock()
+	channels := subsystemHealth(SubsystemChannels, len(ap.channels), ap.channelsErr)
+	ap.channelsMu.RUnlock()
+
+	return Health{Users: users, Channels: channels}
+}
+
+func subsystemHealth(name string, size int, err error) SubsystemHealth {
+	if size > 0 {
+		err = nil
+	}
+	return SubsystemHealth{Name: name, Ready: err == nil, Size: size, Err: err}
+}
+
+// Available returns an ErrSubsystemUnavailable error when the named
+// subsystem, SubsystemUsers or SubsystemChannels, has nothing to serve
+// because its refresh failed.
+func (ap *ApiProvider) Available(subsystem string) error {
+	h := ap.Health()
+
+	var s SubsystemHealth
+	switch subsystem {
+	case SubsystemUsers:
+		s = h.Users
+	case SubsystemChannels:
+		s = h.Channels
+	default:
+		return fmt.Errorf("unknown subsystem %q", subsystem)
+	}
+
+	if !s.Ready {
+		return fmt.Errorf("%w: %s cache could not be loaded: %v", ErrSubsystemUnavailable, s.Name, s.Err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestHealth_DegradedCombinations(t *testing.T) {
+	const (
+		usersJSON    = `[{"id": "U1", "name": "alice"}]`
+		channelsJSON = `[{"id": "C1", "name": "#general", "memberCount": 3}]`
+	)
+
+	tests := []struct {
+		name           string
+		users          bool // whether the users cache file exists
+		channels       bool // whether the channels cache file exists
+		usersReady     bool
+		channelsReady  bool
+		expectDegraded bool
+	}{
+		{name: "both loaded", users: true, channels: true, usersReady: true, channelsReady: true},
+		{name: "channels unavailable", users: true, usersReady: true, expectDegraded: true},
+		{name: "users unavailable", channels: true, channelsReady: true, expectDegraded: true},
+		{name: "both unavailable", expectDegraded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("SLACK_MCP_OFFLINE", "true")
+			t.Setenv("SLACK_MCP_USERS_CACHE", filepath.Join(dir, "users_cache.json"))
+			t.Setenv("SLACK_MCP_CHANNELS_CACHE", filepath.Join(dir, "channels_cache.json"))
+			if tt.users {
+				t.Setenv("SLACK_MCP_USERS_CACHE", writeFixture(t, "users_cache.json", usersJSON))
+			}
+			if tt.channels {
+				t.Setenv("SLACK_MCP_CHANNELS_CACHE", writeFixture(t, "channels_cache.json", channelsJSON))
+			}
+
+			ap, err := New()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = ap.RefreshUsers(context.Background())
+			_ = ap.RefreshChannels(context.Background())
+
+			h := ap.Health()
+			if h.Users.Ready != tt.usersReady || h.Channels.Ready != tt.channelsReady {
+				t.Fatalf("expected users ready %v and channels ready %v, got %+v", tt.usersReady, tt.channelsReady, h)
+			}
+			if h.Degraded() != tt.expectDegraded {
+				t.Errorf("expected Degraded() to be %v", tt.expectDegraded)
+			}
+
+			// the available cache keeps serving
+			_, userErr := ap.resolveUserRef("@alice")
+			_, chanErr := ap.ResolveChannelID("#general", false)
+			if tt.usersReady && userErr != nil {
+				t.Errorf("expected alice to resolve, got %v", userErr)
+			}
+			if tt.channelsReady && chanErr != nil {
+				t.Errorf("expected #general to resolve, got %v", chanErr)
+			}
+
+			// the unavailable one says so instead of "not found"
+			if !tt.usersReady {
+				if !errors.Is(userErr, ErrSubsystemUnavailable) || !errors.Is(ap.Available(SubsystemUsers), ErrSubsystemUnavailable) {
+					t.Errorf("expected ErrSubsystemUnavailable for users, got %v", userErr)
+				}
+				if !errors.Is(h.Users.Err, ErrOffline) {
+					t.Errorf("expected the refresh failure to be reported, got %v", h.Users.Err)
+				}
+			}
+			if !tt.channelsReady {
+				if !errors.Is(chanErr, ErrSubsystemUnavailable) || !errors.Is(ap.Available(SubsystemChannels), ErrSubsystemUnavailable) {
+					t.Errorf("expected ErrSubsystemUnavailable for channels, got %v", chanErr)
+				}
+			}
+		})
+	}
+}
+
+func TestHealth_RecoversAfterRefresh(t *testing.T) {
+	bootErr := errors.New("auth.test failed: 503 Service Unavailable")
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = filepath.Join(t.TempDir(), "channels_cache.json")
+	ap.boot = func(ap *ApiProvider) (*slack.Client, error) {
+		return nil, bootErr
+	}
+
+	// the channels API failing leaves the channels subsystem unavailable
+	if err := ap.RefreshChannels(context.Background()); !errors.Is(err, bootErr) {
+		t.Fatalf("expected the boot error, got %v", err)
+	}
+	if h := ap.Health(); h.Channels.Ready || !errors.Is(h.Channels.Err, bootErr) {
+		t.Fatalf("expected channels to be unavailable, got %+v", h.Channels)
+	}
+
+	// a later successful refresh, here from a cache file, clears it
+	ap.channelsCache = writeFixture(t, "channels_cache.json", `{"schema_version": 1, "entries": [{"id": "C1", "name": "#general"}]}`)
+	if err := ap.RefreshChannels(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ap.Available(SubsystemChannels); err != nil {
+		t.Errorf("expected channels to be available again, got %v", err)
+	}
+}
+
+func TestAvailable_UnknownSubsystem(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	if err := ap.Available("emoji"); err == nil || errors.Is(err, ErrSubsystemUnavailable) {
+		t.Errorf("expected an unknown subsystem error, got %v", err)
+	}
+}
+
+func TestRefreshUsers_SalvagesPartiallyDecodedCache(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	apiURL := srv.URL + "/"
+	srv.Close() // Slack cannot be reached
+
+	// is_bot is mistyped for bob, failing the decoding of the whole file
+	cache := filepath.Join(t.TempDir(), "users_cache.json")
+	if err := os.WriteFile(cache, []byte(`[
+		{"id": "U1", "name": "alice"},
+		{"id": "U2", "name": "bob", "is_bot": "no"},
+		{"id": "U3", "name": "carol"}
+	]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ap, _ := newTestProvider(0)
+	ap.usersCache = cache
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(apiURL))
+
+	if err := ap.RefreshUsers(context.Background()); err == nil {
+		t.Fatal("expected the refetch to fail")
+	}
+
+	for _, id := range []string{"U1", "U2", "U3"} {
+		if _, ok := ap.ResolveUser(id); !ok {
+			t.Errorf("expected %s to be salvaged from the cache", id)
+		}
+	}
+	if err := ap.Available(SubsystemUsers); err != nil {
+		t.Errorf("expected the salvaged users to be served, got %v", err)
+	}
+
+	// users loaded before are not replaced by a partial cache
+	ap.users = map[string]slack.User{"U9": {ID: "U9", Name: "zed"}}
+	_ = ap.RefreshUsers(context.Background())
+	if _, ok := ap.ResolveUser("U1"); ok {
+		t.Error("expected the previously loaded users to be kept as they were")
+	}
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// historyPageSize is the conversations.history page size used when paging
+// through a window.
+const historyPageSize = 200
+
+// GetHistoryWindow returns all messages of a channel posted within
+// [oldest, latest), newest first, paging through conversations.history at
+// the configured rate limit tier. The window is half-open so that adjacent
+// windows, e.g. consecutive days, list a message posted exactly on their
+// shared boundary once: in the later window. Slack's inclusive flag applies
+// to both ends, hence the window is fetched inclusive and a message at
+// latest is dropped here. Messages are unique by timestamp, even when Slack
+// returns one on two pages.
+func (ap *ApiProvider) GetHistoryWindow(ctx context.Context, channelRef string, oldest, latest time.Time) ([]slack.Message, error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	latestTS := slackTimestamp(latest)
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Oldest:    slackTimestamp(oldest),
+		Latest:    latestTS,
+		Inclusive: true,
+		Limit:     historyPageSize,
+	}
+
+	lim := ap.rateTier.Limiter()
+
+	var messages []slack.Message
+	seen := make(map[string]bool)
+	for {
+		history, err := client.GetConversationHistoryContext(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range history.Messages {
+			// pages may overlap by a message at their cursor boundary
+			if msg.Timestamp == latestTS || seen[msg.Timestamp] {
+				continue
+			}
+			seen[msg.Timestamp] = true
+			messages = append(messages, msg)
+		}
+
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			return messages, nil
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+
+		if err := lim.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// slackTimestamp formats t the way Slack formats message timestamps, with
+// microsecond precision.
+func slackTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/int(time.Microsecond))
+}
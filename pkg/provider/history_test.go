@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// historyTestHandler serves conversations.history from timestamps the way
+// Slack does: filtered by oldest and latest, inclusive on both ends or
+// neither, newest first and paged with an offset cursor.
+func historyTestHandler(timestamps []string, pageSize int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		oldest, latest := r.Form.Get("oldest"), r.Form.Get("latest")
+		inclusive := r.Form.Get("inclusive") == "1" || r.Form.Get("inclusive") == "true"
+
+		var window []string
+		for _, ts := range timestamps {
+			if (ts > oldest && ts < latest) || (inclusive && (ts == oldest || ts == latest)) {
+				window = append(window, ts)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(window)))
+
+		offset, _ := strconv.Atoi(r.Form.Get("cursor"))
+		end := min(offset+pageSize, len(window))
+
+		resp := map[string]any{"ok": true, "has_more": end < len(window)}
+		var msgs []map[string]string
+		for _, ts := range window[offset:end] {
+			msgs = append(msgs, map[string]string{"type": "message", "ts": ts, "text": ts})
+		}
+		resp["messages"] = msgs
+		if end < len(window) {
+			resp["response_metadata"] = map[string]string{"next_cursor": strconv.Itoa(end)}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestGetHistoryWindow_BoundaryOnMessage(t *testing.T) {
+	day1 := time.Unix(1700000000, 0)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+
+	// one message lands exactly on the boundary between the windows
+	boundary := slackTimestamp(day2)
+	timestamps := []string{
+		slackTimestamp(day1),
+		slackTimestamp(day1.Add(time.Hour)),
+		slackTimestamp(day2.Add(-time.Microsecond)),
+		boundary,
+		slackTimestamp(day2.Add(time.Microsecond)),
+		slackTimestamp(day2.Add(time.Hour)),
+	}
+
+	// pages of 2 so the windows take several pages each
+	ap, _ := newTestProvider(0, withWebAPI(t, historyTestHandler(timestamps, 2)))
+
+	seen := make(map[string]int)
+	for _, w := range [][2]time.Time{{day1, day2}, {day2, day3}} {
+		msgs, err := ap.GetHistoryWindow(context.Background(), "C1", w[0], w[1])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, m := range msgs {
+			seen[m.Timestamp]++
+		}
+	}
+
+	for _, ts := range timestamps {
+		if seen[ts] != 1 {
+			t.Errorf("expected %s exactly once across the windows, got %d", ts, seen[ts])
+		}
+	}
+
+	msgs, _ := ap.GetHistoryWindow(context.Background(), "C1", day2, day3)
+	if len(msgs) != 3 || msgs[len(msgs)-1].Timestamp != boundary {
+		t.Errorf("expected the boundary message to open the later window, got %+v", msgs)
+	}
+}
+
+func TestSlackTimestamp(t *testing.T) {
+	if got := slackTimestamp(time.Unix(1700000000, 123456789)); got != "1700000000.123456" {
+		t.Errorf("slackTimestamp() = %q", got)
+	}
+}
+
+func TestGetHistoryWindow_OverlappingPages(t *testing.T) {
+	// the second page repeats the last message of the first one
+	pages := map[string]string{
+		"":      `{"ok": true, "has_more": true, "messages": [{"ts": "1700000300.000000"}, {"ts": "1700000200.000000"}], "response_metadata": {"next_cursor": "page2"}}`,
+		"page2": `{"ok": true, "has_more": false, "messages": [{"ts": "1700000200.000000"}, {"ts": "1700000100.000000"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[r.Form.Get("cursor")]))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	msgs, err := ap.GetHistoryWindow(context.Background(), "C1", time.Unix(1700000000, 0), time.Unix(1700001000, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, m.Timestamp)
+	}
+	expected := []string{"1700000300.000000", "1700000200.000000", "1700000100.000000"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected each message once, got %v", got)
+	}
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+)
+
+// GetChannelInfo fetches a single channel with conversations.info, so that
+// its topic, purpose and member count are available without loading the
+// whole channels cache. channelRef is an ID or a name with or without its
+// prefix, names are resolved through the cache.
+func (ap *ApiProvider) GetChannelInfo(ctx context.Context, channelRef string) (Channel, error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return Channel{}, err
+	}
+
+	channel, err := client.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	})
+	if err != nil {
+		return Channel{}, err
+	}
+
+	ch := mapChannel(fromSlackChannel(*channel), ap.ProvideUsersMap().Users)
+
+	return ch, nil
+}
+
+// RefreshChannel refetches a single channel with conversations.info and
+// updates its cache entry, e.g. after it was renamed: the old name stops
+// resolving and the new one resolves without reloading all channels.
+func (ap *ApiProvider) RefreshChannel(ctx context.Context, channelID string) (Channel, error) {
+	ch, err := ap.GetChannelInfo(ctx, channelID)
+	if err != nil {
+		return Channel{}, err
+	}
+	return ap.cacheChannel(ch), nil
+}
+
+// cacheChannel stores a freshly fetched channel in the channels cache,
+// replacing the name it was cached under. Members, which single channel
+// responses leave out, are kept from the cached entry.
+func (ap *ApiProvider) cacheChannel(ch Channel) Channel {
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	if old, ok := ap.channels[ch.ID]; ok {
+		if old.Name != ch.Name && ap.channelsInv[old.Name] == ch.ID {
+			delete(ap.channelsInv, old.Name)
+		}
+		if ch.Members == nil {
+			ch.Members = old.Members
+		}
+	}
+	ap.channels[ch.ID] = ch
+	ap.channelsInv[ch.Name] = ch.ID
+
+	return ch
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestGetChannelInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.info" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("channel") != "C1" {
+			t.Errorf("expected #general to be resolved to C1, got %q", r.Form.Get("channel"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"channel": {
+				"id": "C1",
+				"name": "general",
+				"name_normalized": "general",
+				"is_channel": true,
+				"is_archived": true,
+				"created": 1600000000,
+				"creator": "U1",
+				"topic": {"value": "Company wide"},
+				"purpose": {"value": "Announcements"},
+				"num_members": 42
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general"}}
+	ap.channelsInv = map[string]string{"#general": "C1"}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	ch, err := ap.GetChannelInfo(context.Background(), "#general")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ch.ID != "C1" || ch.Name != "#general" || ch.Topic != "Company wide" || ch.Purpose != "Announcements" {
+		t.Errorf("unexpected channel %+v", ch)
+	}
+	if ch.MemberCount != 42 || !ch.IsArchived || ch.Created != 1600000000 || ch.Creator != "U1" {
+		t.Errorf("expected member count, archived flag and creation time to be set, got %+v", ch)
+	}
+}
+
+func TestRefreshChannel_Rename(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "C1", "name": "announcements", "name_normalized": "announcements", "is_channel": true, "num_members": 3}}`))
+	}))
+	defer srv.Close()
+
+	// cached before #general was renamed to #announcements
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general", MemberCount: 3, Members: []string{"U1"}}}
+	ap.channelsInv = map[string]string{"#general": "C1"}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	var notFound *ChannelNotFoundError
+	if _, err := ap.ResolveChannelID("#announcements", false); !errors.As(err, &notFound) {
+		t.Fatalf("expected the new name not to resolve yet, got %v", err)
+	}
+
+	if _, err := ap.RefreshChannel(context.Background(), "C1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if id, err := ap.ResolveChannelID("#announcements", false); err != nil || id != "C1" {
+		t.Errorf("expected the new name to resolve to C1, got %q, %v", id, err)
+	}
+	if _, ok := ap.channelsInv["#general"]; ok {
+		t.Errorf("expected the old name to be dropped")
+	}
+	if c := ap.channels["C1"]; c.Name != "#announcements" || len(c.Members) != 1 {
+		t.Errorf("expected the entry to be renamed keeping its members, got %+v", c)
+	}
+}
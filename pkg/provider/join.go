@@ -0,0 +1,58 @@
+package provider
+
+import "context"
+
+// JoinChannel joins a public channel with conversations.join and updates
+// its entry in the channels cache, so its history can be read right away.
+// channelRef is an ID or a name with or without its prefix. alreadyMember
+// is true when the token's user was in the channel before.
+func (ap *ApiProvider) JoinChannel(ctx context.Context, channelRef string) (ch Channel, alreadyMember bool, err error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return Channel{}, false, err
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return Channel{}, false, err
+	}
+
+	channel, warning, _, err := client.JoinConversationContext(ctx, channelID)
+	if err != nil {
+		return Channel{}, false, err
+	}
+
+	raw := fromSlackChannel(*channel)
+	raw.IsMember = true
+	ch = mapChannel(raw, ap.ProvideUsersMap().Users)
+
+	return ap.cacheChannel(ch), warning == "already_in_channel", nil
+}
+
+// LeaveChannel leaves a channel with conversations.leave and returns its ID.
+// notMember is true when the token's user was not in the channel.
+func (ap *ApiProvider) LeaveChannel(ctx context.Context, channelRef string) (channelID string, notMember bool, err error) {
+	channelID, err = ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return "", false, err
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return "", false, err
+	}
+
+	notMember, err = client.LeaveConversationContext(ctx, channelID)
+	if err != nil {
+		return "", false, err
+	}
+
+	ap.channelsMu.Lock()
+	if ch, ok := ap.channels[channelID]; ok {
+		ch.IsMember = false
+		ap.channels[channelID] = ch
+	}
+	ap.channelsMu.Unlock()
+
+	return channelID, notMember, nil
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestJoinChannel_UpdatesCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.join" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if got := r.Form.Get("channel"); got != "C1" {
+			t.Errorf("expected C1 to be joined, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true, "num_members": 4, "topic": {"value": "news"}}}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general", MemberCount: 3, Members: []string{"U1"}}}
+	ap.channelsInv = map[string]string{"#general": "C1"}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	ch, alreadyMember, err := ap.JoinChannel(context.Background(), "#general")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ch.ID != "C1" || alreadyMember {
+		t.Errorf("expected C1 to be joined, got %+v, already member %v", ch, alreadyMember)
+	}
+
+	cached := ap.channels["C1"]
+	if cached.MemberCount != 4 || cached.Topic != "news" {
+		t.Errorf("expected the cache entry to be refreshed, got %+v", cached)
+	}
+	if len(cached.Members) != 1 {
+		t.Errorf("expected the cached members to be kept, got %v", cached.Members)
+	}
+}
+
+func TestJoinChannel_AlreadyMember(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "warning": "already_in_channel", "channel": {"id": "C1", "name": "general", "is_channel": true}}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general"}}
+	ap.channelsInv = map[string]string{"#general": "C1"}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	if _, alreadyMember, err := ap.JoinChannel(context.Background(), "C1"); err != nil || !alreadyMember {
+		t.Errorf("expected already member, got %v, %v", alreadyMember, err)
+	}
+}
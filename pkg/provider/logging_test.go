@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+// captureStdout returns everything written to os.Stdout while fn runs.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+
+	fn()
+
+	w.Close()
+	return <-done
+}
+
+func TestRefresh_NothingOnStdout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users.list":
+			_, _ = w.Write([]byte(`{"ok": true, "members": [{"id": "U1", "name": "alice"}]}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true}], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ap, _ := newTestProvider(0)
+	ap.usersCache = filepath.Join(dir, "users_cache.json")
+	ap.channelsCache = filepath.Join(dir, "channels_cache.json")
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	out := captureStdout(t, func() {
+		if err := ap.RefreshUsers(context.Background()); err != nil {
+			t.Errorf("unexpected error refreshing users: %v", err)
+		}
+		if err := ap.RefreshChannels(context.Background()); err != nil {
+			t.Errorf("unexpected error refreshing channels: %v", err)
+		}
+		// the second round is served from the cache files just written
+		_ = ap.RefreshUsers(context.Background())
+		_ = ap.RefreshChannels(context.Background())
+	})
+	if out != "" {
+		t.Errorf("expected nothing on stdout, got %q", out)
+	}
+}
+
+func TestRefresh_RedactsTokensInLog(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	// a failed request quotes its URL, a team endpoint carrying a token here
+	apiURL := srv.URL + "/xoxc-123-secret/d=xoxd-secret/"
+	srv.Close()
+
+	dir := t.TempDir()
+	ap, _ := newTestProvider(0)
+	ap.usersCache = filepath.Join(dir, "users_cache.json")
+	ap.channelsCache = filepath.Join(dir, "channels_cache.json")
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(apiURL))
+	ap.clientEnterprise = &edge.Client{}
+
+	buf := captureLog(t)
+
+	_ = ap.RefreshUsers(context.Background())
+	_ = ap.RefreshChannels(context.Background())
+
+	logged := buf.String()
+	if !strings.Contains(logged, "Failed to fetch users") || !strings.Contains(logged, "Failed to fetch channels") {
+		t.Fatalf("expected both fetches to fail, got:\n%s", logged)
+	}
+	if strings.Contains(logged, "secret") {
+		t.Errorf("expected the token and cookie to be redacted, got:\n%s", logged)
+	}
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/slack-go/slack"
+)
+
+// MemberRange bounds channels by member count, zero bounds are unbounded.
+// Counts from the bulk listing can be stale, channels whose count is within
+// RefreshMargin of a bound are re-checked with conversations.info.
+type MemberRange struct {
+	Min           int
+	Max           int
+	RefreshMargin int
+}
+
+// Contains reports whether count lies within the range.
+func (r MemberRange) Contains(count int) bool {
+	if r.Min > 0 && count < r.Min {
+		return false
+	}
+	if r.Max > 0 && count > r.Max {
+		return false
+	}
+	return true
+}
+
+// nearBound reports whether count is close enough to a bound for a stale
+// value to put the channel on the wrong side of it.
+func (r MemberRange) nearBound(count int) bool {
+	if r.RefreshMargin <= 0 {
+		return false
+	}
+	near := func(bound int) bool {
+		return bound > 0 && count >= bound-r.RefreshMargin && count <= bound+r.RefreshMargin
+	}
+	return near(r.Min) || near(r.Max)
+}
+
+// GetChannelsByMemberCount returns the channels of the given types whose
+// member count lies within r, e.g. tiny channels to archive or huge ones.
+func (ap *ApiProvider) GetChannelsByMemberCount(ctx context.Context, channelTypes []string, r MemberRange) ([]Channel, error) {
+	channels, err := ap.GetChannels(ctx, channelTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return ap.filterByMemberCount(ctx, channels, r), nil
+}
+
+func (ap *ApiProvider) filterByMemberCount(ctx context.Context, channels []Channel, r MemberRange) []Channel {
+	var res []Channel
+	for _, c := range channels {
+		if r.nearBound(c.MemberCount) {
+			if fresh, err := ap.GetChannelInfo(ctx, c.ID); err != nil {
+				log.Printf("Failed to refresh member count of %s, using cached %d: %v", c.ID, c.MemberCount, err)
+			} else {
+				c.MemberCount = fresh.MemberCount
+			}
+		}
+
+		if r.Contains(c.MemberCount) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// GetChannelMembers returns the IDs of up to limit members of a channel,
+// paging through conversations.members at the configured rate tier. A
+// limit of 0 returns all of them, which are then cached on the channel as
+// bulk listings leave Members empty for big channels. channelRef is an ID
+// or a name with or without its prefix.
+func (ap *ApiProvider) GetChannelMembers(ctx context.Context, channelRef string, limit int) ([]string, error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := ap.conversationMembersLimit(ctx, channelID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		ap.channelsMu.Lock()
+		if c, ok := ap.channels[channelID]; ok {
+			c.Members = members
+			ap.channels[channelID] = c
+		}
+		ap.channelsMu.Unlock()
+	}
+
+	return members, nil
+}
+
+func (ap *ApiProvider) conversationMembers(ctx context.Context, channelID string) ([]string, error) {
+	return ap.conversationMembersLimit(ctx, channelID, 0)
+}
+
+func (ap *ApiProvider) conversationMembersLimit(ctx context.Context, channelID string, limit int) ([]string, error) {
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	params := &slack.GetUsersInConversationParameters{ChannelID: channelID, Limit: 1000}
+	if limit > 0 && limit < params.Limit {
+		params.Limit = limit
+	}
+
+	lim := ap.rateTier.Limiter()
+
+	var members []string
+	for {
+		page, cursor, err := client.GetUsersInConversationContext(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, page...)
+
+		if limit > 0 && len(members) >= limit {
+			return members[:limit], nil
+		}
+		if cursor == "" {
+			return members, nil
+		}
+		params.Cursor = cursor
+
+		if err := lim.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fetchMembersEnabled reports whether SLACK_MCP_FETCH_MEMBERS asks for the
+// members of public and private channels to be fetched while listing them.
+func fetchMembersEnabled() bool {
+	switch os.Getenv("SLACK_MCP_FETCH_MEMBERS") {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// fillMembers sets Members of the public and private channels, which the
+// bulk listing leaves empty, and MemberCount from them as the listed count
+// can be stale. Members already cached are reused, each fetch costs at
+// least one conversations.members call so they are paced by the limiter.
+// Archived channels are skipped.
+func (ap *ApiProvider) fillMembers(ctx context.Context, chans []Channel) {
+	lim := ap.rateTier.Limiter()
+	for i := range chans {
+		c := &chans[i]
+		if c.IsIM || c.IsMpIM || c.IsArchived || len(c.Members) > 0 {
+			continue
+		}
+
+		ap.channelsMu.RLock()
+		cached := ap.channels[c.ID].Members
+		ap.channelsMu.RUnlock()
+		if len(cached) > 0 {
+			c.Members, c.MemberCount = cached, len(cached)
+			continue
+		}
+
+		if err := lim.Wait(ctx); err != nil {
+			return
+		}
+		members, err := ap.conversationMembers(ctx, c.ID)
+		if err != nil {
+			log.Printf("Failed to fetch members of %s: %v", c.ID, err)
+			continue
+		}
+		c.Members, c.MemberCount = members, len(members)
+	}
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+func TestMemberRange_Contains(t *testing.T) {
+	tests := []struct {
+		r        MemberRange
+		count    int
+		expected bool
+	}{
+		{r: MemberRange{}, count: 0, expected: true},
+		{r: MemberRange{Min: 3}, count: 2, expected: false},
+		{r: MemberRange{Min: 3}, count: 3, expected: true},
+		{r: MemberRange{Max: 5}, count: 5, expected: true},
+		{r: MemberRange{Max: 5}, count: 6, expected: false},
+		{r: MemberRange{Min: 3, Max: 5}, count: 4, expected: true},
+		{r: MemberRange{Min: 3, Max: 5}, count: 500, expected: false},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Contains(tt.count); got != tt.expected {
+			t.Errorf("%+v.Contains(%d) = %v, expected %v", tt.r, tt.count, got, tt.expected)
+		}
+	}
+}
+
+func TestFilterByMemberCount_RefreshesNearBounds(t *testing.T) {
+	var refreshed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		refreshed = append(refreshed, r.Form.Get("channel"))
+
+		w.Header().Set("Content-Type", "application/json")
+		// C2 grew past the bound since the listing
+		_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "C2", "name": "design", "is_channel": true, "num_members": 7}}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	channels := []Channel{
+		{ID: "C1", Name: "#tiny", MemberCount: 1},
+		{ID: "C2", Name: "#design", MemberCount: 5},
+		{ID: "C3", Name: "#general", MemberCount: 900},
+	}
+
+	got := ap.filterByMemberCount(context.Background(), channels, MemberRange{Max: 5, RefreshMargin: 1})
+
+	if len(got) != 1 || got[0].ID != "C1" {
+		t.Errorf("expected only C1 after refreshing C2, got %+v", got)
+	}
+	if !slices.Equal(refreshed, []string{"C2"}) {
+		t.Errorf("expected only the channel near the bound to be refreshed, got %v", refreshed)
+	}
+
+	// without a margin the cached counts are used as they are
+	refreshed = nil
+	got = ap.filterByMemberCount(context.Background(), channels, MemberRange{Max: 5})
+	if len(got) != 2 || len(refreshed) != 0 {
+		t.Errorf("expected C1 and C2 without any refresh, got %+v (refreshed %v)", got, refreshed)
+	}
+}
+
+func TestGetChannelMembers(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.members" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		pages = append(pages, r.Form.Get("cursor")+"/"+r.Form.Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"ok": true, "members": ["U1", "U2"], "response_metadata": {"next_cursor": "page2"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "members": ["U3"], "response_metadata": {"next_cursor": ""}}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general", MemberCount: 3}}
+	ap.channelsInv = map[string]string{"#general": "C1"}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	members, err := ap.GetChannelMembers(context.Background(), "#general", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(members, []string{"U1", "U2", "U3"}) || len(pages) != 2 {
+		t.Errorf("expected all members over 2 pages, got %v over %v", members, pages)
+	}
+	if !slices.Equal(ap.channels["C1"].Members, members) {
+		t.Errorf("expected the members to be cached on the channel, got %v", ap.channels["C1"].Members)
+	}
+
+	// a limit stops paging early and leaves the cache alone
+	pages = nil
+	ap.channels["C1"] = Channel{ID: "C1", Name: "#general"}
+	members, err = ap.GetChannelMembers(context.Background(), "C1", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(members, []string{"U1", "U2"}) || !slices.Equal(pages, []string{"/2"}) {
+		t.Errorf("expected a single page of 2, got %v over %v", members, pages)
+	}
+	if len(ap.channels["C1"].Members) != 0 {
+		t.Errorf("expected a partial listing not to be cached, got %v", ap.channels["C1"].Members)
+	}
+}
+
+func TestGetChannels_FetchMembers(t *testing.T) {
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{
+				"ok": true,
+				"channels": [
+					{"id": "C1", "name": "general", "name_normalized": "general", "num_members": 10},
+					{"id": "C2", "name": "random", "name_normalized": "random", "num_members": 10},
+					{"id": "C3", "name": "old", "name_normalized": "old", "is_archived": true, "num_members": 10},
+					{"id": "D1", "is_im": true, "user": "U1"}
+				],
+				"response_metadata": {"next_cursor": ""}
+			}`))
+		case "/conversations.members":
+			fetched = append(fetched, r.Form.Get("channel"))
+			_, _ = w.Write([]byte(`{"ok": true, "members": ["U1", "U2"], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	// off by default
+	if _, err := ap.GetChannels(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fetched) != 0 {
+		t.Fatalf("expected no members to be fetched by default, got %v", fetched)
+	}
+
+	// C2 members were cached meanwhile, e.g. by conversations_members
+	ap.channels["C2"] = Channel{ID: "C2", Name: "#random", Members: []string{"U3"}}
+
+	t.Setenv("SLACK_MCP_FETCH_MEMBERS", "true")
+	if _, err := ap.GetChannels(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(fetched, []string{"C1"}) {
+		t.Errorf("expected only C1 to be fetched, got %v", fetched)
+	}
+
+	channels := ap.ProvideChannelsMaps().Channels
+	if c := channels["C1"]; !slices.Equal(c.Members, []string{"U1", "U2"}) || c.MemberCount != 2 {
+		t.Errorf("expected C1 members and an exact count, got %+v", c)
+	}
+	if c := channels["C2"]; !slices.Equal(c.Members, []string{"U3"}) || c.MemberCount != 1 {
+		t.Errorf("expected the cached C2 members to be reused, got %+v", c)
+	}
+	if c := channels["C3"]; len(c.Members) != 0 || c.MemberCount != 10 {
+		t.Errorf("expected the archived channel to be skipped, got %+v", c)
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/slack-go/slack"
+)
+
+const defaultMpimMemberFetchLimit = 50
+
+// mpimMemberFetchLimit returns how many MPIM members missing from the users
+// cache may be fetched with users.info while listing channels, configured
+// with SLACK_MCP_MPIM_MEMBER_FETCH. 0 disables the fetch.
+func mpimMemberFetchLimit() int {
+	v := os.Getenv("SLACK_MCP_MPIM_MEMBER_FETCH")
+	if v == "" {
+		return defaultMpimMemberFetchLimit
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("Invalid SLACK_MCP_MPIM_MEMBER_FETCH %q, using %d", v, defaultMpimMemberFetchLimit)
+		return defaultMpimMemberFetchLimit
+	}
+	return n
+}
+
+func mpimMembers(channels []slack.Channel) [][]string {
+	var members [][]string
+	for _, c := range channels {
+		if c.IsMpIM {
+			members = append(members, c.Members)
+		}
+	}
+	return members
+}
+
+// fetchMpimMembers fetches up to limit users of members that are not in the
+// users cache yet and adds them to it, so that group DM purposes list names
+// rather than unknown users. It returns the number of users requested.
+func (ap *ApiProvider) fetchMpimMembers(ctx context.Context, members [][]string, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+
+	ap.usersMu.RLock()
+	for _, ids := range members {
+		for _, id := range ids {
+			if _, ok := ap.users[id]; !ok && !seen[id] && len(missing) < limit {
+				seen[id] = true
+				missing = append(missing, id)
+			}
+		}
+	}
+	ap.usersMu.RUnlock()
+
+	if len(missing) == 0 {
+		return 0
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return 0
+	}
+
+	users, err := client.GetUsersInfoContext(ctx, missing...)
+	if err != nil {
+		log.Printf("Failed to fetch %d group DM members missing from the users cache: %v", len(missing), err)
+		return len(missing)
+	}
+
+	ap.usersMu.Lock()
+	for _, u := range *users {
+		ap.indexUser(u)
+	}
+	ap.usersMu.Unlock()
+
+	return len(missing)
+}
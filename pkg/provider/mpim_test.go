@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// mpimTestHandler lists one group DM with a member missing from the users
+// cache and records the users.info lookups in fetched.
+func mpimTestHandler(t *testing.T, fetched *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{
+				"ok": true,
+				"channels": [
+					{"id": "G1", "name": "mpdm-alice--carol-1", "name_normalized": "mpdm-alice--carol-1", "is_mpim": true, "members": ["U1", "U3"]}
+				],
+				"response_metadata": {"next_cursor": ""}
+			}`))
+		case "/users.info":
+			*fetched = append(*fetched, r.Form.Get("users"))
+			_, _ = w.Write([]byte(`{"ok": true, "users": [{"id": "U3", "name": "carol", "real_name": "Carol Danvers"}]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}
+}
+
+func TestGetChannels_FetchesUnknownMpimMembers(t *testing.T) {
+	var fetched []string
+	ap, _ := newTestProvider(0,
+		withUsers(slack.User{ID: "U1", Name: "alice", RealName: "Alice Liddell"}),
+		withWebAPI(t, mpimTestHandler(t, &fetched)),
+	)
+
+	got, err := ap.GetChannels(context.Background(), []string{"mpim"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 group DM, got %+v", got)
+	}
+	if got[0].Purpose != "Group DM with Alice Liddell, Carol Danvers" {
+		t.Errorf("unexpected purpose %q", got[0].Purpose)
+	}
+	if len(fetched) != 1 || fetched[0] != "U3" {
+		t.Errorf("expected only the uncached member to be fetched, got %v", fetched)
+	}
+	if _, ok := ap.ResolveUser("U3"); !ok || ap.usersInv["carol"] != "U3" {
+		t.Error("expected the fetched member to be added to the users cache")
+	}
+}
+
+func TestGetChannels_LabelsUnknownMpimMembers(t *testing.T) {
+	t.Setenv("SLACK_MCP_MPIM_MEMBER_FETCH", "0")
+
+	var fetched []string
+	ap, _ := newTestProvider(0,
+		withUsers(slack.User{ID: "U1", Name: "alice", RealName: "Alice Liddell"}),
+		withWebAPI(t, mpimTestHandler(t, &fetched)),
+	)
+
+	got, err := ap.GetChannels(context.Background(), []string{"mpim"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Purpose != "Group DM with Alice Liddell, 1 unknown user" {
+		t.Errorf("expected the uncached member to be labeled, got %+v", got)
+	}
+	if len(fetched) != 0 {
+		t.Errorf("expected no fetch when disabled, got %v", fetched)
+	}
+}
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultChannelNamePrefix = "#"
+	defaultDMNamePrefix      = "@"
+)
+
+// ChannelNamePrefix returns the prefix prepended to public and private
+// channel names. It is "#" unless SLACK_MCP_CHANNEL_PREFIX is set, an empty
+// value strips the prefix entirely.
+func ChannelNamePrefix() string {
+	if prefix, ok := os.LookupEnv("SLACK_MCP_CHANNEL_PREFIX"); ok {
+		return prefix
+	}
+	return defaultChannelNamePrefix
+}
+
+// DMNamePrefix returns the prefix prepended to IM and MPIM names. It is "@"
+// unless SLACK_MCP_DM_PREFIX is set, an empty value strips the prefix.
+func DMNamePrefix() string {
+	if prefix, ok := os.LookupEnv("SLACK_MCP_DM_PREFIX"); ok {
+		return prefix
+	}
+	return defaultDMNamePrefix
+}
+
+// bareChannelName strips a default or configured prefix from a channel name.
+func bareChannelName(name string) string {
+	for _, prefix := range []string{ChannelNamePrefix(), DMNamePrefix(), defaultChannelNamePrefix, defaultDMNamePrefix} {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return name
+}
+
+// channelNameKeys returns the channelsInv keys a user supplied reference may
+// be stored under, so that "#general", "general" and the configured prefix
+// form all resolve to the same channel. Channel keys come before DM keys.
+func channelNameKeys(ref string) []string {
+	bare := bareChannelName(ref)
+	keys := []string{ref}
+	for _, key := range []string{ChannelNamePrefix() + bare, DMNamePrefix() + bare} {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// isDM reports whether c is an IM or MPIM rather than a channel.
+func isDM(c Channel) bool {
+	return c.IsIM || c.IsMpIM
+}
+
+// forcedKind reports whether ref's prefix restricts it to channels ("#" or
+// the configured channel prefix) or to DMs ("@" or the configured DM
+// prefix). A bare name may be either.
+func forcedKind(ref string) (channel, dm bool) {
+	hasPrefix := func(prefix string) bool {
+		return prefix != "" && strings.HasPrefix(ref, prefix)
+	}
+	switch {
+	case hasPrefix(defaultChannelNamePrefix) || hasPrefix(ChannelNamePrefix()):
+		return true, false
+	case hasPrefix(defaultDMNamePrefix) || hasPrefix(DMNamePrefix()):
+		return false, true
+	}
+	return false, false
+}
+
+// candidates returns the cached channels a name may refer to, channels
+// before DMs. A prefixed name only matches its own kind, which also holds
+// when the prefixes are configured empty and both kinds share names. Unless
+// caseSensitive, names differing in case only match when none is exact.
+func (cc *ChannelsCache) candidates(ref string, caseSensitive bool) []Channel {
+	channel, dm := forcedKind(ref)
+
+	keys := channelNameKeys(ref)
+	matches := func(c Channel) bool {
+		return !(channel && isDM(c)) && !(dm && !isDM(c))
+	}
+
+	var res []Channel
+	for _, key := range keys {
+		id, ok := cc.ChannelsInv[key]
+		if !ok {
+			continue
+		}
+		c, ok := cc.Channels[id]
+		if !ok || !matches(c) {
+			continue
+		}
+		if !slices.ContainsFunc(res, func(r Channel) bool { return r.ID == c.ID }) {
+			res = append(res, c)
+		}
+	}
+
+	// With empty prefixes a channel and a DM may share a name, the inverse
+	// map then holds only one of them
+	if len(res) == 0 && (channel || dm) {
+		for _, c := range cc.Channels {
+			if matches(c) && slices.Contains(keys, c.Name) {
+				res = append(res, c)
+			}
+		}
+	}
+
+	if len(res) == 0 && !caseSensitive {
+		for _, c := range cc.Channels {
+			if matches(c) && slices.ContainsFunc(keys, func(key string) bool { return strings.EqualFold(key, c.Name) }) {
+				res = append(res, c)
+			}
+		}
+		// the cache map has no order, keep the result stable
+		sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return !isDM(res[i]) && isDM(res[j])
+	})
+
+	return res
+}
+
+// ChannelByID returns a cached channel without copying the channels cache
+// the way ProvideChannelsMaps does.
+func (ap *ApiProvider) ChannelByID(id string) (Channel, bool) {
+	ap.channelsMu.RLock()
+	defer ap.channelsMu.RUnlock()
+
+	c, ok := ap.channels[id]
+	return c, ok
+}
+
+// ChannelNameByID returns the cached name of a channel, with its prefix.
+func (ap *ApiProvider) ChannelNameByID(id string) (string, bool) {
+	c, ok := ap.ChannelByID(id)
+	return c.Name, ok
+}
+
+// UserIDPattern matches user IDs, U12345678 or W12345678 for Enterprise
+// Grid.
+var UserIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]{8,}$`)
+
+// imWith returns the cached DM with the user userID.
+func (cc *ChannelsCache) imWith(userID string) (Channel, bool) {
+	for _, c := range cc.Channels {
+		if c.IsIM && c.User == userID {
+			return c, true
+		}
+	}
+	return Channel{}, false
+}
+
+// Lookup resolves a channel reference to the cached channel. The reference
+// may be a channel ID or a name with or without its prefix, matched as
+// ResolveChannelID does; a bare name matching both a channel and a DM
+// resolves to the channel.
+func (cc *ChannelsCache) Lookup(ref string, caseSensitive bool) (Channel, bool) {
+	if c, ok := cc.Channels[ref]; ok {
+		return c, true
+	}
+
+	if cands := cc.candidates(ref, caseSensitive); len(cands) > 0 {
+		return cands[0], true
+	}
+
+	return Channel{}, false
+}
+
+// AmbiguousChannelError is returned for a bare name matching both a channel
+// and a DM, e.g. "general" with #general and a user named general.
+type AmbiguousChannelError struct {
+	Ref        string
+	Candidates []string // names of the matches, the channel first
+}
+
+func (e *AmbiguousChannelError) Error() string {
+	return fmt.Sprintf("channel %q is ambiguous, use one of %s", e.Ref, strings.Join(e.Candidates, " or "))
+}
+
+// ResolveChannelID converts a channel reference, an ID or a name with or
+// without its prefix, to the channel ID; handlers resolve all references
+// through it. A user ID resolves to the cached DM with that user. References
+// missing from the cache are passed through as IDs unless they are clearly
+// names, those fail with a *ChannelNotFoundError suggesting the closest
+// cached names. A bare name matching both a channel and a DM fails with an
+// *AmbiguousChannelError. Names differing in case, e.g. "#General" for
+// #general, resolve when no name matches exactly unless caseSensitive.
+func (ap *ApiProvider) ResolveChannelID(ref string, caseSensitive bool) (string, error) {
+	cc := ap.ProvideChannelsMaps()
+	if c, ok := cc.Channels[ref]; ok {
+		return c.ID, nil
+	}
+
+	if UserIDPattern.MatchString(ref) {
+		if c, ok := cc.imWith(ref); ok {
+			return c.ID, nil
+		}
+	}
+
+	cands := cc.candidates(ref, caseSensitive)
+	if len(cands) > 1 && isDM(cands[len(cands)-1]) != isDM(cands[0]) {
+		names := make([]string, 0, len(cands))
+		for _, c := range cands {
+			names = append(names, c.Name)
+		}
+		return "", &AmbiguousChannelError{Ref: ref, Candidates: names}
+	}
+	if len(cands) > 0 {
+		return cands[0].ID, nil
+	}
+
+	if strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "@") {
+		if err := ap.Available(SubsystemChannels); err != nil {
+			return "", err
+		}
+		return "", &ChannelNotFoundError{Ref: ref, Suggestions: suggestChannels(cc.Channels, ref)}
+	}
+	return ref, nil
+}
@@ -0,0 +1,300 @@
+package provider
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+)
+
+func TestMapChannel_Prefixes(t *testing.T) {
+	users := map[string]slack.User{
+		"U1": {ID: "U1", Name: "alice", RealName: "Alice"},
+	}
+
+	tests := []struct {
+		name          string
+		channelPrefix *string
+		dmPrefix      *string
+		expectChannel string
+		expectDM      string
+	}{
+		{
+			name:          "default prefixes",
+			expectChannel: "#general",
+			expectDM:      "@alice",
+		},
+		{
+			name:          "unprefixed",
+			channelPrefix: strPtr(""),
+			dmPrefix:      strPtr(""),
+			expectChannel: "general",
+			expectDM:      "alice",
+		},
+		{
+			name:          "custom prefixes",
+			channelPrefix: strPtr("channel:"),
+			dmPrefix:      strPtr("dm:"),
+			expectChannel: "channel:general",
+			expectDM:      "dm:alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPrefixEnv(t, tt.channelPrefix, tt.dmPrefix)
+
+			chn := mapChannel(rawChannel{Channel: Channel{ID: "C1", Name: "general", MemberCount: 3}, NameNormalized: "general"}, users)
+			if chn.Name != tt.expectChannel {
+				t.Errorf("channel name = %q, expected %q", chn.Name, tt.expectChannel)
+			}
+
+			dm := mapChannel(rawChannel{Channel: Channel{ID: "D1", User: "U1", IsIM: true}}, users)
+			if dm.Name != tt.expectDM {
+				t.Errorf("dm name = %q, expected %q", dm.Name, tt.expectDM)
+			}
+		})
+	}
+}
+
+func TestChannelsCacheLookup_Prefixes(t *testing.T) {
+	for _, prefix := range []*string{nil, strPtr("")} {
+		setPrefixEnv(t, prefix, prefix)
+
+		users := map[string]slack.User{"U1": {ID: "U1", Name: "alice"}}
+		chn := mapChannel(rawChannel{Channel: Channel{ID: "C1", Name: "general", MemberCount: 3}, NameNormalized: "general"}, users)
+		dm := mapChannel(rawChannel{Channel: Channel{ID: "D1", User: "U1", IsIM: true}}, users)
+		cache := &ChannelsCache{
+			Channels:    map[string]Channel{chn.ID: chn, dm.ID: dm},
+			ChannelsInv: map[string]string{chn.Name: chn.ID, dm.Name: dm.ID},
+		}
+
+		for ref, expected := range map[string]string{
+			"C1":       "C1",
+			"#general": "C1",
+			"general":  "C1",
+			"@alice":   "D1",
+			"alice":    "D1",
+		} {
+			got, ok := cache.Lookup(ref, false)
+			if !ok || got.ID != expected {
+				t.Errorf("Lookup(%q) = %q, %v; expected %q", ref, got.ID, ok, expected)
+			}
+		}
+
+		if _, ok := cache.Lookup("#random", false); ok {
+			t.Errorf("expected unknown channel not to resolve")
+		}
+	}
+}
+
+func setPrefixEnv(t *testing.T, channelPrefix, dmPrefix *string) {
+	t.Helper()
+	for key, val := range map[string]*string{
+		"SLACK_MCP_CHANNEL_PREFIX": channelPrefix,
+		"SLACK_MCP_DM_PREFIX":      dmPrefix,
+	} {
+		// t.Setenv restores the previous state once the test finishes
+		t.Setenv(key, "")
+		if val == nil {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, *val)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestMapChannel_NormalizesTopicAndPurpose(t *testing.T) {
+	users := map[string]slack.User{"U1": {ID: "U1", Name: "alice"}}
+	topic := "Incidents only\n<https://status.example.com|status page> &amp; runbooks"
+	purpose := "Owned by <@U1>\n\n"
+
+	t.Run("normalized", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_RAW_CHANNEL_TEXT", "")
+
+		ch := mapChannel(rawChannel{Channel: Channel{ID: "C1", Name: "incidents", Topic: topic, Purpose: purpose, MemberCount: 3}, NameNormalized: "incidents"}, users)
+		if ch.Topic != "Incidents only https://status.example.com - status page & runbooks" {
+			t.Errorf("unexpected topic %q", ch.Topic)
+		}
+		if ch.Purpose != "Owned by @alice" {
+			t.Errorf("unexpected purpose %q", ch.Purpose)
+		}
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_RAW_CHANNEL_TEXT", "true")
+
+		ch := mapChannel(rawChannel{Channel: Channel{ID: "C1", Name: "incidents", Topic: topic, Purpose: purpose, MemberCount: 3}, NameNormalized: "incidents"}, users)
+		if ch.Topic != topic || ch.Purpose != purpose {
+			t.Errorf("expected raw topic and purpose, got %q and %q", ch.Topic, ch.Purpose)
+		}
+	})
+}
+
+func TestDisplayNamePref_SharedByChannelsAndMentions(t *testing.T) {
+	users := map[string]slack.User{
+		"U1": {ID: "U1", Name: "jdoe", RealName: "Jane Doe", Profile: slack.UserProfile{DisplayName: "Jane"}},
+		"U2": {ID: "U2", Name: "bob", RealName: "Bob Builder"},
+	}
+
+	tests := []struct {
+		pref    string
+		dm      string
+		group   string
+		mention string
+	}{
+		{pref: "", dm: "DM with Jane Doe", group: "Group DM with Jane Doe, Bob Builder", mention: "ping @jdoe"},
+		{pref: "display", dm: "DM with Jane", group: "Group DM with Jane, U2", mention: "ping @Jane"},
+		{pref: "real", dm: "DM with Jane Doe", group: "Group DM with Jane Doe, Bob Builder", mention: "ping @Jane Doe"},
+		{pref: "username", dm: "DM with jdoe", group: "Group DM with jdoe, bob", mention: "ping @jdoe"},
+	}
+	t.Cleanup(func() { text.SetNamePref("") })
+	for _, tt := range tests {
+		text.SetNamePref(tt.pref)
+
+		dm := mapChannel(rawChannel{Channel: Channel{ID: "D1", User: "U1", MemberCount: 2, IsIM: true, IsPrivate: true}}, users)
+		group := mapChannel(rawChannel{Channel: Channel{ID: "G1", Name: "mpdm-jdoe--bob-1", Members: []string{"U1", "U2"}, MemberCount: 2, IsMpIM: true, IsPrivate: true}, NameNormalized: "mpdm-jdoe--bob-1"}, users)
+		mention := text.ProcessTextWithUsers("ping <@U1>", users)
+
+		if dm.Purpose != tt.dm || group.Purpose != tt.group || mention != tt.mention {
+			t.Errorf("pref %q: got %q, %q, %q, expected %q, %q, %q", tt.pref, dm.Purpose, group.Purpose, mention, tt.dm, tt.group, tt.mention)
+		}
+	}
+}
+
+func TestResolveChannelID_ChannelAndDMCollide(t *testing.T) {
+	for _, prefix := range []*string{nil, strPtr("")} {
+		setPrefixEnv(t, prefix, prefix)
+
+		// a user named general, with a DM, next to #general
+		users := map[string]slack.User{"U1": {ID: "U1", Name: "general"}}
+		chn := mapChannel(rawChannel{Channel: Channel{ID: "C1", Name: "general", MemberCount: 3}, NameNormalized: "general"}, users)
+		dm := mapChannel(rawChannel{Channel: Channel{ID: "D1", User: "U1", IsIM: true}}, users)
+
+		ap, _ := newTestProvider(0)
+		ap.channels = map[string]Channel{chn.ID: chn, dm.ID: dm}
+		ap.channelsInv = map[string]string{chn.Name: chn.ID, dm.Name: dm.ID}
+		if prefix != nil {
+			// the names collide in the inverse map, the last one wins
+			ap.channelsInv = map[string]string{"general": dm.ID}
+		}
+
+		if id, err := ap.ResolveChannelID("#general", false); err != nil || id != "C1" {
+			t.Errorf("prefix %v: expected # to force the channel, got %q, %v", prefix, id, err)
+		}
+		if id, err := ap.ResolveChannelID("@general", false); err != nil || id != "D1" {
+			t.Errorf("prefix %v: expected @ to force the DM, got %q, %v", prefix, id, err)
+		}
+		if prefix != nil {
+			continue
+		}
+
+		_, err := ap.ResolveChannelID("general", false)
+		var ambiguous *AmbiguousChannelError
+		if !errors.As(err, &ambiguous) {
+			t.Fatalf("expected an AmbiguousChannelError, got %v", err)
+		}
+		if !slices.Equal(ambiguous.Candidates, []string{"#general", "@general"}) {
+			t.Errorf("expected both candidates, channel first, got %v", ambiguous.Candidates)
+		}
+
+		// Lookup, which cannot fail, prefers the channel
+		if c, ok := ap.ProvideChannelsMaps().Lookup("general", false); !ok || c.ID != "C1" {
+			t.Errorf("expected Lookup to prefer the channel, got %+v", c)
+		}
+	}
+}
+
+func TestResolveChannelID_PrefixForcesKind(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"D1": {ID: "D1", Name: "@alice", IsIM: true}}
+	ap.channelsInv = map[string]string{"@alice": "D1"}
+
+	if id, err := ap.ResolveChannelID("alice", false); err != nil || id != "D1" {
+		t.Errorf("expected a bare name to fall back to the DM, got %q, %v", id, err)
+	}
+
+	var notFound *ChannelNotFoundError
+	if _, err := ap.ResolveChannelID("#alice", false); !errors.As(err, &notFound) {
+		t.Errorf("expected #alice not to resolve to the DM, got %v", err)
+	}
+}
+
+func TestResolveChannelID_Forms(t *testing.T) {
+	users := map[string]slack.User{"U00000001": {ID: "U00000001", Name: "alice"}}
+	chn := mapChannel(rawChannel{Channel: Channel{ID: "C1", Name: "general", MemberCount: 3}, NameNormalized: "general"}, users)
+	dm := mapChannel(rawChannel{Channel: Channel{ID: "D1", User: "U00000001", IsIM: true}}, users)
+
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{chn.ID: chn, dm.ID: dm}
+	ap.channelsInv = map[string]string{chn.Name: chn.ID, dm.Name: dm.ID}
+
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{ref: "general", expected: "C1"},
+		{ref: "#general", expected: "C1"},
+		{ref: "C1", expected: "C1"},
+		{ref: "@alice", expected: "D1"},
+		{ref: "U00000001", expected: "D1"},
+		// without a cached DM the user ID is passed on, Slack opens one
+		{ref: "U00000002", expected: "U00000002"},
+	}
+	for _, tt := range tests {
+		if id, err := ap.ResolveChannelID(tt.ref, false); err != nil || id != tt.expected {
+			t.Errorf("%s: expected %q, got %q, %v", tt.ref, tt.expected, id, err)
+		}
+	}
+}
+
+func TestResolveChannelID_CaseSensitivity(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{
+		"C1": {ID: "C1", Name: "#general"},
+		"D1": {ID: "D1", Name: "@alice", IsIM: true},
+	}
+	ap.channelsInv = map[string]string{"#general": "C1", "@alice": "D1"}
+
+	for _, ref := range []string{"#General", "GENERAL"} {
+		if id, err := ap.ResolveChannelID(ref, false); err != nil || id != "C1" {
+			t.Errorf("%s: expected C1, got %q, %v", ref, id, err)
+		}
+	}
+	if id, err := ap.ResolveChannelID("@Alice", false); err != nil || id != "D1" {
+		t.Errorf("expected @Alice to resolve to D1, got %q, %v", id, err)
+	}
+
+	var notFound *ChannelNotFoundError
+	if _, err := ap.ResolveChannelID("#General", true); !errors.As(err, &notFound) {
+		t.Errorf("expected #General not to resolve case-sensitively, got %v", err)
+	}
+	if id, err := ap.ResolveChannelID("#general", true); err != nil || id != "C1" {
+		t.Errorf("expected the exact name to resolve, got %q, %v", id, err)
+	}
+	if _, ok := ap.ProvideChannelsMaps().Lookup("#General", true); ok {
+		t.Error("expected Lookup not to match #General case-sensitively")
+	}
+}
+
+func TestChannelNameByID(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#general", MemberCount: 3}}
+
+	if c, ok := ap.ChannelByID("C1"); !ok || c.MemberCount != 3 {
+		t.Errorf("expected the cached channel, got %+v, %v", c, ok)
+	}
+	if name, ok := ap.ChannelNameByID("C1"); !ok || name != "#general" {
+		t.Errorf("expected #general, got %q, %v", name, ok)
+	}
+	if _, ok := ap.ChannelNameByID("C2"); ok {
+		t.Errorf("expected an unknown channel not to be found")
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
+	"github.com/slack-go/slack"
+)
+
+// ErrOffline is returned for operations that need the Slack API while the
+// provider runs in offline mode.
+var ErrOffline = errors.New("offline mode: Slack API calls are disabled (unset SLACK_MCP_OFFLINE to connect)")
+
+// fixturesDir returns SLACK_MCP_FIXTURES_DIR. Live API responses are recorded
+// there, and replayed from there in offline mode.
+func fixturesDir() string {
+	return os.Getenv("SLACK_MCP_FIXTURES_DIR")
+}
+
+// withFixtureRecording wraps roundTripper to record responses as fixtures
+// when SLACK_MCP_FIXTURES_DIR is set.
+func withFixtureRecording(roundTripper http.RoundTripper) http.RoundTripper {
+	if dir := fixturesDir(); dir != "" {
+		return transport.NewRecord(roundTripper, dir)
+	}
+	return roundTripper
+}
+
+// newReplayClient returns a client answering from the fixtures recorded in
+// dir, it never reaches Slack.
+func newReplayClient(dir string) *slack.Client {
+	return slack.New("xoxp-offline", slack.OptionHTTPClient(&http.Client{
+		Transport: transport.NewReplay(dir),
+	}))
+}
+
+// isOfflineMode reports whether SLACK_MCP_OFFLINE asks for the provider to
+// serve exclusively from the on-disk caches.
+func isOfflineMode() bool {
+	switch os.Getenv("SLACK_MCP_OFFLINE") {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// newOffline creates an ApiProvider which never boots a Slack client: no
+// token is needed and AuthTest is not called. Users and channels are loaded
+// from the cache files only, API calls are answered from the recorded
+// fixtures when SLACK_MCP_FIXTURES_DIR is set and fail with ErrOffline
+// otherwise.
+func newOffline() *ApiProvider {
+	// Live runs key the caches by team, the last written workspace is served
+	usersCache := os.Getenv("SLACK_MCP_USERS_CACHE")
+	if usersCache == "" {
+		usersCache = newestCacheFile("users_cache.json", "users_cache_[A-Z]*.json")
+	}
+	if usersCache == "" {
+		usersCache = filepath.Join(getCacheDir(), "users_cache.json")
+	}
+
+	channelsCache := os.Getenv("SLACK_MCP_CHANNELS_CACHE")
+	if channelsCache == "" {
+		// The channels of the workspace the users are served from. Prefer
+		// the cache written with session tokens, which are the most capable
+		// and hence give the most complete fixtures
+		team := cacheTeamID(usersCache, "users_cache.json")
+		channelsCache = newestCacheFile(teamCacheName("channels_cache_v2.json", team))
+		if channelsCache == "" {
+			channelsCache = newestCacheFile(teamCacheName("channels_cache.json", team))
+		}
+	}
+	if channelsCache == "" {
+		channelsCache = filepath.Join(getCacheDir(), "channels_cache.json")
+	}
+
+	log.Printf("Offline mode: serving users from %q and channels from %q", usersCache, channelsCache)
+
+	return &ApiProvider{
+		boot: func(ap *ApiProvider) (*slack.Client, error) {
+			return nil, ErrOffline
+		},
+
+		users:               make(map[string]slack.User),
+		usersInv:            map[string]string{},
+		usersDisplayNameInv: map[string]string{},
+		usersRealNameInv:    map[string]string{},
+		usersEmailInv:       map[string]string{},
+		usersCache:          usersCache,
+
+		channels:      make(map[string]Channel),
+		channelsInv:   map[string]string{},
+		channelsCache: channelsCache,
+
+		rateTier: rateTier(),
+
+		offline: true,
+	}
+}
+
+// IsOffline reports whether the provider serves from the caches only.
+func (ap *ApiProvider) IsOffline() bool {
+	return ap.offline
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// Permalink returns the permalink of the message ts in channelID. It is built
+// from the workspace URL returned by auth.test, so no API call is made, and
+// only falls back to chat.getPermalink when the workspace URL is unknown.
+// threadTs is the parent of a thread reply and may be empty.
+func (ap *ApiProvider) Permalink(ctx context.Context, channelID, ts, threadTs string) (string, error) {
+	if info := ap.authTest(); info != nil && info.URL != "" {
+		return buildPermalink(info.URL, channelID, ts, threadTs), nil
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return "", err
+	}
+
+	return client.GetPermalinkContext(ctx, &slack.PermalinkParameters{
+		Channel: channelID,
+		Ts:      ts,
+	})
+}
+
+// buildPermalink follows the format Slack uses for permalinks, e.g.
+// https://team.slack.com/archives/C12345678/p1700000000000100, adding the
+// parent thread for replies.
+func buildPermalink(teamURL, channelID, ts, threadTs string) string {
+	link := strings.TrimSuffix(teamURL, "/") + "/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+
+	if threadTs != "" && threadTs != ts {
+		link += "?" + url.Values{
+			"thread_ts": {threadTs},
+			"cid":       {channelID},
+		}.Encode()
+	}
+
+	return link
+}
+
+// ErrInvalidPermalink is returned for URLs which are not Slack permalinks.
+var ErrInvalidPermalink = errors.New("not a Slack permalink")
+
+// PermalinkParts are the components of a permalink.
+type PermalinkParts struct {
+	TeamURL   string // e.g. https://team.slack.com/
+	ChannelID string
+	Ts        string // empty for a link to the channel itself
+	ThreadTs  string // the parent of a thread reply, empty otherwise
+}
+
+// ParsePermalink splits a permalink in the format buildPermalink follows,
+// e.g. https://team.slack.com/archives/C12345678/p1700000000000100, into
+// its components. The thread_ts query of thread replies is kept.
+func ParsePermalink(link string) (PermalinkParts, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return PermalinkParts{}, fmt.Errorf("%w: %q", ErrInvalidPermalink, link)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || len(segments) > 3 || segments[0] != "archives" || segments[1] == "" {
+		return PermalinkParts{}, fmt.Errorf("%w: %q, expected https://<team>.slack.com/archives/<channel>/p<ts>", ErrInvalidPermalink, link)
+	}
+
+	parts := PermalinkParts{
+		TeamURL:   u.Scheme + "://" + u.Host + "/",
+		ChannelID: segments[1],
+	}
+	if len(segments) == 3 {
+		ts, ok := permalinkTs(segments[2])
+		if !ok {
+			return PermalinkParts{}, fmt.Errorf("%w: %q is not a message ID like p1700000000000100", ErrInvalidPermalink, segments[2])
+		}
+		parts.Ts = ts
+	}
+	if threadTs := u.Query().Get("thread_ts"); threadTs != parts.Ts {
+		parts.ThreadTs = threadTs
+	}
+
+	return parts, nil
+}
+
+// permalinkTs turns the message ID of a permalink back into the message
+// timestamp: p1700000000000100 is 1700000000.000100.
+func permalinkTs(id string) (string, bool) {
+	digits, ok := strings.CutPrefix(id, "p")
+	if !ok || len(digits) <= 6 || strings.Trim(digits, "0123456789") != "" {
+		return "", false
+	}
+	return digits[:len(digits)-6] + "." + digits[len(digits)-6:], true
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+func TestBuildPermalink(t *testing.T) {
+	tests := []struct {
+		name     string
+		teamURL  string
+		ts       string
+		threadTs string
+		expected string
+	}{
+		{
+			name:     "channel message",
+			teamURL:  "https://acme.slack.com/",
+			ts:       "1700000000.000100",
+			expected: "https://acme.slack.com/archives/C12345678/p1700000000000100",
+		},
+		{
+			name:     "thread parent",
+			teamURL:  "https://acme.slack.com",
+			ts:       "1700000000.000100",
+			threadTs: "1700000000.000100",
+			expected: "https://acme.slack.com/archives/C12345678/p1700000000000100",
+		},
+		{
+			name:     "thread reply",
+			teamURL:  "https://acme.slack.com/",
+			ts:       "1700000050.000200",
+			threadTs: "1700000000.000100",
+			expected: "https://acme.slack.com/archives/C12345678/p1700000050000200?cid=C12345678&thread_ts=1700000000.000100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildPermalink(tt.teamURL, "C12345678", tt.ts, tt.threadTs); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPermalink_FromTeamInfo(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	ap.authResponse = &slack2.AuthTestResponse{URL: "https://acme.slack.com/"}
+
+	// no client is configured, so any API call would fail
+	link, err := ap.Permalink(context.Background(), "C12345678", "1700000000.000100", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link != "https://acme.slack.com/archives/C12345678/p1700000000000100" {
+		t.Errorf("unexpected permalink %q", link)
+	}
+}
+
+func TestPermalink_FallsBackToAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.getPermalink" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C12345678", "permalink": "https://acme.slack.com/archives/C12345678/p1700000000000100"}`))
+	}))
+	defer srv.Close()
+
+	ap, _ := newTestProvider(0)
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+
+	link, err := ap.Permalink(context.Background(), "C12345678", "1700000000.000100", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link != "https://acme.slack.com/archives/C12345678/p1700000000000100" {
+		t.Errorf("unexpected permalink %q", link)
+	}
+}
+
+func TestParsePermalink(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected PermalinkParts
+	}{
+		{
+			name:     "channel message",
+			link:     "https://acme.slack.com/archives/C12345678/p1700000000123456",
+			expected: PermalinkParts{TeamURL: "https://acme.slack.com/", ChannelID: "C12345678", Ts: "1700000000.123456"},
+		},
+		{
+			name:     "thread reply",
+			link:     "https://acme.slack.com/archives/C12345678/p1700000050000200?thread_ts=1700000000.000100&cid=C12345678",
+			expected: PermalinkParts{TeamURL: "https://acme.slack.com/", ChannelID: "C12345678", Ts: "1700000050.000200", ThreadTs: "1700000000.000100"},
+		},
+		{
+			name:     "thread parent",
+			link:     " https://acme.slack.com/archives/C12345678/p1700000000000100?thread_ts=1700000000.000100 ",
+			expected: PermalinkParts{TeamURL: "https://acme.slack.com/", ChannelID: "C12345678", Ts: "1700000000.000100"},
+		},
+		{
+			name:     "channel",
+			link:     "https://acme.enterprise.slack.com/archives/C12345678/",
+			expected: PermalinkParts{TeamURL: "https://acme.enterprise.slack.com/", ChannelID: "C12345678"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePermalink(tt.link)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	// what buildPermalink builds is parsed back
+	link := buildPermalink("https://acme.slack.com/", "C12345678", "1700000050.000200", "1700000000.000100")
+	if got, err := ParsePermalink(link); err != nil || got.Ts != "1700000050.000200" || got.ThreadTs != "1700000000.000100" {
+		t.Errorf("expected the built permalink to round-trip, got %+v (%v)", got, err)
+	}
+
+	for _, link := range []string{
+		"",
+		"C12345678/p1700000000123456",
+		"https://acme.slack.com/team/U12345678",
+		"https://acme.slack.com/archives/C12345678/1700000000.123456",
+		"https://acme.slack.com/archives/C12345678/p123",
+		"ftp://acme.slack.com/archives/C12345678/p1700000000123456",
+	} {
+		if _, err := ParsePermalink(link); !errors.Is(err, ErrInvalidPermalink) {
+			t.Errorf("expected ErrInvalidPermalink for %q, got %v", link, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	slack2 "github.com/rusq/slack"
+)
+
+// SavedItem is a pinned or starred message or file, with who saved it and
+// when.
+type SavedItem struct {
+	Type      string    `json:"type"`
+	ChannelID string    `json:"channelID"`
+	Timestamp string    `json:"ts,omitempty"`     // message timestamp
+	FileID    string    `json:"fileID,omitempty"` // set for file items
+	UserID    string    `json:"userID,omitempty"` // author of the message
+	Text      string    `json:"text"`
+	SavedBy   string    `json:"savedBy,omitempty"`     // user ID, pins only
+	SavedName string    `json:"savedByName,omitempty"` // display name, pins only
+	SavedAt   time.Time `json:"savedAt"`
+}
+
+// GetPins returns the items pinned to a channel with who pinned them and
+// when. channelRef is an ID or a name with or without its prefix.
+func (ap *ApiProvider) GetPins(ctx context.Context, channelRef string) ([]SavedItem, error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ap.ProvideEnterprise()
+	if err != nil {
+		return nil, err
+	}
+
+	pins, err := client.PinsList(ctx, channelID)
+	if err != nil {
+		return nil, scopeError("pins.list", err)
+	}
+
+	var pinners []string
+	for _, p := range pins {
+		pinners = append(pinners, p.CreatedBy)
+	}
+	users := ap.ResolveUsers(pinners)
+
+	items := make([]SavedItem, 0, len(pins))
+	for _, p := range pins {
+		item := savedItem(p.Type, p.Channel, p.Message, p.File, p.Created)
+		if item.ChannelID == "" {
+			item.ChannelID = channelID
+		}
+		item.SavedBy = p.CreatedBy
+		if u, ok := users[p.CreatedBy]; ok {
+			item.SavedName = text.UserDisplayName(u)
+		} else {
+			item.SavedName = p.CreatedBy
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetStarred returns up to limit items the user starred (saved), newest
+// first. Bot tokens cannot list stars and get ErrScopeMissing.
+func (ap *ApiProvider) GetStarred(ctx context.Context, limit int) ([]SavedItem, error) {
+	if ap.IsBotToken() {
+		return nil, fmt.Errorf("%w: stars.list is not available to bot tokens", ErrScopeMissing)
+	}
+
+	client, err := ap.ProvideEnterprise()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var (
+		items  []SavedItem
+		cursor string
+	)
+	for len(items) < limit {
+		stars, next, err := client.StarsList(ctx, cursor, min(limit-len(items), 100))
+		if err != nil {
+			return nil, scopeError("stars.list", err)
+		}
+		for _, s := range stars {
+			items = append(items, savedItem(s.Type, s.Channel, s.Message, s.File, s.DateCreate))
+		}
+
+		if next == "" || len(stars) == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+func savedItem(typ, channelID string, msg *slack2.Message, file *slack2.File, created int64) SavedItem {
+	item := SavedItem{
+		Type:      typ,
+		ChannelID: channelID,
+		SavedAt:   time.Unix(created, 0).UTC(),
+	}
+
+	switch {
+	case msg != nil:
+		item.Timestamp = msg.Timestamp
+		item.UserID = msg.User
+		item.Text = msg.Text
+	case file != nil:
+		item.FileID = file.ID
+		item.UserID = file.User
+		item.Text = file.Title
+	}
+
+	return item
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+func TestGetPins(t *testing.T) {
+	ap, _ := newTestProvider(0, withEdgeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/pins.list" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("channel") != "C1" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"items": [
+				{"type": "message", "channel": "C1", "created": 1700000500, "created_by": "U2",
+				 "message": {"type": "message", "user": "U1", "text": "runbook: restart the db", "ts": "1700000100.000100"}},
+				{"type": "file", "created": 1700000600, "created_by": "U9",
+				 "file": {"id": "F1", "title": "architecture.png", "user": "U1"}}
+			]
+		}`))
+	}), slack2.AuthTestResponse{TeamID: "T1"}))
+	ap.users["U2"] = slack.User{ID: "U2", Name: "bob", RealName: "Bob Builder"}
+	ap.channels = map[string]Channel{"C1": {ID: "C1", Name: "#ops"}}
+	ap.channelsInv = map[string]string{"#ops": "C1"}
+
+	pins, err := ap.GetPins(context.Background(), "#ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pins) != 2 {
+		t.Fatalf("expected 2 pins, got %+v", pins)
+	}
+
+	msg := pins[0]
+	if msg.Timestamp != "1700000100.000100" || msg.UserID != "U1" || msg.Text != "runbook: restart the db" {
+		t.Errorf("unexpected message pin %+v", msg)
+	}
+	if msg.SavedBy != "U2" || msg.SavedName != "Bob Builder" || !msg.SavedAt.Equal(time.Unix(1700000500, 0)) {
+		t.Errorf("unexpected pin metadata %+v", msg)
+	}
+
+	file := pins[1]
+	if file.FileID != "F1" || file.ChannelID != "C1" || file.Text != "architecture.png" {
+		t.Errorf("unexpected file pin %+v", file)
+	}
+	// unknown pinners fall back to their IDs
+	if file.SavedName != "U9" {
+		t.Errorf("expected unknown pinner to fall back to ID, got %q", file.SavedName)
+	}
+}
+
+func TestGetStarred(t *testing.T) {
+	ap, _ := newTestProvider(0, withEdgeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/stars.list" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"items": [
+				{"type": "message", "channel": "C1", "date_create": 1700000700,
+				 "message": {"type": "message", "user": "U1", "text": "read later", "ts": "1700000200.000100"}}
+			],
+			"response_metadata": {"next_cursor": ""}
+		}`))
+	}), slack2.AuthTestResponse{TeamID: "T1"}))
+
+	stars, err := ap.GetStarred(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stars) != 1 || stars[0].Text != "read later" || !stars[0].SavedAt.Equal(time.Unix(1700000700, 0)) {
+		t.Errorf("unexpected stars %+v", stars)
+	}
+}
+
+func TestGetStarred_BotToken(t *testing.T) {
+	ap, _ := newTestProvider(0, withEdgeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("bot tokens must not call %q", r.URL.Path)
+	}), slack2.AuthTestResponse{TeamID: "T1"}))
+	ap.isBotToken = true
+
+	if _, err := ap.GetStarred(context.Background(), 10); !errors.Is(err, ErrScopeMissing) {
+		t.Fatalf("expected ErrScopeMissing, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	slack2 "github.com/rusq/slack"
+	"github.com/rusq/slackdump/v3/auth"
+	"github.com/slack-go/slack"
+)
+
+func TestProvideEnterprise_UsesProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a forward proxy receives the absolute URL of the target
+		proxied = append(proxied, r.URL.String())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "items": []}`))
+	}))
+	defer proxy.Close()
+
+	t.Setenv("SLACK_MCP_PROXY", proxy.URL)
+
+	prov, err := auth.NewValueAuth("xoxc-test", "xoxd-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ap, _ := newTestProvider(0)
+	ap.authProvider = &prov
+	ap.authResponse = &slack2.AuthTestResponse{URL: "http://grid.example.invalid/", TeamID: "T1"}
+	ap.clientGeneric = slack.New("xoxc-test")
+
+	client, err := ap.ProvideEnterprise()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.PinsList(context.Background(), "C1"); err != nil {
+		t.Fatalf("expected the request to succeed through the proxy: %v", err)
+	}
+
+	if len(proxied) != 1 || proxied[0] != "http://grid.example.invalid/api/pins.list" {
+		t.Errorf("expected the edge client to route through SLACK_MCP_PROXY, got %v", proxied)
+	}
+}
+
+func TestRateLimitRetryClient_UsesProxyWithoutCookies(t *testing.T) {
+	var proxied []*http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "user": "alice", "team": "Acme"}`))
+	}))
+	defer proxy.Close()
+
+	t.Setenv("SLACK_MCP_PROXY", proxy.URL)
+
+	api := slack.New("xoxp-test", slack.OptionHTTPClient(rateLimitRetryClient()), slack.OptionAPIURL("http://slack.example.invalid/api/"))
+	if _, err := api.AuthTest(); err != nil {
+		t.Fatalf("expected the request to succeed through the proxy: %v", err)
+	}
+
+	if len(proxied) != 1 || proxied[0].URL.String() != "http://slack.example.invalid/api/auth.test" {
+		t.Fatalf("expected the xoxp client to route through SLACK_MCP_PROXY, got %d requests", len(proxied))
+	}
+	if cookie := proxied[0].Header.Get("Cookie"); cookie != "" {
+		t.Errorf("expected no session cookie on an xoxp request, got %q", cookie)
+	}
+}
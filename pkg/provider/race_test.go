@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// TestCaches_ConcurrentRefreshAndRead is meant for go test -race: it reloads
+// the caches while other goroutines resolve users and channels.
+func TestCaches_ConcurrentRefreshAndRead(t *testing.T) {
+	dir := t.TempDir()
+	usersCache := filepath.Join(dir, "users.json")
+	channelsCache := filepath.Join(dir, "channels.json")
+
+	var users, channels string
+	for i := range 200 {
+		sep := ","
+		if i == 0 {
+			sep = ""
+		}
+		users += fmt.Sprintf(`%s{"id": "U%d", "name": "user%d", "real_name": "User %d"}`, sep, i, i, i)
+		channels += fmt.Sprintf(`%s{"id": "C%d", "name": "#chan%d", "memberCount": %d}`, sep, i, i, i)
+	}
+	if err := os.WriteFile(usersCache, []byte(`{"schema_version": 1, "entries": [`+users+`]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(channelsCache, []byte(`{"schema_version": 1, "entries": [`+channels+`]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ap := &ApiProvider{
+		users:               make(map[string]slack.User),
+		usersInv:            map[string]string{},
+		usersDisplayNameInv: map[string]string{},
+		usersRealNameInv:    map[string]string{},
+		usersEmailInv:       map[string]string{},
+		usersCache:          usersCache,
+		channels:            make(map[string]Channel),
+		channelsInv:         map[string]string{},
+		channelsCache:       channelsCache,
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if err := ap.RefreshUsers(ctx); err != nil {
+					t.Error(err)
+				}
+				if err := ap.RefreshChannels(ctx); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				ap.ResolveUser(fmt.Sprintf("U%d", i))
+				_ = ap.ProvideUsersMap().UsersInv[fmt.Sprintf("user%d", i)]
+				_, _ = ap.ResolveChannelID(fmt.Sprintf("#chan%d", i), false)
+				_ = ap.ProvideChannelsMaps().Channels[fmt.Sprintf("C%d", i)]
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, ok := ap.ResolveUser("U199"); !ok {
+		t.Error("expected the users cache to be loaded")
+	}
+	if _, err := ap.ResolveChannelID("#chan199", false); err != nil {
+		t.Errorf("expected the channels cache to be loaded: %v", err)
+	}
+}
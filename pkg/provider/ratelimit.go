@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// rateLimitMaxRetries is how many times a rate limited request is retried
+// before the response is handed back to the caller as is, unless
+// SLACK_MCP_MAX_RETRIES says otherwise.
+const rateLimitMaxRetries = 3
+
+// maxRetries returns how many times rate limited requests are retried, read
+// from SLACK_MCP_MAX_RETRIES.
+func maxRetries() int {
+	if s := os.Getenv("SLACK_MCP_MAX_RETRIES"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			return n
+		}
+		log.Printf("Invalid SLACK_MCP_MAX_RETRIES %q, using %d", s, rateLimitMaxRetries)
+	}
+	return rateLimitMaxRetries
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+)
+
+var ErrMessageNotFound = errors.New("message not found")
+
+// ReactionSummary describes a single emoji reaction on a message.
+type ReactionSummary struct {
+	Name      string   `json:"name"`
+	Count     int      `json:"count"`
+	UserIDs   []string `json:"userIDs"`
+	UserNames []string `json:"userNames"`
+}
+
+// GetReactions returns the reactions on the message identified by channelRef
+// and ts using reactions.get, with reacting users resolved to display names.
+func (ap *ApiProvider) GetReactions(ctx context.Context, channelRef, ts string) ([]ReactionSummary, error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, err := client.GetReactionsContext(ctx,
+		slack.NewRefToMessage(channelID, ts),
+		slack.GetReactionsParameters{Full: true},
+	)
+	if err != nil {
+		var slackErr slack.SlackErrorResponse
+		if errors.As(err, &slackErr) && slackErr.Err == "message_not_found" {
+			return nil, fmt.Errorf("%w: channel %s, ts %s", ErrMessageNotFound, channelID, ts)
+		}
+		return nil, err
+	}
+
+	var userIDs []string
+	for _, r := range reactions {
+		userIDs = append(userIDs, r.Users...)
+	}
+	users := ap.ResolveUsers(userIDs)
+
+	summaries := make([]ReactionSummary, 0, len(reactions))
+	for _, r := range reactions {
+		names := make([]string, 0, len(r.Users))
+		for _, id := range r.Users {
+			if u, ok := users[id]; ok {
+				names = append(names, text.UserDisplayName(u))
+			} else {
+				names = append(names, id)
+			}
+		}
+
+		summaries = append(summaries, ReactionSummary{
+			Name:      r.Name,
+			Count:     r.Count,
+			UserIDs:   r.Users,
+			UserNames: names,
+		})
+	}
+
+	return summaries, nil
+}
+
+const (
+	// defaultReactionScanMessages bounds how much history is scanned when
+	// aggregating reactions and no budget is given.
+	defaultReactionScanMessages = 1000
+	// reactionScanPageSize is the conversations.history page size used for
+	// scanning, the maximum Slack recommends.
+	reactionScanPageSize = 200
+)
+
+// ReactionTotal is the number of times an emoji was used across messages.
+type ReactionTotal struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	Messages int    `json:"messages"`
+}
+
+// ReactedMessage is a message ranked by the reactions it received.
+type ReactedMessage struct {
+	Timestamp string `json:"ts"`
+	UserID    string `json:"userID"`
+	Text      string `json:"text"`
+	Reactions int    `json:"reactions"`
+}
+
+// ChannelReactionStats aggregates the reactions and pins found in a channel's
+// recent history.
+type ChannelReactionStats struct {
+	ChannelID       string           `json:"channelID"`
+	MessagesScanned int              `json:"messagesScanned"`
+	Totals          []ReactionTotal  `json:"totals"`
+	TopMessages     []ReactedMessage `json:"topMessages"`
+	PinnedMessages  []string         `json:"pinnedMessages"`
+}
+
+// GetChannelReactionStats scans at most maxMessages of the channel's most
+// recent messages and aggregates the reactions conversations.history returns
+// inline, so no per-message reactions.get calls are made. It returns totals
+// per emoji and the top most reacted messages, together with the timestamps
+// of pinned messages.
+func (ap *ApiProvider) GetChannelReactionStats(ctx context.Context, channelRef string, maxMessages, top int) (*ChannelReactionStats, error) {
+	channelID, err := ap.ResolveChannelID(channelRef, false)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, err
+	}
+
+	if maxMessages <= 0 {
+		maxMessages = defaultReactionScanMessages
+	}
+
+	var messages []slack.Message
+	params := &slack.GetConversationHistoryParameters{ChannelID: channelID}
+	for len(messages) < maxMessages {
+		params.Limit = min(reactionScanPageSize, maxMessages-len(messages))
+
+		history, err := client.GetConversationHistoryContext(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, history.Messages...)
+
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+	if len(messages) > maxMessages {
+		messages = messages[:maxMessages]
+	}
+
+	stats := aggregateReactions(messages, top)
+	stats.ChannelID = channelID
+	return stats, nil
+}
+
+// aggregateReactions sums up the inline reactions of messages in one pass.
+func aggregateReactions(messages []slack.Message, top int) *ChannelReactionStats {
+	stats := &ChannelReactionStats{MessagesScanned: len(messages)}
+
+	totals := make(map[string]*ReactionTotal)
+	var reacted []ReactedMessage
+	for _, msg := range messages {
+		if len(msg.PinnedTo) > 0 {
+			stats.PinnedMessages = append(stats.PinnedMessages, msg.Timestamp)
+		}
+
+		var count int
+		for _, r := range msg.Reactions {
+			t, ok := totals[r.Name]
+			if !ok {
+				t = &ReactionTotal{Name: r.Name}
+				totals[r.Name] = t
+			}
+			t.Count += r.Count
+			t.Messages++
+			count += r.Count
+		}
+
+		if count > 0 {
+			reacted = append(reacted, ReactedMessage{
+				Timestamp: msg.Timestamp,
+				UserID:    msg.User,
+				Text:      msg.Text,
+				Reactions: count,
+			})
+		}
+	}
+
+	for _, t := range totals {
+		stats.Totals = append(stats.Totals, *t)
+	}
+	sort.Slice(stats.Totals, func(i, j int) bool {
+		if stats.Totals[i].Count != stats.Totals[j].Count {
+			return stats.Totals[i].Count > stats.Totals[j].Count
+		}
+		return stats.Totals[i].Name < stats.Totals[j].Name
+	})
+
+	sort.SliceStable(reacted, func(i, j int) bool {
+		return reacted[i].Reactions > reacted[j].Reactions
+	})
+	if top > 0 && len(reacted) > top {
+		reacted = reacted[:top]
+	}
+	stats.TopMessages = reacted
+
+	return stats
+}
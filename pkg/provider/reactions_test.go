@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// reactionsTestProvider knows alice by display name, bob by real name and
+// #general, and answers Web API calls with handler.
+func reactionsTestProvider(t *testing.T, handler http.HandlerFunc) testProviderOption {
+	return func(ap *ApiProvider) {
+		withUsers(
+			slack.User{ID: "U1", Name: "alice", Profile: slack.UserProfile{DisplayName: "Ali"}},
+			slack.User{ID: "U2", Name: "bob", RealName: "Bob Builder"},
+		)(ap)
+		withChannels(Channel{ID: "C1", Name: "#general"})(ap)
+		withWebAPI(t, handler)(ap)
+	}
+}
+
+func TestGetReactions(t *testing.T) {
+	ap, _ := newTestProvider(0, reactionsTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/reactions.get" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("channel") != "C1" || r.Form.Get("timestamp") != "1700000000.000100" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"type": "message",
+			"channel": "C1",
+			"message": {
+				"type": "message",
+				"ts": "1700000000.000100",
+				"reactions": [
+					{"name": "thumbsup", "count": 2, "users": ["U1", "U2"]},
+					{"name": "eyes", "count": 1, "users": ["U9"]}
+				]
+			}
+		}`))
+	}))
+
+	reactions, err := ap.GetReactions(context.Background(), "#general", "1700000000.000100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reactions) != 2 {
+		t.Fatalf("expected 2 reactions, got %d", len(reactions))
+	}
+
+	thumbs := reactions[0]
+	if thumbs.Name != "thumbsup" || thumbs.Count != 2 {
+		t.Errorf("unexpected reaction %+v", thumbs)
+	}
+	if len(thumbs.UserNames) != 2 || thumbs.UserNames[0] != "Ali" || thumbs.UserNames[1] != "Bob Builder" {
+		t.Errorf("unexpected user names %v", thumbs.UserNames)
+	}
+
+	// unknown users fall back to their IDs
+	if reactions[1].UserNames[0] != "U9" {
+		t.Errorf("expected unknown user to fall back to ID, got %v", reactions[1].UserNames)
+	}
+}
+
+func TestGetReactions_MessageNotFound(t *testing.T) {
+	ap, _ := newTestProvider(0, reactionsTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": false, "error": "message_not_found"}`))
+	}))
+
+	_, err := ap.GetReactions(context.Background(), "C1", "1700000000.000100")
+	if !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("expected ErrMessageNotFound, got %v", err)
+	}
+}
+
+func TestGetChannelReactionStats(t *testing.T) {
+	var pages int
+	ap, _ := newTestProvider(0, reactionsTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.history" {
+			t.Errorf("unexpected path %q, reactions must come from history", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		pages++
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{
+				"ok": true,
+				"has_more": true,
+				"response_metadata": {"next_cursor": "page2"},
+				"messages": [
+					{"type": "message", "user": "U1", "text": "shipped", "ts": "1700000300.000100",
+					 "reactions": [{"name": "tada", "count": 3, "users": ["U1", "U2", "U3"]}, {"name": "rocket", "count": 1, "users": ["U2"]}]},
+					{"type": "message", "user": "U2", "text": "lunch?", "ts": "1700000200.000100"},
+					{"type": "message", "user": "U2", "text": "runbook", "ts": "1700000100.000100", "pinned_to": ["C1"],
+					 "reactions": [{"name": "tada", "count": 1, "users": ["U1"]}]}
+				]
+			}`))
+			return
+		}
+		if r.Form.Get("cursor") != "page2" || r.Form.Get("limit") != "1" {
+			t.Errorf("expected the second page to be limited by the budget, got %v", r.Form)
+		}
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"has_more": true,
+			"response_metadata": {"next_cursor": "page3"},
+			"messages": [
+				{"type": "message", "user": "U1", "text": "hello", "ts": "1700000000.000100",
+				 "reactions": [{"name": "wave", "count": 2, "users": ["U1", "U2"]}]}
+			]
+		}`))
+	}))
+
+	stats, err := ap.GetChannelReactionStats(context.Background(), "#general", 4, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pages != 2 || stats.MessagesScanned != 4 {
+		t.Errorf("expected 4 messages over 2 pages, got %d over %d", stats.MessagesScanned, pages)
+	}
+	if stats.ChannelID != "C1" {
+		t.Errorf("expected channel C1, got %q", stats.ChannelID)
+	}
+
+	expectedTotals := []ReactionTotal{
+		{Name: "tada", Count: 4, Messages: 2},
+		{Name: "wave", Count: 2, Messages: 1},
+		{Name: "rocket", Count: 1, Messages: 1},
+	}
+	if len(stats.Totals) != len(expectedTotals) {
+		t.Fatalf("expected totals %+v, got %+v", expectedTotals, stats.Totals)
+	}
+	for i, total := range expectedTotals {
+		if stats.Totals[i] != total {
+			t.Errorf("expected total %+v, got %+v", total, stats.Totals[i])
+		}
+	}
+
+	if len(stats.TopMessages) != 2 || stats.TopMessages[0].Timestamp != "1700000300.000100" || stats.TopMessages[0].Reactions != 4 || stats.TopMessages[1].Timestamp != "1700000000.000100" {
+		t.Errorf("unexpected top messages %+v", stats.TopMessages)
+	}
+	if len(stats.PinnedMessages) != 1 || stats.PinnedMessages[0] != "1700000100.000100" {
+		t.Errorf("unexpected pinned messages %v", stats.PinnedMessages)
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// ErrThreadNotFound is returned when conversations.replies keeps answering
+// thread_not_found, the thread was deleted or never existed.
+var ErrThreadNotFound = errors.New("thread not found")
+
+const (
+	// threadRetryDefault is how many times a fresh thread answering
+	// thread_not_found is retried, unless SLACK_MCP_THREAD_RETRIES says
+	// otherwise.
+	threadRetryDefault = 2
+	// threadFreshness is how old a thread may be for thread_not_found to be
+	// taken as Slack not having caught up with its creation yet.
+	threadFreshness = time.Minute
+)
+
+// threadRetryBackoff is the wait before the first retry, doubled after each.
+var threadRetryBackoff = 250 * time.Millisecond
+
+// threadRetries returns how many times thread_not_found is retried for fresh
+// threads, read from SLACK_MCP_THREAD_RETRIES.
+func threadRetries() int {
+	if s := os.Getenv("SLACK_MCP_THREAD_RETRIES"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			return n
+		}
+		log.Printf("Invalid SLACK_MCP_THREAD_RETRIES %q, using %d", s, threadRetryDefault)
+	}
+	return threadRetryDefault
+}
+
+// GetThreadReplies wraps conversations.replies. Right after a thread is
+// created Slack may answer thread_not_found for a moment, so threads younger
+// than threadFreshness are retried with a short backoff. Older threads are
+// not, their thread_not_found is permanent. Rate limiting is retried by the
+// transport and independently of this.
+func (ap *ApiProvider) GetThreadReplies(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	retries := 0
+	if isFreshThread(params.Timestamp, time.Now()) {
+		retries = threadRetries()
+	}
+
+	wait := threadRetryBackoff
+	for attempt := 0; ; attempt++ {
+		msgs, hasMore, nextCursor, err := client.GetConversationRepliesContext(ctx, params)
+		if !isThreadNotFound(err) {
+			return msgs, hasMore, nextCursor, err
+		}
+		if attempt >= retries {
+			return nil, false, "", fmt.Errorf("%w: channel %s, ts %s", ErrThreadNotFound, params.ChannelID, params.Timestamp)
+		}
+
+		log.Printf("Thread %s in %s not found yet, retrying in %s (attempt %d/%d)", params.Timestamp, params.ChannelID, wait, attempt+1, retries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, false, "", ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+func isThreadNotFound(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	return errors.As(err, &slackErr) && slackErr.Err == "thread_not_found"
+}
+
+// isFreshThread reports whether the thread started at ts is younger than
+// threadFreshness at now. Unparsable timestamps are not fresh.
+func isFreshThread(ts string, now time.Time) bool {
+	secs, err := strconv.ParseFloat(ts, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(0, int64(secs*float64(time.Second))))
+	return age < threadFreshness
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// repliesTestHandler answers conversations.replies with thread_not_found
+// for the first notFound calls and with a reply afterwards, counting the
+// calls in calls. It also shortens the retry backoff for the test.
+func repliesTestHandler(t *testing.T, notFound int, calls *int) http.HandlerFunc {
+	backoff := threadRetryBackoff
+	threadRetryBackoff = time.Millisecond
+	t.Cleanup(func() { threadRetryBackoff = backoff })
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.replies" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		*calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		if *calls <= notFound {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "thread_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "messages": [{"type": "message", "user": "U1", "text": "hi", "ts": "1700000000.000100"}]}`))
+	}
+}
+
+func threadTS(t time.Time) string {
+	return fmt.Sprintf("%d.000100", t.Unix())
+}
+
+func TestGetThreadReplies_TransientNotFound(t *testing.T) {
+	calls := 0
+	ap, _ := newTestProvider(0, withWebAPI(t, repliesTestHandler(t, 2, &calls)))
+
+	params := &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: threadTS(time.Now())}
+	msgs, _, _, err := ap.GetThreadReplies(context.Background(), params)
+	if err != nil {
+		t.Fatalf("expected the fresh thread to be found on retry, got %v", err)
+	}
+	if len(msgs) != 1 || calls != 3 {
+		t.Errorf("expected 1 message after 3 calls, got %d after %d", len(msgs), calls)
+	}
+}
+
+func TestGetThreadReplies_PermanentNotFound(t *testing.T) {
+	// an old thread is not retried at all
+	calls := 0
+	ap, _ := newTestProvider(0, withWebAPI(t, repliesTestHandler(t, 1, &calls)))
+
+	params := &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: threadTS(time.Now().Add(-time.Hour))}
+	if _, _, _, err := ap.GetThreadReplies(context.Background(), params); !errors.Is(err, ErrThreadNotFound) {
+		t.Fatalf("expected ErrThreadNotFound, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single call for an old thread, got %d", calls)
+	}
+}
+
+func TestGetThreadReplies_RetriesAreCapped(t *testing.T) {
+	t.Setenv("SLACK_MCP_THREAD_RETRIES", "3")
+
+	// a fresh thread which was deleted keeps answering thread_not_found
+	calls := 0
+	ap, _ := newTestProvider(0, withWebAPI(t, repliesTestHandler(t, 100, &calls)))
+
+	params := &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: threadTS(time.Now())}
+	if _, _, _, err := ap.GetThreadReplies(context.Background(), params); !errors.Is(err, ErrThreadNotFound) {
+		t.Fatalf("expected ErrThreadNotFound, got %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("expected 1 call and 3 retries, got %d calls", calls)
+	}
+}
+
+func TestIsFreshThread(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		ts       string
+		expected bool
+	}{
+		{ts: "1699999990.000100", expected: true},
+		{ts: "1699999000.000100", expected: false},
+		{ts: "not-a-ts", expected: false},
+	}
+	for _, tt := range tests {
+		if got := isFreshThread(tt.ts, now); got != tt.expected {
+			t.Errorf("isFreshThread(%q) = %v, expected %v", tt.ts, got, tt.expected)
+		}
+	}
+}
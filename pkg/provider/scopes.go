@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// commonScopes are the OAuth scopes most tools depend on, missing ones are
+// logged at boot so they are not discovered halfway through a run.
+var commonScopes = []string{"channels:history", "users:read", "search:read"}
+
+// scopeRecorder is the HTTP client of a Web API client which keeps the
+// scopes Slack lists in the X-OAuth-Scopes header of its responses to user
+// and bot tokens, so that the auth.test of the boot also detects them.
+type scopeRecorder struct {
+	client *http.Client
+
+	mu     sync.Mutex
+	scopes map[string]bool
+}
+
+// Do implements the HTTP client interface of slack.Client.
+func (r *scopeRecorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		scopes := make(map[string]bool)
+		for _, h := range header {
+			for _, s := range strings.Split(h, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					scopes[s] = true
+				}
+			}
+		}
+		r.mu.Lock()
+		r.scopes = scopes
+		r.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+// Scopes returns the scopes of the last response listing them, nil when
+// none did, e.g. for session tokens.
+func (r *scopeRecorder) Scopes() map[string]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.scopes
+}
+
+// recordScopes records the scopes granted to the token and logs the common
+// ones that are absent. Unknown scopes, nil, are logged only.
+func (ap *ApiProvider) recordScopes(scopes map[string]bool) {
+	if scopes == nil {
+		log.Printf("Failed to detect token scopes: auth.test listed none")
+		return
+	}
+	ap.scopes = scopes
+
+	if missing := ap.missingScopes(); len(missing) > 0 {
+		log.Printf("WARNING: token is missing scopes %s, tools relying on them will fail", strings.Join(missing, ", "))
+	}
+}
+
+// missingScopes returns the common scopes the token lacks. Bot tokens can
+// never be granted search:read, so it is not reported for them.
+func (ap *ApiProvider) missingScopes() []string {
+	var missing []string
+	for _, s := range commonScopes {
+		if s == "search:read" && ap.isBotToken {
+			continue
+		}
+		if !ap.hasScope(s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// HasScope reports whether the token was granted scope. It returns true
+// when the scopes are unknown, e.g. for session tokens or before boot, so
+// callers only skip calls that are certain to fail.
+func (ap *ApiProvider) HasScope(scope string) bool {
+	ap.clientMu.RLock()
+	defer ap.clientMu.RUnlock()
+
+	return ap.hasScope(scope)
+}
+
+// hasScope is HasScope, the caller must hold clientMu, e.g. during the boot.
+func (ap *ApiProvider) hasScope(scope string) bool {
+	if ap.scopes == nil {
+		return true
+	}
+	return ap.scopes[scope]
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestAuthenticate_RecordsScopes(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/auth.test" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Header().Set("X-OAuth-Scopes", "channels:history, users:read,chat:write")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "team_id": "T1", "user_id": "U1"}`))
+	}))
+	defer srv.Close()
+
+	scopes := &scopeRecorder{client: srv.Client()}
+	client := slack.New("xoxp-test", slack.OptionHTTPClient(scopes), slack.OptionAPIURL(srv.URL+"/"))
+
+	ap, _ := newTestProvider(0)
+	if err := ap.authenticate("xoxp-test", client.AuthTest, scopes.Scopes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the scopes to come with the one auth.test, got %d calls", calls)
+	}
+	if len(ap.scopes) != 3 || !ap.scopes["users:read"] || !ap.scopes["chat:write"] {
+		t.Errorf("unexpected scopes %v", ap.scopes)
+	}
+
+	if missing := ap.missingScopes(); !slices.Equal(missing, []string{"search:read"}) {
+		t.Errorf("expected search:read to be missing, got %v", missing)
+	}
+	if ap.HasScope("search:read") {
+		t.Error("expected HasScope to report the missing scope")
+	}
+
+	// bots can never hold search:read
+	ap.isBotToken = true
+	if missing := ap.missingScopes(); len(missing) != 0 {
+		t.Errorf("expected nothing missing for a bot, got %v", missing)
+	}
+}
+
+func TestAuthenticate_NoScopesHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer srv.Close()
+
+	scopes := &scopeRecorder{client: srv.Client()}
+	client := slack.New("xoxc-test", slack.OptionHTTPClient(scopes), slack.OptionAPIURL(srv.URL+"/"))
+
+	ap, _ := newTestProvider(0)
+	if err := ap.authenticate("xoxc-test", client.AuthTest, scopes.Scopes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ap.scopes != nil {
+		t.Fatalf("expected unknown scopes, got %v", ap.scopes)
+	}
+
+	// unknown scopes never block a call
+	if !ap.HasScope("search:read") || len(ap.missingScopes()) != 0 {
+		t.Error("expected every scope to be assumed when unknown")
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// GetChannelsSince returns the channels created or joined after since. The
+// channels are fetched from the API and compared with the channels cache
+// file, which holds them as of the previous fetch, possibly of a previous
+// run: channels created after since are always returned, older ones only
+// when the user, or bot, was not a member of them before. The cache file is
+// then rewritten, so that the next call diffs against this one.
+func (ap *ApiProvider) GetChannelsSince(ctx context.Context, since time.Time) ([]Channel, error) {
+	channelsCache, err := ap.teamCachePath(ap.channelsCache, ap.channelsCacheByTeam)
+	if err != nil {
+		return nil, err
+	}
+	prev := ap.persistedChannels(channelsCache)
+
+	// GetChannels fills the channels cache as a side effect
+	if _, err := ap.GetChannels(ctx, AllChanTypes); err != nil {
+		return nil, err
+	}
+	cur := ap.ProvideChannelsMaps().Channels
+
+	if !ap.offline {
+		writeChannelsCache(channelsCache, slices.Collect(maps.Values(cur)))
+	}
+
+	return channelsSince(prev, cur, since), nil
+}
+
+// persistedChannels returns the channels of the cache file at path, or the
+// in-memory snapshot when the file cannot be read, e.g. in memory mode.
+// Files older than channelsCacheVersion lack the member field, so they
+// count as no snapshot at all rather than as one without memberships.
+func (ap *ApiProvider) persistedChannels(path string) map[string]Channel {
+	data, err := readCacheFile(path)
+	if err != nil {
+		return ap.ProvideChannelsMaps().Channels
+	}
+	cached, version, err := decodeCache[Channel](data)
+	if err != nil {
+		return ap.ProvideChannelsMaps().Channels
+	}
+	if version < channelsCacheVersion {
+		return nil
+	}
+
+	prev := make(map[string]Channel, len(cached))
+	for _, c := range cached {
+		prev[c.ID] = c
+	}
+	return prev
+}
+
+// channelsSince diffs two channel cache snapshots. A channel was joined when
+// it is a member one now but was not before, whether or not it was listed:
+// public channels are listed to non-members too. An empty prev means there
+// is nothing to diff against, so only creation times are considered.
+func channelsSince(prev, cur map[string]Channel, since time.Time) []Channel {
+	var res []Channel
+	for id, c := range cur {
+		if c.Created > since.Unix() {
+			res = append(res, c)
+			continue
+		}
+		if len(prev) > 0 && c.IsMember && !prev[id].IsMember {
+			res = append(res, c)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+func TestChannelsSince(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+
+	prev := map[string]Channel{
+		"C1": {ID: "C1", Name: "#general", Created: 1600000000, IsMember: true},
+		"C2": {ID: "C2", Name: "#random", Created: 1600000000, IsMember: true},
+		// listed before, as public channels are, but not joined
+		"C5": {ID: "C5", Name: "#watercooler", Created: 1500000000},
+	}
+	cur := map[string]Channel{
+		"C1": {ID: "C1", Name: "#general", Created: 1600000000, IsMember: true},
+		"C2": {ID: "C2", Name: "#random", Created: 1600000000, IsMember: true},
+		// created after since
+		"C3": {ID: "C3", Name: "#launch", Created: 1700000100},
+		// created long ago, joined since the previous snapshot
+		"C4": {ID: "C4", Name: "#design", Created: 1500000000, IsMember: true},
+		"C5": {ID: "C5", Name: "#watercooler", Created: 1500000000, IsMember: true},
+		// created long ago, listed but never joined
+		"C6": {ID: "C6", Name: "#announcements", Created: 1500000000},
+		// no creation time, new in this snapshot
+		"D1": {ID: "D1", Name: "@alice", IsIM: true, IsMember: true},
+	}
+
+	got := channelsSince(prev, cur, since)
+
+	var ids []string
+	for _, c := range got {
+		ids = append(ids, c.ID)
+	}
+	if len(ids) != 4 || ids[0] != "C3" || ids[1] != "C4" || ids[2] != "C5" || ids[3] != "D1" {
+		t.Errorf("expected [C3 C4 C5 D1], got %v", ids)
+	}
+}
+
+func TestChannelsSince_NoPreviousSnapshot(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+
+	cur := map[string]Channel{
+		"C1": {ID: "C1", Name: "#general", Created: 1600000000, IsMember: true},
+		"C3": {ID: "C3", Name: "#launch", Created: 1700000100},
+		"D1": {ID: "D1", Name: "@alice", IsIM: true, IsMember: true},
+	}
+
+	// without a previous snapshot only creation times can be trusted
+	got := channelsSince(nil, cur, since)
+	if len(got) != 1 || got[0].ID != "C3" {
+		t.Errorf("expected only C3, got %v", got)
+	}
+}
+
+func TestChannelsSince_Unchanged(t *testing.T) {
+	snapshot := map[string]Channel{
+		"C1": {ID: "C1", Name: "#general", Created: 1600000000, IsMember: true},
+	}
+
+	if got := channelsSince(snapshot, snapshot, time.Unix(1700000000, 0)); len(got) != 0 {
+		t.Errorf("expected no channels, got %v", got)
+	}
+}
+
+func TestGetChannelsSince_DiffsAgainstPreviousRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"channels": [
+				{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true, "is_member": true, "created": 1600000000},
+				{"id": "C2", "name": "design", "name_normalized": "design", "is_channel": true, "is_member": true, "created": 1600000000}
+			],
+			"response_metadata": {"next_cursor": ""}
+		}`))
+	}))
+	defer srv.Close()
+
+	// written by a previous run, before C2 was joined
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+	err := os.WriteFile(cache, []byte(`{"schema_version": 1, "entries": [
+		{"id": "C1", "name": "#general", "member": true, "created": 1600000000},
+		{"id": "C2", "name": "#design", "created": 1600000000}
+	]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = cache
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	got, err := ap.GetChannelsSince(context.Background(), time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "C2" {
+		t.Errorf("expected the joined C2, got %+v", got)
+	}
+
+	// the next call diffs against this one
+	got, err = ap.GetChannelsSince(context.Background(), time.Unix(1700000000, 0))
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected no channels on the next call, got %+v, %v", got, err)
+	}
+}
+
+func TestGetChannelsSince_OutdatedCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"channels": [
+				{"id": "C1", "name": "general", "name_normalized": "general", "is_channel": true, "is_member": true, "created": 1600000000},
+				{"id": "C2", "name": "launch", "name_normalized": "launch", "is_channel": true, "created": 1700000100}
+			],
+			"response_metadata": {"next_cursor": ""}
+		}`))
+	}))
+	defer srv.Close()
+
+	// version 0 has no member field, C1 would look joined since
+	cache := filepath.Join(t.TempDir(), "channels_cache.json")
+	err := os.WriteFile(cache, []byte(`[{"id": "C1", "name": "#general", "created": 1600000000}]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ap, _ := newTestProvider(0)
+	ap.channelsCache = cache
+	ap.authResponse = &slack2.AuthTestResponse{}
+	ap.clientGeneric = slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/"))
+	ap.clientEnterprise = &edge.Client{}
+
+	got, err := ap.GetChannelsSince(context.Background(), time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "C2" {
+		t.Errorf("expected only the new C2, got %+v", got)
+	}
+}
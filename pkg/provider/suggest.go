@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxChannelSuggestions bounds how many names a ChannelNotFoundError offers.
+const maxChannelSuggestions = 3
+
+// ChannelNotFoundError is returned when a channel name is missing from the
+// channels cache. Suggestions holds the closest cached names, best first.
+type ChannelNotFoundError struct {
+	Ref         string
+	Suggestions []string
+}
+
+func (e *ChannelNotFoundError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("channel %q not found", e.Ref)
+	}
+	return fmt.Sprintf("channel %q not found, did you mean %s?", e.Ref, strings.Join(e.Suggestions, " or "))
+}
+
+// suggestChannels returns up to maxChannelSuggestions cached channel names
+// close to ref: names starting with it come first, then the ones within a
+// small edit distance, scaled with the length of ref.
+func suggestChannels(channels map[string]Channel, ref string) []string {
+	bare := strings.ToLower(bareChannelName(ref))
+	if bare == "" {
+		return nil
+	}
+	maxDist := max(2, len(bare)/3)
+
+	type candidate struct {
+		name   string
+		prefix bool
+		dist   int
+	}
+
+	var candidates []candidate
+	for _, c := range channels {
+		name := strings.ToLower(bareChannelName(c.Name))
+		if name == "" {
+			continue
+		}
+
+		cand := candidate{name: c.Name, prefix: strings.HasPrefix(name, bare), dist: levenshtein(bare, name)}
+		if cand.prefix || cand.dist <= maxDist {
+			candidates = append(candidates, cand)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.prefix != b.prefix {
+			return a.prefix
+		}
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+		return a.name < b.name
+	})
+
+	var res []string
+	for _, c := range candidates[:min(len(candidates), maxChannelSuggestions)] {
+		res = append(res, c.name)
+	}
+	return res
+}
+
+// levenshtein returns the edit distance between a and b, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResolveChannelID_SuggestsOnTypo(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	ap.channels = map[string]Channel{
+		"C1": {ID: "C1", Name: "#general"},
+		"C2": {ID: "C2", Name: "#general-announcements"},
+		"C3": {ID: "C3", Name: "#random"},
+		"C4": {ID: "C4", Name: "#engineering"},
+	}
+	ap.channelsInv = map[string]string{"#general": "C1", "#general-announcements": "C2", "#random": "C3", "#engineering": "C4"}
+
+	tests := []struct {
+		ref      string
+		expected []string
+	}{
+		{ref: "#genral", expected: []string{"#general"}},
+		{ref: "#gen", expected: []string{"#general", "#general-announcements"}},
+		{ref: "#randon", expected: []string{"#random"}},
+		{ref: "#marketing", expected: nil},
+	}
+	for _, tt := range tests {
+		_, err := ap.ResolveChannelID(tt.ref, false)
+
+		var notFound *ChannelNotFoundError
+		if !errors.As(err, &notFound) {
+			t.Fatalf("ResolveChannelID(%q): expected a ChannelNotFoundError, got %v", tt.ref, err)
+		}
+		if !slices.Equal(notFound.Suggestions, tt.expected) {
+			t.Errorf("ResolveChannelID(%q) suggested %v, expected %v", tt.ref, notFound.Suggestions, tt.expected)
+		}
+	}
+
+	if _, err := ap.ResolveChannelID("#genral", false); err == nil || err.Error() != `channel "#genral" not found, did you mean #general?` {
+		t.Errorf("unexpected error message %v", err)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "general", b: "general", expected: 0},
+		{a: "genral", b: "general", expected: 1},
+		{a: "random", b: "randon", expected: 1},
+		{a: "", b: "abc", expected: 3},
+		{a: "日本", b: "日本語", expected: 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	// Embedded so timezones resolve in images without zoneinfo, e.g. alpine
+	_ "time/tzdata"
+
+	"github.com/slack-go/slack"
+)
+
+var ErrTimezoneUnknown = errors.New("timezone unknown")
+
+// GetUserTimezone returns the location of the user referenced by userRef, an
+// ID or a username with or without "@". Timezones the tz database does not
+// know fall back to a fixed zone at the user's UTC offset; users without any
+// timezone set, e.g. most bots, yield ErrTimezoneUnknown.
+func (ap *ApiProvider) GetUserTimezone(ctx context.Context, userRef string) (*time.Location, error) {
+	userID, err := ap.resolveUserRef(userRef)
+	if err != nil {
+		return nil, err
+	}
+
+	user, ok := ap.ResolveUser(userID)
+	if !ok {
+		return nil, fmt.Errorf("user %q not found", userRef)
+	}
+
+	return UserLocation(user)
+}
+
+// locations caches the zones loaded from the tz database by name, which
+// user listings would otherwise load once per user.
+var locations sync.Map
+
+// UserLocation returns the location of user, see GetUserTimezone.
+func UserLocation(user slack.User) (*time.Location, error) {
+	if user.TZ != "" {
+		if loc, ok := locations.Load(user.TZ); ok {
+			return loc.(*time.Location), nil
+		}
+		if loc, err := time.LoadLocation(user.TZ); err == nil {
+			locations.Store(user.TZ, loc)
+			return loc, nil
+		}
+	}
+
+	if user.TZ == "" && user.TZOffset == 0 {
+		return nil, fmt.Errorf("%w for user %s", ErrTimezoneUnknown, user.ID)
+	}
+
+	name := user.TZLabel
+	if name == "" {
+		name = user.TZ
+	}
+	return time.FixedZone(name, user.TZOffset), nil
+}
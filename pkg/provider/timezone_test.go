@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func TestGetUserTimezone(t *testing.T) {
+	ap, _ := newTestProvider(0)
+	for _, u := range []slack.User{
+		{ID: "U1", Name: "alice", TZ: "Asia/Tokyo", TZLabel: "Japan Standard Time", TZOffset: 9 * 3600},
+		{ID: "U2", Name: "bob", TZ: "America/New_York", TZLabel: "Eastern Daylight Time", TZOffset: -4 * 3600},
+		{ID: "U3", Name: "carol", TZ: "Mars/Olympus_Mons", TZLabel: "Olympus Mons Time", TZOffset: 5*3600 + 1800},
+		{ID: "B1", Name: "deploybot", IsBot: true},
+	} {
+		ap.users[u.ID] = u
+		ap.usersInv[u.Name] = u.ID
+	}
+
+	// a fixed instant in northern summer, New York observes daylight time
+	at := time.Date(2024, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		ref    string
+		name   string
+		offset int
+	}{
+		{ref: "U1", name: "Asia/Tokyo", offset: 9 * 3600},
+		{ref: "@bob", name: "America/New_York", offset: -4 * 3600},
+		{ref: "carol", name: "Olympus Mons Time", offset: 5*3600 + 1800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			loc, err := ap.GetUserTimezone(context.Background(), tt.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if loc.String() != tt.name {
+				t.Errorf("expected location %q, got %q", tt.name, loc.String())
+			}
+			if _, offset := at.In(loc).Zone(); offset != tt.offset {
+				t.Errorf("expected offset %d, got %d", tt.offset, offset)
+			}
+		})
+	}
+
+	if _, err := ap.GetUserTimezone(context.Background(), "B1"); !errors.Is(err, ErrTimezoneUnknown) {
+		t.Errorf("expected ErrTimezoneUnknown for a user without timezone, got %v", err)
+	}
+	if _, err := ap.GetUserTimezone(context.Background(), "@nobody"); err == nil {
+		t.Error("expected an error for an unknown user")
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeClientCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "slack-mcp-server"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestClientCertificates(t *testing.T) {
+	certFile, keyFile := writeClientCert(t)
+
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CLIENT_CERT", "")
+		t.Setenv("SLACK_MCP_CLIENT_KEY", "")
+
+		certs, err := clientCertificates()
+		if err != nil || certs != nil {
+			t.Errorf("expected no certificates, got %v, %v", certs, err)
+		}
+	})
+
+	t.Run("both set", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CLIENT_CERT", certFile)
+		t.Setenv("SLACK_MCP_CLIENT_KEY", keyFile)
+
+		certs, err := clientCertificates()
+		if err != nil || len(certs) != 1 {
+			t.Fatalf("expected one certificate, got %v, %v", certs, err)
+		}
+	})
+
+	t.Run("only one set", func(t *testing.T) {
+		t.Setenv("SLACK_MCP_CLIENT_CERT", certFile)
+		t.Setenv("SLACK_MCP_CLIENT_KEY", "")
+
+		if _, err := clientCertificates(); err == nil {
+			t.Error("expected an error when the key is missing")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/slack-go/slack"
+)
+
+// ErrInvalidAuth is returned when Slack stopped accepting the credentials,
+// e.g. once the browser session a xoxc token was copied from ended.
+var ErrInvalidAuth = errors.New("credentials rejected by Slack")
+
+// invalidAuthCodes are the Slack error codes meaning the credentials are no
+// longer valid, as opposed to lacking a scope.
+var invalidAuthCodes = map[string]bool{
+	"invalid_auth":     true,
+	"not_authed":       true,
+	"token_revoked":    true,
+	"token_expired":    true,
+	"account_inactive": true,
+}
+
+// TokenRefresher reloads credentials Slack stopped accepting, so callers
+// can retry on IsInvalidAuth errors instead of failing until a restart.
+type TokenRefresher interface {
+	RefreshToken(ctx context.Context) error
+}
+
+var _ TokenRefresher = (*ApiProvider)(nil)
+
+// IsInvalidAuth reports whether err, from the Web API or the edge API,
+// means the credentials are no longer valid.
+func IsInvalidAuth(err error) bool {
+	if errors.Is(err, ErrInvalidAuth) {
+		return true
+	}
+
+	var apiErr *edge.APIError
+	var slackErr slack.SlackErrorResponse
+	switch {
+	case errors.As(err, &apiErr):
+		return invalidAuthCodes[apiErr.Err]
+	case errors.As(err, &slackErr):
+		return invalidAuthCodes[slackErr.Err]
+	}
+	return false
+}
+
+// RefreshToken re-reads the credentials from SLACK_MCP_TOKEN_FILE and
+// rebuilds the Slack clients with them, the caches are kept. The file holds
+// the same variables as the environment, one NAME=value per line, e.g.
+// SLACK_MCP_XOXC_TOKEN and SLACK_MCP_XOXD_TOKEN. Without the file an
+// ErrInvalidAuth error explaining how to recover is returned.
+func (ap *ApiProvider) RefreshToken(ctx context.Context) error {
+	path := os.Getenv("SLACK_MCP_TOKEN_FILE")
+	if path == "" {
+		return fmt.Errorf("%w: restart the server with fresh credentials, or set SLACK_MCP_TOKEN_FILE to have them re-read", ErrInvalidAuth)
+	}
+
+	ap.tokenMu.Lock()
+	defer ap.tokenMu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read SLACK_MCP_TOKEN_FILE: %v", ErrInvalidAuth, err)
+	}
+	vars := parseTokenFile(data)
+
+	creds, err := credentialsFrom(func(name string) string { return vars[name] })
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidAuth, path, err)
+	}
+
+	// Callers holding the old clients finish with them, the next ones boot
+	// with the new credentials
+	ap.clientMu.Lock()
+	ap.boot, ap.token, ap.isBotToken = creds.boot, creds.token, creds.isBotToken
+	ap.clientGeneric, ap.clientEnterprise = nil, nil
+	ap.bootErr = nil
+	ap.clientMu.Unlock()
+
+	if _, err := ap.ProvideGeneric(); err != nil {
+		return fmt.Errorf("%w: the credentials in %s were rejected too: %v", ErrInvalidAuth, path, err)
+	}
+
+	log.Printf("Reloaded the credentials from %s", path)
+	return nil
+}
+
+// parseTokenFile parses NAME=value lines, blank lines and # comments are
+// skipped and values may be quoted.
+func parseTokenFile(data []byte) map[string]string {
+	vars := make(map[string]string)
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[strings.TrimSpace(name)] = value
+	}
+
+	return vars
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/slack-go/slack"
+)
+
+func TestIsInvalidAuth(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{fmt.Errorf("auth.test failed: %w", slack.SlackErrorResponse{Err: "invalid_auth"}), true},
+		{slack.SlackErrorResponse{Err: "token_expired"}, true},
+		{&edge.APIError{Err: "invalid_auth"}, true},
+		{fmt.Errorf("%w: no file", ErrInvalidAuth), true},
+		{slack.SlackErrorResponse{Err: "missing_scope"}, false},
+		{errors.New("invalid_auth"), false},
+	}
+	for _, tt := range tests {
+		if got := IsInvalidAuth(tt.err); got != tt.expected {
+			t.Errorf("IsInvalidAuth(%v) = %v, expected %v", tt.err, got, tt.expected)
+		}
+	}
+}
+
+func TestParseTokenFile(t *testing.T) {
+	data := []byte(`# copied from the browser
+SLACK_MCP_XOXC_TOKEN=xoxc-1-2
+export SLACK_MCP_XOXD_TOKEN="xoxd-a%2Fb"
+
+not a variable
+`)
+	expected := map[string]string{
+		"SLACK_MCP_XOXC_TOKEN": "xoxc-1-2",
+		"SLACK_MCP_XOXD_TOKEN": "xoxd-a%2Fb",
+	}
+	if got := parseTokenFile(data); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRefreshToken_WithoutFile(t *testing.T) {
+	t.Setenv("SLACK_MCP_TOKEN_FILE", "")
+
+	ap, _ := newTestProvider(0)
+	if err := ap.RefreshToken(context.Background()); !errors.Is(err, ErrInvalidAuth) {
+		t.Errorf("expected ErrInvalidAuth, got %v", err)
+	}
+}
+
+func TestRefreshToken_NoCredentialsInFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.env")
+	if err := os.WriteFile(path, []byte("SLACK_MCP_XOXC_TOKEN=xoxc-1-2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SLACK_MCP_TOKEN_FILE", path)
+
+	ap, _ := newTestProvider(0)
+	client := slack.New("xoxp-old")
+	ap.clientGeneric = client
+
+	err := ap.RefreshToken(context.Background())
+	if !errors.Is(err, ErrInvalidAuth) {
+		t.Errorf("expected ErrInvalidAuth, got %v", err)
+	}
+	if ap.clientGeneric != client {
+		t.Error("expected the client to be kept when the file holds no usable credentials")
+	}
+}
+
+func TestRefreshToken_SwapsCredentialsUnderLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.env")
+	if err := os.WriteFile(path, []byte("SLACK_MCP_XOXB_TOKEN=xoxb-new\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SLACK_MCP_TOKEN_FILE", path)
+	t.Setenv("SLACK_MCP_CACHE_DIR", t.TempDir())
+
+	rejected := errors.New("invalid_auth")
+	ap, _ := newTestProvider(0)
+	ap.boot = func(ap *ApiProvider) (*slack.Client, error) { return nil, rejected }
+
+	// readers racing the refresh, checked by the race detector
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			_ = ap.IsBotToken()
+			_ = ap.HasScope("files:read")
+			_, _ = ap.ProvideGeneric()
+		}
+	}()
+
+	// the new token is not accepted here, which does not matter for the swap
+	_ = ap.RefreshToken(context.Background())
+	<-done
+
+	if !ap.IsBotToken() {
+		t.Error("expected the bot token from the file to be in use")
+	}
+}
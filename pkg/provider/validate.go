@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	slack2 "github.com/rusq/slack"
+	"github.com/slack-go/slack"
+)
+
+// Validate checks the configured credentials without caching anything: it
+// runs auth.test, lists a channel and probes search.messages. The token
+// type, the workspace and whether search is available are logged, a failure
+// is returned as an error explaining what to fix.
+func (ap *ApiProvider) Validate(ctx context.Context) error {
+	if ap.offline {
+		return fmt.Errorf("%w: there are no credentials to validate", ErrOffline)
+	}
+
+	info, err := ap.AuthInfo()
+	if err != nil {
+		return credentialsError(err)
+	}
+	log.Printf("Credentials OK: %s token of %s on %s (%s)", info.TokenType, info.User, info.Team, info.URL)
+
+	if err := ap.validateChannels(ctx); err != nil {
+		return err
+	}
+
+	if available, reason := ap.searchAvailable(ctx); available {
+		log.Printf("search.messages is available")
+	} else {
+		log.Printf("search.messages is not available: %s", reason)
+	}
+
+	return nil
+}
+
+// validateChannelPages bounds how many conversations.list pages Validate
+// reads looking for a channel: with archived channels excluded a page may
+// come back empty while later ones are not.
+const validateChannelPages = 5
+
+// validateChannels fails unless at least one channel can be listed, through
+// the client GetChannels uses.
+func (ap *ApiProvider) validateChannels(ctx context.Context) error {
+	var (
+		ids []string
+		err error
+	)
+	if ap.authTest().EnterpriseID == "" {
+		client, cerr := ap.ProvideGeneric()
+		if cerr != nil {
+			return fmt.Errorf("failed to create the client: %w", cerr)
+		}
+		params := &slack.GetConversationsParameters{
+			Types:           []string{"public_channel", "private_channel", "im", "mpim"},
+			Limit:           100,
+			ExcludeArchived: true,
+		}
+		for page := 0; page < validateChannelPages && len(ids) == 0 && err == nil; page++ {
+			var chans []slack.Channel
+			chans, params.Cursor, err = client.GetConversationsContext(ctx, params)
+			for _, c := range chans {
+				ids = append(ids, c.ID)
+			}
+			if params.Cursor == "" {
+				break
+			}
+		}
+	} else {
+		client, cerr := ap.ProvideEnterprise()
+		if cerr != nil {
+			return fmt.Errorf("failed to create the enterprise client: %w", cerr)
+		}
+		var chans []slack2.Channel
+		chans, _, err = client.GetConversationsContext(ctx, nil)
+		for _, c := range chans {
+			ids = append(ids, c.ID)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list channels: %w", scopeError("conversations.list", err))
+	}
+	if len(ids) == 0 {
+		return errors.New("failed to list channels: none are visible to the token")
+	}
+
+	log.Printf("Channels OK: listed %s", ids[0])
+	return nil
+}
+
+// searchAvailable probes search.messages and reports why it is unavailable
+// if so. Bot tokens are never allowed to search.
+func (ap *ApiProvider) searchAvailable(ctx context.Context) (bool, string) {
+	if ap.IsBotToken() {
+		return false, "bot tokens cannot use search.messages"
+	}
+	if !ap.HasScope("search:read") {
+		return false, "the token lacks the search:read scope"
+	}
+
+	client, err := ap.ProvideGeneric()
+	if err != nil {
+		return false, err.Error()
+	}
+	if _, err := client.SearchMessagesContext(ctx, "validate", slack.SearchParameters{Count: 1, Page: 1}); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// credentialsError explains a failed auth.test in terms of what to check.
+func credentialsError(err error) error {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return fmt.Errorf("failed to reach Slack: %w", err)
+	}
+
+	if invalidAuthCodes[slackErr.Err] {
+		return fmt.Errorf("%w (%s): check the token, for SLACK_MCP_XOXC_TOKEN also SLACK_MCP_XOXD_TOKEN, session tokens expire once you log out of the browser they were copied from", ErrInvalidAuth, slackErr.Err)
+	}
+	return fmt.Errorf("credentials rejected by Slack: %w", err)
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestValidate(t *testing.T) {
+	var searched bool
+	ap, _ := newTestProvider(0, withBootAPI(t, "xoxp-test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok": true, "user": "alice", "team": "Acme", "url": "https://acme.slack.com/"}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general"}]}`))
+		case "/search.messages":
+			searched = true
+			_, _ = w.Write([]byte(`{"ok": true, "messages": {"matches": []}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})))
+	logged := captureLog(t)
+
+	if err := ap.Validate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !searched {
+		t.Error("expected search.messages to be probed")
+	}
+	for _, want := range []string{"xoxp token of alice on Acme", "listed C1", "search.messages is available"} {
+		if !strings.Contains(logged.String(), want) {
+			t.Errorf("expected the log to contain %q, got:\n%s", want, logged)
+		}
+	}
+}
+
+func TestValidate_BotCannotSearch(t *testing.T) {
+	ap, _ := newTestProvider(0, withBootAPI(t, "xoxb-test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok": true, "user": "bot", "team": "Acme", "bot_id": "B1"}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C1", "name": "general"}]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})))
+	logged := captureLog(t)
+
+	if err := ap.Validate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(logged.String(), "search.messages is not available: bot tokens") {
+		t.Errorf("expected search to be reported unavailable, got:\n%s", logged)
+	}
+}
+
+func TestValidate_InvalidAuth(t *testing.T) {
+	ap, _ := newTestProvider(0, withBootAPI(t, "xoxc-expired", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+	})))
+
+	err := ap.Validate(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "credentials rejected by Slack (invalid_auth)") || !strings.Contains(err.Error(), "SLACK_MCP_XOXD_TOKEN") {
+		t.Errorf("expected an actionable invalid_auth error, got %v", err)
+	}
+}
+
+func TestValidate_NoChannels(t *testing.T) {
+	ap, _ := newTestProvider(0, withBootAPI(t, "xoxp-test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok": true, "user": "alice", "team": "Acme"}`))
+		case "/conversations.list":
+			_, _ = w.Write([]byte(`{"ok": true, "channels": []}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})))
+
+	if err := ap.Validate(context.Background()); err == nil || !strings.Contains(err.Error(), "none are visible") {
+		t.Errorf("expected a channel listing error, got %v", err)
+	}
+}
+
+func TestValidate_FollowsEmptyFirstPage(t *testing.T) {
+	var pages int
+	ap, _ := newTestProvider(0, withBootAPI(t, "xoxp-test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok": true, "user": "alice", "team": "Acme"}`))
+		case "/conversations.list":
+			pages++
+			_ = r.ParseForm()
+			if r.Form.Get("cursor") == "" {
+				// every channel of the first page was archived
+				_, _ = w.Write([]byte(`{"ok": true, "channels": [], "response_metadata": {"next_cursor": "page2"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok": true, "channels": [{"id": "C2", "name": "general"}]}`))
+		case "/search.messages":
+			_, _ = w.Write([]byte(`{"ok": true, "messages": {"matches": []}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})))
+	logged := captureLog(t)
+
+	if err := ap.Validate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 2 || !strings.Contains(logged.String(), "listed C2") {
+		t.Errorf("expected the second page to be listed, got %d pages:\n%s", pages, logged)
+	}
+}
@@ -20,6 +20,7 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 		server.WithLogging(),
 		server.WithRecovery(),
 		server.WithToolHandlerMiddleware(buildMiddleware(transport)),
+		server.WithToolHandlerMiddleware(refreshOnInvalidAuth(provider)),
 	)
 
 	conversationsHandler := handler.NewConversationsHandler(provider)
@@ -33,10 +34,23 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Required(),
 			mcp.Description("    - `channel_id` (string): ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
 		),
+		caseSensitiveOption(),
 		mcp.WithBoolean("include_activity_messages",
 			mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("include_permalinks",
+			mcp.Description("If true, each message includes its permalink so it can be cited. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_broadcast_reach",
+			mcp.Description("If true, @channel, @here and @everyone mentions are annotated with the channel's cached member count, e.g. '@channel (notified ~120)'. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("collapse_quoted_replies",
+			mcp.Description("If true, quoted lines at the start of a message which repeat earlier messages of the same response are dropped, keeping only what the reply adds. Shrinks email-style threads where every reply re-quotes the chain. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
 		mcp.WithString("cursor",
 			mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
 		),
@@ -54,6 +68,7 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Required(),
 			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
 		),
+		caseSensitiveOption(),
 		mcp.WithString("thread_ts",
 			mcp.Required(),
 			mcp.Description("Unique identifier of either a thread’s parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies."),
@@ -62,6 +77,18 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("include_permalinks",
+			mcp.Description("If true, each message includes its permalink so it can be cited. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_broadcast_reach",
+			mcp.Description("If true, @channel, @here and @everyone mentions are annotated with the channel's cached member count, e.g. '@channel (notified ~120)'. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("collapse_quoted_replies",
+			mcp.Description("If true, quoted lines at the start of a message which repeat earlier messages of the same response are dropped, keeping only what the reply adds. Shrinks email-style threads where every reply re-quotes the chain. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
 		mcp.WithString("cursor",
 			mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
 		),
@@ -79,6 +106,7 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Required(),
 			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
 		),
+		caseSensitiveOption(),
 		mcp.WithString("thread_ts",
 			mcp.Description("Unique identifier of either a thread’s parent message or a message in the thread_ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies. Optional, if not provided the message will be added to the channel itself, otherwise it will be added to the thread."),
 		),
@@ -98,11 +126,12 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.WithTitleAnnotation("Search Messages"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithString("search_query",
-				mcp.Description("Search query to filter messages. Example: 'marketing report'."),
+				mcp.Description("Search query to filter messages. Example: 'marketing report'. The from:@name modifier is resolved to the user's ID, by username, display name or real name, and in:#name to the channel's canonical name."),
 			),
 			mcp.WithString("filter_in_channel",
 				mcp.Description("Filter messages in a specific public/private channel by its ID or name. Example: 'C1234567890', 'G1234567890', or '#general'. If not provided, all channels will be searched."),
 			),
+			caseSensitiveOption(),
 			mcp.WithString("filter_in_im_or_mpim",
 				mcp.Description("Filter messages in a direct message (DM) or multi-person direct message (MPIM) conversation by its ID or name. Example: 'D1234567890' or '@username_dm'. If not provided, all DMs and MPIMs will be searched."),
 			),
@@ -149,7 +178,24 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Description("Comma-separated channel types. Allowed values: 'mpim', 'im', 'public_channel', 'private_channel'. Example: 'public_channel,private_channel,im'"),
 		),
 		mcp.WithString("sort",
-			mcp.Description("Type of sorting. Allowed values: 'popularity' - sort by number of members/participants in each channel."),
+			mcp.Description("Type of sorting. Allowed values: 'popularity' - sort each page by number of members/participants, 'name', 'member_count' or 'created' - sort all listed channels, so that pages are stable."),
+		),
+		mcp.WithString("sort_direction",
+			mcp.Description("Direction of the 'name', 'member_count' and 'created' sorts. Allowed values: 'asc' (default), 'desc'."),
+		),
+		mcp.WithBoolean("include_archived",
+			mcp.Description("If true, archived channels are listed as well. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("member_only",
+			mcp.Description("If true, only channels the user, or bot, is a member of are listed, e.g. the channels a bot was invited to and can read. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("min_members",
+			mcp.Description("Only list channels with at least this many members. Example: 50 to find large channels."),
+		),
+		mcp.WithNumber("max_members",
+			mcp.Description("Only list channels with at most this many members. Example: 2 to find tiny channels to archive."),
 		),
 		mcp.WithNumber("limit",
 			mcp.DefaultNumber(100),
@@ -160,6 +206,76 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 		),
 	), channelsHandler.ChannelsHandler)
 
+	s.AddTool(mcp.NewTool("channels_search",
+		mcp.WithDescription("Search the cached channels by a substring of their name, topic or purpose, case-insensitively, e.g. to find a channel remembered by what it is for"),
+		mcp.WithTitleAnnotation("Search Channels"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Text to look for. Example: 'incident' or 'design reviews'."),
+		),
+		mcp.WithString("fields",
+			mcp.DefaultString("all"),
+			mcp.Description("Comma-separated fields to search. Allowed values: 'name', 'topic', 'purpose' or 'all' (default)."),
+		),
+		mcp.WithBoolean("include_archived",
+			mcp.Description("If true, archived channels are searched as well. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(100),
+			mcp.Description("The maximum number of channels to return, ordered by name."),
+		),
+		formatOption(),
+	), channelsHandler.ChannelsSearchHandler)
+
+	s.AddTool(mcp.NewTool("channels_recently_active",
+		mcp.WithDescription("List channels by their latest message, most recent first, e.g. to decide which channels to read. Each channel costs a conversations.history request, the results are reused for 5 minutes"),
+		mcp.WithTitleAnnotation("List Recently Active Channels"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_types",
+			mcp.DefaultString("public_channel,private_channel"),
+			mcp.Description("Comma-separated channel types. Allowed values: 'mpim', 'im', 'public_channel', 'private_channel'. Default is 'public_channel,private_channel'."),
+		),
+		mcp.WithNumber("max_probed",
+			mcp.DefaultNumber(50),
+			mcp.Description("The maximum number of channels whose latest message is looked up, the ones with the most members first. At most 200. Channels looked up in the last 5 minutes do not count."),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(20),
+			mcp.Description("The maximum number of channels to return."),
+		),
+		formatOption(),
+	), channelsHandler.ChannelsRecentlyActiveHandler)
+
+	s.AddTool(mcp.NewTool("conversations_info",
+		mcp.WithDescription("Get a single channel by ID or name, including its topic, purpose, member count and whether it is archived"),
+		mcp.WithTitleAnnotation("Get Channel Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		caseSensitiveOption(),
+		formatOption(),
+	), channelsHandler.ChannelsInfoHandler)
+
+	s.AddTool(mcp.NewTool("conversations_members",
+		mcp.WithDescription("List the members of a channel with their user ID, username and real name"),
+		mcp.WithTitleAnnotation("List Channel Members"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		caseSensitiveOption(),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(0),
+			mcp.Description("Maximum number of members to return. Default is 0, which returns all of them; big channels are paged through at the configured rate limit tier."),
+		),
+		formatOption(),
+	), channelsHandler.ChannelsMembersHandler)
+
 	s.AddTool(mcp.NewTool("conversations_create",
 		mcp.WithDescription("Create a new public channel"),
 		mcp.WithString("name",
@@ -174,6 +290,7 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Required(),
 			mcp.Description("ID of the channel to rename in format Cxxxxxxxxxx or its name starting with #... aka #general"),
 		),
+		caseSensitiveOption(),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("New name for the channel. Must be 80 characters or less."),
@@ -186,6 +303,7 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Required(),
 			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general"),
 		),
+		caseSensitiveOption(),
 		mcp.WithString("users",
 			mcp.Required(),
 			mcp.Description("Comma-separated list of user IDs (U1234567890) or usernames (@username) to invite"),
@@ -198,29 +316,214 @@ func NewMCPServer(provider *provider.ApiProvider, transport string) *MCPServer {
 			mcp.Required(),
 			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general"),
 		),
+		caseSensitiveOption(),
 		mcp.WithString("topic",
 			mcp.Required(),
 			mcp.Description("New topic/description for the channel"),
 		),
 	), conversationsHandler.ConversationsSetTopicHandler)
 
+	s.AddTool(mcp.NewTool("conversations_mark_read",
+		mcp.WithDescription("Mark a channel or DM as read up to a message. Requires a user or session token, bot tokens cannot mark channels as read."),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm"),
+		),
+		caseSensitiveOption(),
+		mcp.WithString("ts",
+			mcp.Required(),
+			mcp.Description("Timestamp of the last read message in format 1234567890.123456"),
+		),
+	), conversationsHandler.ConversationsMarkReadHandler)
+
+	s.AddTool(mcp.NewTool("permalink_resolve",
+		mcp.WithDescription("Split a Slack message permalink into its channel ID, channel name and message timestamp, e.g. to fetch the linked message or its thread with conversations_replies. No API call is made."),
+		mcp.WithTitleAnnotation("Resolve Permalink"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("permalink",
+			mcp.Required(),
+			mcp.Description("Permalink of a message or channel. Example: https://team.slack.com/archives/C1234567890/p1700000000123456, thread replies may carry ?thread_ts=1700000000.000100."),
+		),
+		formatOption(),
+	), conversationsHandler.PermalinkResolveHandler)
+
+	s.AddTool(mcp.NewTool("conversations_join",
+		mcp.WithDescription("Join a public channel, so that its history can be read and messages posted to it"),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general"),
+		),
+		caseSensitiveOption(),
+	), conversationsHandler.ConversationsJoinHandler)
+
+	s.AddTool(mcp.NewTool("conversations_leave",
+		mcp.WithDescription("Leave a channel. Only allowed for the channels SLACK_MCP_ADD_MESSAGE_TOOL allows posting to."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general"),
+		),
+		caseSensitiveOption(),
+	), conversationsHandler.ConversationsLeaveHandler)
+
 	s.AddTool(mcp.NewTool("users_resolve",
 		mcp.WithDescription("Resolve a user by their username, display name, real name, or email. Returns matching user information including user ID, username, display name, and real name."),
 		mcp.WithString("query",
 			mcp.Required(),
-			mcp.Description("The search query (username, display name, real name, or email). Can start with @ but it's not required."),
+			mcp.Description("The search query (username, display name, real name, email, or user ID such as U12345678). Can start with @ but it's not required."),
 		),
 		mcp.WithString("search_type",
 			mcp.DefaultString("auto"),
 			mcp.Description("Type of search to perform. Options: 'username', 'display_name', 'real_name', 'email', 'auto' (default). 'auto' searches all fields."),
 		),
+		mcp.WithBoolean("exclude_deleted",
+			mcp.Description("If true, deactivated users are left out of the results. Default is boolean false, in which case they are listed after active users."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("case_sensitive",
+			mcp.Description("If true, exact matches require the same letter case as the query. Default is boolean false, partial matches always ignore case."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("csv_quoting",
+			mcp.DefaultString("minimal"),
+			mcp.Description("How CSV fields are quoted. Options: 'minimal' (default) quotes only when needed, 'all' quotes every field, or a comma-separated list of columns to always quote, matched ignoring case, e.g. 'email,realName'."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of matches returned. Default is 0, returning all of them. When limit or offset is set, a second text block reports the total match count, whether more results exist and the next offset."),
+			mcp.DefaultNumber(0),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of matches to skip, for paging through large result sets. Default is 0."),
+			mcp.DefaultNumber(0),
+		),
+		formatOption(),
 	), usersHandler.UsersResolveHandler)
 
+	s.AddTool(mcp.NewTool("users_bulk_resolve",
+		mcp.WithDescription("Resolve many user IDs at once, e.g. all <@U...> mentions found in a conversation. Returns a row per ID with username, real name and display name; IDs unknown to the users cache are returned with status 'not_found'."),
+		mcp.WithTitleAnnotation("Bulk Resolve Users"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("user_ids",
+			mcp.Required(),
+			mcp.Description("Comma-separated list or JSON array of user IDs. Example: 'U1234567890,U0987654321' or '[\"U1234567890\"]'. Mentions such as <@U1234567890> are accepted too."),
+		),
+		formatOption(),
+	), usersHandler.UsersBulkResolveHandler)
+
+	s.AddTool(mcp.NewTool("users_presence",
+		mcp.WithDescription("Get whether a user is active or away, along with their status text, status emoji and timezone. The user is resolved like users_resolve does; when several users match, the best match is looked up and the others are listed in otherMatches."),
+		mcp.WithTitleAnnotation("User Presence"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("User ID, username, display name, real name or email. Can start with @ but it's not required."),
+		),
+		formatOption(),
+	), usersHandler.UsersPresenceHandler)
+
+	filesHandler := handler.NewFilesHandler(provider)
+	s.AddTool(mcp.NewTool("files_list",
+		mcp.WithDescription("List files shared in the workspace, newest first, with their ID, name, title, mimetype, size and permalink. Requires the files:read scope."),
+		mcp.WithTitleAnnotation("List Files"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Description("Only list files shared in this channel, in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		caseSensitiveOption(),
+		mcp.WithString("user",
+			mcp.Description("Only list files uploaded by this user, a user ID such as U1234567890 or a username with or without @."),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(100),
+			mcp.Description("Maximum number of files to return. Default is 100."),
+		),
+		formatOption(),
+	), filesHandler.FilesListHandler)
+
+	s.AddTool(mcp.NewTool("files_info",
+		mcp.WithDescription("Get the metadata of a single file: name, title, mimetype, size and permalink. Requires the files:read scope."),
+		mcp.WithTitleAnnotation("Get File Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("file_id",
+			mcp.Required(),
+			mcp.Description("ID of the file in format Fxxxxxxxxxx."),
+		),
+		formatOption(),
+	), filesHandler.FilesInfoHandler)
+
+	s.AddTool(mcp.NewTool("files_download",
+		mcp.WithDescription("Download the contents of a file, e.g. to extract the text of an attached document. Returned base64 encoded unless a path is given. Files larger than SLACK_MCP_MAX_FILE_BYTES (10 MiB by default) are refused. Requires the files:read scope."),
+		mcp.WithTitleAnnotation("Download File"),
+		mcp.WithString("file_id",
+			mcp.Required(),
+			mcp.Description("ID of the file in format Fxxxxxxxxxx."),
+		),
+		mcp.WithString("path",
+			mcp.Description("If set, the contents are written to this path, relative to SLACK_MCP_DOWNLOAD_DIR, instead of being returned. The file must not exist yet. Refused unless SLACK_MCP_DOWNLOAD_DIR is set."),
+		),
+		formatOption(),
+	), filesHandler.FilesDownloadHandler)
+
+	emojiHandler := handler.NewEmojiHandler(provider)
+	s.AddTool(mcp.NewTool("emoji_list",
+		mcp.WithDescription("List the workspace's custom emoji with their image URL. Aliases name the emoji they point to and carry its image URL, which is empty for aliases of standard emoji."),
+		mcp.WithTitleAnnotation("List Custom Emoji"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description("Only list emoji whose name contains this text, e.g. 'party' or ':partyparrot:'. Default lists all of them."),
+		),
+		formatOption(),
+	), emojiHandler.EmojiListHandler)
+
+	authHandler := handler.NewAuthHandler(provider)
+	s.AddTool(mcp.NewTool("auth_info",
+		mcp.WithDescription("Report the current auth context: token type (xoxp, xoxb, xoxc or offline), team, user and bot ID, and what it allows (canSearch, canPost, canReadHistory). Use it to learn which tools will work before calling them."),
+		mcp.WithTitleAnnotation("Auth Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		formatOption(),
+	), authHandler.AuthInfoHandler)
+
+	s.AddTool(mcp.NewTool("teams_list",
+		mcp.WithDescription("List the workspaces of the Enterprise Grid the token belongs to, with their ID, name, domain and URL. Fails on a workspace that is not part of a grid."),
+		mcp.WithTitleAnnotation("List Grid Teams"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		formatOption(),
+	), authHandler.TeamsListHandler)
+
+	cacheHandler := handler.NewCacheHandler(provider)
+
+	s.AddTool(mcp.NewTool("cache_refresh",
+		mcp.WithDescription("Refetch the users or channels cache from Slack instead of the cache files, e.g. after someone joined the workspace or a channel was created, and rewrite the files. Channels Slack no longer lists are dropped, users are kept"),
+		mcp.WithTitleAnnotation("Refresh Cache"),
+		mcp.WithString("cache",
+			mcp.DefaultString("all"),
+			mcp.Description("Cache to refresh. Allowed values: 'users', 'channels' or 'all' (default)."),
+		),
+		formatOption(),
+	), cacheHandler.CacheRefreshHandler)
+
 	return &MCPServer{
 		server: s,
 	}
 }
 
+// caseSensitiveOption is the case_sensitive parameter of the tools taking a
+// channel reference.
+func caseSensitiveOption() mcp.ToolOption {
+	return mcp.WithBoolean("case_sensitive",
+		mcp.Description("If true, channel and DM names only resolve with the same letter case. Default is boolean false, in which case a name differing in case, e.g. '#General', resolves when no name matches exactly."),
+		mcp.DefaultBool(false),
+	)
+}
+
+// formatOption is the format parameter of the tools returning rows.
+func formatOption() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.DefaultString("csv"),
+		mcp.Description("Output format: 'csv' (default) or 'json', an array with one object per CSV row whose keys are the CSV column names in camelCase, e.g. userID for UserID."),
+	)
+}
+
 func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
 	return server.NewSSEServer(s.server,
 		server.WithBaseURL(fmt.Sprintf("http://%s", addr)),
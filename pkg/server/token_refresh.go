@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// refreshOnInvalidAuth retries a tool call once after refresher reloaded the
+// credentials, when the call failed because Slack rejected them. If they
+// can't be reloaded the call fails with the reason and how to recover.
+func refreshOnInvalidAuth(refresher provider.TokenRefresher) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			res, err := next(ctx, req)
+			if err == nil || !provider.IsInvalidAuth(err) {
+				return res, err
+			}
+
+			log.Printf("Tool %s failed with invalid credentials, reloading them: %v", req.Params.Name, err)
+			if rerr := refresher.RefreshToken(ctx); rerr != nil {
+				return nil, fmt.Errorf("%v: %w", err, rerr)
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package text
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// standardEmoji maps commonly used standard Slack emoji names to their
+// unicode glyphs. Names missing here, custom emoji included, are rendered in
+// their :name: form.
+var standardEmoji = map[string]string{
+	"+1":                            "👍",
+	"thumbsup":                      "👍",
+	"-1":                            "👎",
+	"thumbsdown":                    "👎",
+	"tada":                          "🎉",
+	"smile":                         "😄",
+	"smiley":                        "😃",
+	"grinning":                      "😀",
+	"laughing":                      "😆",
+	"joy":                           "😂",
+	"rolling_on_the_floor_laughing": "🤣",
+	"slightly_smiling_face":         "🙂",
+	"wink":                          "😉",
+	"blush":                         "😊",
+	"heart_eyes":                    "😍",
+	"thinking_face":                 "🤔",
+	"neutral_face":                  "😐",
+	"sweat_smile":                   "😅",
+	"cry":                           "😢",
+	"sob":                           "😭",
+	"scream":                        "😱",
+	"rage":                          "😡",
+	"pray":                          "🙏",
+	"clap":                          "👏",
+	"wave":                          "👋",
+	"ok_hand":                       "👌",
+	"muscle":                        "💪",
+	"raised_hands":                  "🙌",
+	"point_up":                      "☝️",
+	"eyes":                          "👀",
+	"heart":                         "❤️",
+	"broken_heart":                  "💔",
+	"fire":                          "🔥",
+	"star":                          "⭐",
+	"sparkles":                      "✨",
+	"rocket":                        "🚀",
+	"100":                           "💯",
+	"white_check_mark":              "✅",
+	"heavy_check_mark":              "✔️",
+	"x":                             "❌",
+	"warning":                       "⚠️",
+	"exclamation":                   "❗",
+	"question":                      "❓",
+	"bulb":                          "💡",
+	"memo":                          "📝",
+	"calendar":                      "📆",
+	"pushpin":                       "📌",
+	"link":                          "🔗",
+	"lock":                          "🔒",
+	"bell":                          "🔔",
+	"mega":                          "📣",
+	"coffee":                        "☕",
+	"beers":                         "🍻",
+	"cake":                          "🍰",
+	"gift":                          "🎁",
+	"trophy":                        "🏆",
+	"bug":                           "🐛",
+	"hammer_and_wrench":             "🛠️",
+	"construction":                  "🚧",
+	"rotating_light":                "🚨",
+	"hourglass_flowing_sand":        "⏳",
+	"zap":                           "⚡",
+	"sunny":                         "☀️",
+	"umbrella":                      "☔",
+	"see_no_evil":                   "🙈",
+	"raising_hand":                  "🙋",
+	"ghost":                         "👻",
+	"skull":                         "💀",
+	"poop":                          "💩",
+	"hankey":                        "💩",
+}
+
+// emojiGlyphChars holds the symbol runes of the standardEmoji glyphs,
+// escaped for a regexp character class, along with the skin tone modifiers
+// and the zero width joiner they combine with. They are the only symbols
+// filterSpecialChars keeps, any other one is stripped like before.
+var emojiGlyphChars = func() string {
+	var symbols []rune
+	for _, glyph := range standardEmoji {
+		for _, r := range glyph {
+			if unicode.Is(unicode.So, r) && !slices.Contains(symbols, r) {
+				symbols = append(symbols, r)
+			}
+		}
+	}
+	slices.Sort(symbols)
+	return regexp.QuoteMeta(string(symbols)) + `\x{1F3FB}-\x{1F3FF}\x{200D}`
+}()
+
+// keptGlyphRegex matches the glyphs filterSpecialChars keeps whole.
+var keptGlyphRegex = regexp.MustCompile(`^[\p{M}` + emojiGlyphChars + `]+$`)
+
+// emojiText renders an emoji as its unicode glyph when known, preferring the
+// codepoints Slack sends along with the element, and as :name: otherwise.
+// Codepoints decoding to a glyph the clean-up would strip, one missing from
+// standardEmoji, are rendered as :name: too rather than vanish.
+func emojiText(name, unicode string) string {
+	if glyph := decodeEmojiCodepoints(unicode); glyph != "" && keptGlyphRegex.MatchString(glyph) {
+		return glyph
+	}
+	if glyph, ok := standardEmoji[name]; ok {
+		return glyph
+	}
+	return ":" + name + ":"
+}
+
+// decodeEmojiCodepoints decodes Slack's dash separated hex codepoints, e.g.
+// "1f44b-1f3fb", returning an empty string when they are malformed.
+func decodeEmojiCodepoints(unicode string) string {
+	if unicode == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, cp := range strings.Split(unicode, "-") {
+		r, err := strconv.ParseUint(cp, 16, 32)
+		if err != nil {
+			return ""
+		}
+		sb.WriteRune(rune(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package text
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	NameDisplay  = "display"  // the profile display name
+	NameReal     = "real"     // the full name
+	NameUsername = "username" // the handle
+)
+
+var (
+	namePrefOnce sync.Once
+	namePrefs    []string
+)
+
+// namePref returns the order in which user names are tried, configured with
+// SLACK_MCP_DISPLAY_NAME_PREF as a comma-separated list, e.g. "real,username".
+// The variable is parsed once, nil means it is unset.
+func namePref() []string {
+	namePrefOnce.Do(func() {
+		namePrefs = parseNamePref(os.Getenv("SLACK_MCP_DISPLAY_NAME_PREF"))
+	})
+	return namePrefs
+}
+
+// SetNamePref replaces the preference read from SLACK_MCP_DISPLAY_NAME_PREF
+// with v, an empty v restores the per-call defaults.
+func SetNamePref(v string) {
+	namePrefOnce.Do(func() {})
+	namePrefs = parseNamePref(v)
+}
+
+func parseNamePref(v string) []string {
+	if v == "" {
+		return nil
+	}
+
+	var pref []string
+	for _, p := range strings.Split(v, ",") {
+		switch p = strings.TrimSpace(p); p {
+		case NameDisplay, NameReal, NameUsername:
+			pref = append(pref, p)
+		default:
+			log.Printf("Invalid SLACK_MCP_DISPLAY_NAME_PREF entry %q, expected display, real or username", p)
+		}
+	}
+	return pref
+}
+
+// UserName returns the first non-empty name of u in the configured
+// preference, or in order when none is configured. It returns an empty
+// string when u has none of them.
+func UserName(u slack.User, order ...string) string {
+	if pref := namePref(); len(pref) > 0 {
+		order = pref
+	}
+	for _, p := range order {
+		var name string
+		switch p {
+		case NameDisplay:
+			name = u.Profile.DisplayName
+		case NameReal:
+			name = u.RealName
+		case NameUsername:
+			name = u.Name
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// UserDisplayName returns the name Slack shows for u, see UserName, trying
+// the display name, the real name and the username by default and falling
+// back to the user ID.
+func UserDisplayName(u slack.User) string {
+	if name := UserName(u, NameDisplay, NameReal, NameUsername); name != "" {
+		return name
+	}
+	return u.ID
+}
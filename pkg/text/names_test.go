@@ -0,0 +1,50 @@
+package text
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestUserDisplayName(t *testing.T) {
+	full := slack.User{ID: "U1", Name: "jdoe", RealName: "Jane Doe", Profile: slack.UserProfile{DisplayName: "Jane"}}
+	noDisplay := slack.User{ID: "U2", Name: "bob", RealName: "Bob Builder"}
+
+	tests := []struct {
+		pref     string
+		user     slack.User
+		expected string
+	}{
+		{pref: "", user: full, expected: "Jane"},
+		{pref: "", user: noDisplay, expected: "Bob Builder"},
+		{pref: "real,username", user: full, expected: "Jane Doe"},
+		{pref: "username", user: full, expected: "jdoe"},
+		{pref: " display , username ", user: noDisplay, expected: "bob"},
+		{pref: "display", user: noDisplay, expected: "U2"},
+		{pref: "nickname", user: full, expected: "Jane"}, // invalid entries fall back to the default
+	}
+	t.Cleanup(func() { SetNamePref("") })
+	for _, tt := range tests {
+		SetNamePref(tt.pref)
+		if got := UserDisplayName(tt.user); got != tt.expected {
+			t.Errorf("UserDisplayName(%s) with pref %q = %q, expected %q", tt.user.ID, tt.pref, got, tt.expected)
+		}
+	}
+}
+
+func TestNamePref_ParsedOnce(t *testing.T) {
+	namePrefOnce = sync.Once{}
+	t.Cleanup(func() { SetNamePref("") })
+
+	t.Setenv("SLACK_MCP_DISPLAY_NAME_PREF", "username")
+	u := slack.User{ID: "U1", Name: "jdoe", RealName: "Jane Doe"}
+	if got := UserDisplayName(u); got != "jdoe" {
+		t.Fatalf("expected the configured preference, got %q", got)
+	}
+
+	t.Setenv("SLACK_MCP_DISPLAY_NAME_PREF", "real")
+	if got := UserDisplayName(u); got != "jdoe" {
+		t.Errorf("expected the preference read on first use to stick, got %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package text
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+var (
+	// markupRegex matches any Slack markup token: links, user and channel
+	// mentions and special mentions, with an optional label. A decoded "<"
+	// in front of anything else, e.g. <tag>, is text.
+	markupRegex = regexp.MustCompile(`<((?:[@#!]|(?:https?|mailto|tel):)[^>|]*)(?:\|([^>]*))?>`)
+	// boldRegex and italicRegex match *bold* and _italic_ markers standing
+	// apart from words, so that snake_case and 2*3*4 are left alone.
+	boldRegex   = regexp.MustCompile(`(^|[^\w*])\*([^*\n]+)\*($|[^\w*])`)
+	italicRegex = regexp.MustCompile(`(^|[^\w_])_([^_\n]+)_($|[^\w_])`)
+)
+
+// ProcessTextPlain converts Slack's message format to plain text, e.g. for
+// feeding messages to an LLM: links become "label (url)", user mentions
+// @name using users, channel mentions #name using channelName and then their
+// label, special mentions @here or their label, and *bold* and _italic_
+// markers are stripped. Either lookup may be nil. Unlike ProcessText no
+// punctuation is dropped.
+//
+// Entities are decoded, s may be raw message text or text as
+// ExtractTextFromMessage returns it, which is not decoded a second time.
+func ProcessTextPlain(s string, users map[string]slack.User, channelName ChannelNamer) string {
+	s = decodeEntities(s)
+
+	// Markup is rendered first and kept aside, so that markers inside URLs,
+	// e.g. https://example.com/a_b_c, are not taken for formatting
+	var tokens []string
+	s = markupRegex.ReplaceAllStringFunc(s, func(token string) string {
+		match := markupRegex.FindStringSubmatch(token)
+		tokens = append(tokens, renderPlainMarkup(match[1], match[2], users, channelName))
+		return plainPlaceholder(len(tokens) - 1)
+	})
+
+	s = stripMarkers(s, boldRegex)
+	s = stripMarkers(s, italicRegex)
+
+	for i, token := range tokens {
+		s = strings.Replace(s, plainPlaceholder(i), token, 1)
+	}
+
+	return restoreEscapes(s)
+}
+
+// renderPlainMarkup renders the target and label of a markup token.
+func renderPlainMarkup(target, label string, users map[string]slack.User, channelName ChannelNamer) string {
+	label = strings.TrimSpace(label)
+
+	switch {
+	case strings.HasPrefix(target, "@"):
+		id := target[1:]
+		if u, ok := users[id]; ok {
+			return "@" + UserDisplayName(u)
+		}
+		if label != "" {
+			return "@" + strings.TrimPrefix(label, "@")
+		}
+		return target
+	case strings.HasPrefix(target, "#"):
+		id := target[1:]
+		if channelName != nil {
+			if name, ok := channelName(id); ok {
+				return "#" + name
+			}
+		}
+		if label != "" {
+			return "#" + strings.TrimPrefix(label, "#")
+		}
+		return target
+	case strings.HasPrefix(target, "!"):
+		if label != "" {
+			return label
+		}
+		// e.g. <!subteam^S123> or <!date^1700000000^{date}>
+		name, _, _ := strings.Cut(target[1:], "^")
+		return "@" + name
+	case strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "tel:"):
+		if label != "" {
+			return label
+		}
+		_, addr, _ := strings.Cut(target, ":")
+		return addr
+	}
+
+	if label == "" || label == target {
+		return target
+	}
+	return label + " (" + target + ")"
+}
+
+// stripMarkers removes the markers matched by re, keeping what they enclose.
+// Adjacent matches share the character between them, hence the loop.
+func stripMarkers(s string, re *regexp.Regexp) string {
+	for {
+		stripped := re.ReplaceAllString(s, "${1}${2}${3}")
+		if stripped == s {
+			return s
+		}
+		s = stripped
+	}
+}
+
+func plainPlaceholder(i int) string {
+	return "\x00" + strconv.Itoa(i) + "\x00"
+}
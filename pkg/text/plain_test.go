@@ -0,0 +1,75 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestProcessTextPlain(t *testing.T) {
+	users := map[string]slack.User{
+		"U12345678": {ID: "U12345678", Name: "alice"},
+	}
+	channelName := func(id string) (string, bool) {
+		if id == "C12345678" {
+			return "general", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "labelled link", input: "see <https://example.com/a_b_c|the docs>", expected: "see the docs (https://example.com/a_b_c)"},
+		{name: "bare link", input: "<https://example.com>", expected: "https://example.com"},
+		{name: "mailto link", input: "mail <mailto:bob@example.com|Bob>", expected: "mail Bob"},
+		{name: "user mention", input: "ask <@U12345678>", expected: "ask @alice"},
+		{name: "unknown user keeps its label", input: "ask <@U99999999|bob>", expected: "ask @bob"},
+		{name: "unknown user keeps its ID", input: "ask <@U99999999>", expected: "ask @U99999999"},
+		{name: "cached channel", input: "in <#C12345678>", expected: "in #general"},
+		{name: "uncached channel label", input: "in <#C99999999|random>", expected: "in #random"},
+		{name: "special mention", input: "<!here> standup", expected: "@here standup"},
+		{name: "user group", input: "<!subteam^S123|@oncall> help", expected: "@oncall help"},
+		{name: "bold", input: "this is *important* and *urgent*", expected: "this is important and urgent"},
+		{name: "italic", input: "_really_ _quite_ sure", expected: "really quite sure"},
+		{name: "snake_case and products are kept", input: "set max_retry_count to 2*3*4", expected: "set max_retry_count to 2*3*4"},
+		{name: "decoded angle brackets are text", input: "A & B <tag>", expected: "A & B <tag>"},
+		{name: "entities are decoded", input: "Tom &amp; Jerry &lt;b&gt;", expected: "Tom & Jerry <b>"},
+		{name: "punctuation is kept", input: "Done! (finally) - 100%", expected: "Done! (finally) - 100%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProcessTextPlain(tt.input, users, channelName); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProcessTextPlain_DecodesEntitiesOnce(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// a literal "&lt;" typed by the user arrives as &amp;lt;
+		{input: "&amp;lt;", expected: "&lt;"},
+		{input: "&amp;amp;", expected: "&amp;"},
+		{input: "<https://example.com?a=1&amp;b=2|Tom &amp; Jerry>", expected: "Tom & Jerry (https://example.com?a=1&b=2)"},
+		// decoded markup characters are not parsed as markup again
+		{input: "&lt;b&gt;*x*&lt;/b&gt;", expected: "<b>x</b>"},
+		{input: "&lt;@U12345678&gt;", expected: "<@U12345678>"},
+		{input: "&amp;lt;@U12345678&amp;gt;", expected: "&lt;@U12345678&gt;"},
+	}
+	for _, tt := range tests {
+		msg := &slack.Message{Msg: slack.Msg{Text: tt.input}}
+		if got := ProcessTextPlain(ExtractTextFromMessage(msg), nil, nil); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, got)
+		}
+		// raw text renders the same, decoded once
+		if got := ProcessTextPlain(tt.input, nil, nil); got != tt.expected {
+			t.Errorf("%q: expected %q from raw text, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package text
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+var (
+	// pollOptionMarker matches the emoji numbering poll apps put in front of
+	// each option, e.g. ":one: " or ":regional_indicator_a: ".
+	pollOptionMarker = regexp.MustCompile(`^:[a-z0-9_+\-]+:\s*`)
+	// pollInlineVotes matches a vote count appended to the option text as
+	// inline code, e.g. "Option A `3`".
+	pollInlineVotes = regexp.MustCompile("\\s*`(\\d+)`$")
+	// pollContextVotes matches a vote count in the context block below an
+	// option, e.g. "3 votes" or "No votes".
+	pollContextVotes = regexp.MustCompile(`(?i)^(\d+|no) votes?$`)
+)
+
+type pollOption struct {
+	label string
+	votes int
+}
+
+// extractPoll renders blocks posted by poll apps such as Simple Poll as
+// "Question / Option A (3 votes) / Option B (1 vote)". Polls are recognised
+// by a question followed by at least two sections with a vote button, each
+// carrying a vote count inline or in a context block right below it.
+func extractPoll(blocks []slack.Block) (string, bool) {
+	var (
+		question string
+		options  []pollOption
+	)
+
+	for i := 0; i < len(blocks); i++ {
+		switch b := blocks[i].(type) {
+		case *slack.HeaderBlock:
+			if question == "" && len(options) == 0 && b.Text != nil {
+				question = b.Text.Text
+			}
+		case *slack.SectionBlock:
+			if b.Text == nil {
+				continue
+			}
+			if b.Accessory == nil || b.Accessory.ButtonElement == nil {
+				if question == "" && len(options) == 0 {
+					question = b.Text.Text
+				}
+				continue
+			}
+
+			opt, ok := parsePollOption(b.Text.Text)
+			if !ok && i+1 < len(blocks) {
+				if ctx, isCtx := blocks[i+1].(*slack.ContextBlock); isCtx {
+					if votes, found := contextVotes(ctx); found {
+						opt.votes, ok = votes, true
+						i++
+					}
+				}
+			}
+			if !ok {
+				return "", false
+			}
+			options = append(options, opt)
+		}
+	}
+
+	if question == "" || len(options) < 2 {
+		return "", false
+	}
+
+	parts := []string{strings.Trim(question, "* ")}
+	for _, opt := range options {
+		unit := "votes"
+		if opt.votes == 1 {
+			unit = "vote"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d %s)", opt.label, opt.votes, unit))
+	}
+
+	return strings.Join(parts, " / "), true
+}
+
+// parsePollOption strips the numbering from an option and reports whether
+// the text carried an inline vote count.
+func parsePollOption(text string) (pollOption, bool) {
+	label := pollOptionMarker.ReplaceAllString(strings.TrimSpace(text), "")
+
+	m := pollInlineVotes.FindStringSubmatch(label)
+	if m == nil {
+		return pollOption{label: label}, false
+	}
+
+	votes, _ := strconv.Atoi(m[1])
+	return pollOption{
+		label: strings.TrimSpace(strings.TrimSuffix(label, m[0])),
+		votes: votes,
+	}, true
+}
+
+func contextVotes(block *slack.ContextBlock) (int, bool) {
+	for _, element := range block.ContextElements.Elements {
+		t, ok := element.(*slack.TextBlockObject)
+		if !ok {
+			continue
+		}
+		m := pollContextVotes.FindStringSubmatch(strings.TrimSpace(t.Text))
+		if m == nil {
+			continue
+		}
+		votes, _ := strconv.Atoi(m[1]) // "no" leaves votes at 0
+		return votes, true
+	}
+	return 0, false
+}
@@ -0,0 +1,67 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func pollSection(text, value string) *slack.SectionBlock {
+	button := slack.NewButtonBlockElement("vote", value, slack.NewTextBlockObject(slack.PlainTextType, "Vote", false, false))
+	return slack.NewSectionBlock(
+		slack.NewTextBlockObject(slack.MarkdownType, text, false, false),
+		nil,
+		slack.NewAccessory(button),
+	)
+}
+
+func pollVotes(text string) *slack.ContextBlock {
+	return slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, text, false, false))
+}
+
+func TestExtractPoll_SimplePoll(t *testing.T) {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*Where should we go for lunch?*", false, false), nil, nil),
+		pollSection(":one: Ramen", "1"),
+		pollVotes("3 votes"),
+		pollSection(":two: Tacos", "2"),
+		pollVotes("1 vote"),
+		pollSection(":three: Salad", "3"),
+		pollVotes("No votes"),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, "Created by <@U12345678> with /poll", false, false)),
+	}
+
+	expected := "Where should we go for lunch? / Ramen (3 votes) / Tacos (1 vote) / Salad (0 votes)"
+
+	result := extractTextFromBlocks(blocks)
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestExtractPoll_InlineVotes(t *testing.T) {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "Release day?", false, false)),
+		pollSection(":regional_indicator_a: Tuesday `2`", "a"),
+		pollSection(":regional_indicator_b: Thursday `5`", "b"),
+	}
+
+	expected := "Release day? / Tuesday (2 votes) / Thursday (5 votes)"
+
+	poll, ok := extractPoll(blocks)
+	if !ok || poll != expected {
+		t.Errorf("Expected %q, got %q (ok=%v)", expected, poll, ok)
+	}
+}
+
+func TestExtractPoll_NotAPoll(t *testing.T) {
+	// a single section with a button is an ordinary interactive message
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "Build failed", false, false), nil, nil),
+		pollSection("See logs", "logs"),
+	}
+
+	if poll, ok := extractPoll(blocks); ok {
+		t.Errorf("Expected no poll, got %q", poll)
+	}
+}
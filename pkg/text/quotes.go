@@ -0,0 +1,68 @@
+package text
+
+import (
+	"html"
+	"strings"
+)
+
+// CollapseQuotedReplies strips the leading quoted block of a message when
+// it repeats what earlier messages of the same conversation already said,
+// as email-style replies re-quoting the whole chain do. texts are raw
+// message texts, before ProcessText, oldest first. Quoted lines are dropped
+// from the top for as long as each one equals a whole line of an earlier
+// message, compared normalized, the reply's own content is kept. A message
+// consisting of nothing but such quotes is left as it is.
+func CollapseQuotedReplies(texts []string) []string {
+	res := make([]string, len(texts))
+
+	seen := map[string]bool{}
+	for i, s := range texts {
+		lines := strings.Split(s, "\n")
+
+		n := 0
+		for ; n < len(lines); n++ {
+			content, quoted := unquoteLine(lines[n])
+			if c := normalizeQuote(content); !quoted || (c != "" && !seen[c]) {
+				break
+			}
+		}
+
+		if n > 0 && n < len(lines) && strings.TrimSpace(strings.Join(lines[n:], "\n")) != "" {
+			res[i] = strings.Join(lines[n:], "\n")
+		} else {
+			res[i] = s
+		}
+
+		for _, line := range lines {
+			content, _ := unquoteLine(line)
+			seen[normalizeQuote(content)] = true
+		}
+	}
+
+	return res
+}
+
+// unquoteLine strips all quote markers, nested ones included, from the
+// start of line and reports whether there were any. Slack sends them HTML
+// escaped in the text field.
+func unquoteLine(line string) (string, bool) {
+	quoted := false
+	for {
+		line = strings.TrimLeft(line, " \t")
+		switch {
+		case strings.HasPrefix(line, "&gt;"):
+			line = line[len("&gt;"):]
+		case strings.HasPrefix(line, ">"):
+			line = line[1:]
+		default:
+			return line, quoted
+		}
+		quoted = true
+	}
+}
+
+// normalizeQuote lowercases s and collapses its whitespace, an empty quote
+// line normalizes to "" and counts as said before.
+func normalizeQuote(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(html.UnescapeString(s)), " "))
+}
@@ -0,0 +1,64 @@
+package text
+
+import (
+	"testing"
+)
+
+func TestCollapseQuotedReplies_NestedChain(t *testing.T) {
+	// every reply re-quotes the whole chain, the way Slack escapes it
+	texts := []string{
+		"Can we move the release to Friday?",
+		"&gt; Can we move the release to Friday?\nFriday works, QA needs Thursday though.",
+		"&gt; Friday works, QA needs Thursday though.\n&gt; &gt; Can we move the release to Friday?\nThen Friday it is.",
+	}
+
+	got := CollapseQuotedReplies(texts)
+	expected := []string{
+		"Can we move the release to Friday?",
+		"Friday works, QA needs Thursday though.",
+		"Then Friday it is.",
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("message %d: got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestCollapseQuotedReplies_KeepsUniqueQuotes(t *testing.T) {
+	texts := []string{
+		"First message",
+		// quoting something from outside the conversation is content
+		"&gt; From the vendor: shipping is delayed\nFYI",
+		// only the duplicate part of the leading quote goes
+		"&gt; First message\n&gt; and something never said\nreply",
+		// a message made of a duplicate quote only is left alone
+		"&gt; First message",
+	}
+
+	got := CollapseQuotedReplies(texts)
+	expected := []string{
+		"First message",
+		"&gt; From the vendor: shipping is delayed\nFYI",
+		"&gt; and something never said\nreply",
+		"&gt; First message",
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("message %d: got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestCollapseQuotedReplies_ComparesWholeLines(t *testing.T) {
+	texts := []string{
+		"The deploy is done for the api service",
+		// a quote that is only part of an earlier line says something new
+		"&gt; The deploy is done\nreally?",
+	}
+
+	got := CollapseQuotedReplies(texts)
+	if got[1] != texts[1] {
+		t.Errorf("expected a partial quote to be kept, got %q", got[1])
+	}
+}
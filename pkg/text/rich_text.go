@@ -2,11 +2,43 @@ package text
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
 )
 
+// Default caps on how many attachments and blocks of a single message are
+// processed, so that a huge message can't blow up extraction time and output.
+const (
+	defaultMaxAttachments = 100
+	defaultMaxBlocks      = 500
+)
+
+// maxAttachments returns the number of attachments processed per message,
+// overridable through SLACK_MCP_MAX_ATTACHMENTS.
+func maxAttachments() int {
+	return envLimit("SLACK_MCP_MAX_ATTACHMENTS", defaultMaxAttachments)
+}
+
+// maxBlocks returns the number of blocks processed per message, overridable
+// through SLACK_MCP_MAX_BLOCKS.
+func maxBlocks() int {
+	return envLimit("SLACK_MCP_MAX_BLOCKS", defaultMaxBlocks)
+}
+
+func envLimit(name string, def int) int {
+	if v := os.Getenv(name); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return def
+}
+
 // ExtractTextFromMessage extracts all text content from a Slack message,
 // including text from blocks and attachments (rich format support).
 func ExtractTextFromMessage(msg *slack.Message) string {
@@ -14,7 +46,7 @@ func ExtractTextFromMessage(msg *slack.Message) string {
 
 	// 1. Basic text field
 	if msg.Text != "" {
-		parts = append(parts, msg.Text)
+		parts = append(parts, decodeEntities(msg.Text))
 	}
 
 	// 2. Extract text from Blocks
@@ -49,6 +81,17 @@ func ExtractTextFromMessage(msg *slack.Message) string {
 func extractTextFromBlocks(blocks []slack.Block) string {
 	var parts []string
 
+	var skipped int
+	if limit := maxBlocks(); len(blocks) > limit {
+		skipped = len(blocks) - limit
+		blocks = blocks[:limit]
+	}
+
+	if poll, ok := extractPoll(blocks); ok {
+		parts = append(parts, poll)
+		blocks = nil
+	}
+
 	for _, block := range blocks {
 		switch b := block.(type) {
 		case *slack.SectionBlock:
@@ -61,9 +104,17 @@ func extractTextFromBlocks(blocks []slack.Block) string {
 			}
 		case *slack.ContextBlock:
 			parts = append(parts, extractTextFromContextBlock(b)...)
+		case *slack.ActionBlock:
+			if text := extractTextFromActionBlock(b); text != "" {
+				parts = append(parts, text)
+			}
 		}
 	}
 
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("[+%d more blocks]", skipped))
+	}
+
 	return strings.Join(parts, "\n")
 }
 
@@ -81,6 +132,15 @@ func extractTextFromSectionBlock(block *slack.SectionBlock) []string {
 		}
 	}
 
+	if a := block.Accessory; a != nil {
+		switch {
+		case a.ImageElement != nil && a.ImageElement.AltText != "":
+			parts = append(parts, "[Image: "+a.ImageElement.AltText+"]")
+		case a.ButtonElement != nil && a.ButtonElement.Text != nil && a.ButtonElement.Text.Text != "":
+			parts = append(parts, "["+a.ButtonElement.Text.Text+"]")
+		}
+	}
+
 	return parts
 }
 
@@ -105,9 +165,7 @@ func extractTextFromRichTextElement(element slack.RichTextElement) []string {
 			parts = append(parts, extractTextFromRichTextSectionElement(elem)...)
 		}
 	case *slack.RichTextList:
-		for _, item := range e.Elements {
-			parts = append(parts, extractTextFromRichTextElement(item)...)
-		}
+		parts = append(parts, extractTextFromRichTextList(e, 0)...)
 	case *slack.RichTextQuote:
 		for _, elem := range e.Elements {
 			parts = append(parts, extractTextFromRichTextSectionElement(elem)...)
@@ -144,9 +202,9 @@ func extractTextFromRichTextSectionElement(element slack.RichTextSectionElement)
 	case *slack.RichTextSectionChannelElement:
 		parts = append(parts, "<#"+e.ChannelID+">")
 	case *slack.RichTextSectionEmojiElement:
-		parts = append(parts, ":"+e.Name+":")
+		parts = append(parts, emojiText(e.Name, e.Unicode))
 	case *slack.RichTextSectionDateElement:
-		parts = append(parts, fmt.Sprintf("%d", e.Timestamp))
+		parts = append(parts, dateElementText(e))
 	}
 
 	return parts
@@ -174,7 +232,16 @@ func extractTextFromContextBlock(block *slack.ContextBlock) []string {
 func extractTextFromAttachments(attachments []slack.Attachment) string {
 	var parts []string
 
+	var skipped int
+	if limit := maxAttachments(); len(attachments) > limit {
+		skipped = len(attachments) - limit
+		attachments = attachments[:limit]
+	}
+
 	for _, att := range attachments {
+		// Severity hint from the color bar, applied to the first line
+		first := len(parts)
+
 		// Title
 		if att.Title != "" {
 			titleText := att.Title
@@ -186,22 +253,29 @@ func extractTextFromAttachments(attachments []slack.Attachment) string {
 
 		// Pretext
 		if att.Pretext != "" {
-			parts = append(parts, att.Pretext)
+			parts = append(parts, decodeEntities(att.Pretext))
 		}
 
-		// Main text
-		if att.Text != "" {
-			parts = append(parts, att.Text)
+		// Main text, the plain text fallback stands in when it is missing
+		mainText := att.Text
+		if mainText == "" {
+			mainText = att.Fallback
+		}
+		forward, isForward := forwardAttribution(att)
+		if isForward {
+			parts = append(parts, forward+" "+decodeEntities(mainText))
+		} else if mainText != "" {
+			parts = append(parts, decodeEntities(mainText))
 		}
 
-		// Author
-		if att.AuthorName != "" {
+		// Author, part of the attribution of forwards
+		if att.AuthorName != "" && !isForward {
 			parts = append(parts, "Author: "+att.AuthorName)
 		}
 
 		// Fields
 		for _, field := range att.Fields {
-			fieldText := field.Title + ": " + field.Value
+			fieldText := field.Title + ": " + decodeEntities(field.Value)
 			parts = append(parts, fieldText)
 		}
 
@@ -217,11 +291,93 @@ func extractTextFromAttachments(attachments []slack.Attachment) string {
 				parts = append(parts, blockText)
 			}
 		}
+
+		if severity := attachmentSeverity(att.Color); severity != "" && len(parts) > first {
+			parts[first] = "[" + severity + "] " + parts[first]
+		}
+	}
+
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("[+%d more attachments]", skipped))
 	}
 
 	return strings.Join(parts, "\n")
 }
 
+// archivesChannelRegex extracts the channel ID from a message permalink,
+// e.g. https://example.slack.com/archives/C12345678/p1700000000000100.
+var archivesChannelRegex = regexp.MustCompile(`^https://[^/]+\.slack\.com/archives/([CDG][A-Z0-9]+)/p\d+`)
+
+// forwardAttribution returns "Forwarded from @author in <#C...>:" for an
+// attachment quoting another message, which carries that message's ts,
+// author and permalink in from_url. Integrations such as GitHub or Jira set
+// ts and author_name too, but link elsewhere, they are not forwards.
+func forwardAttribution(att slack.Attachment) (string, bool) {
+	if att.Ts == "" || att.AuthorName == "" {
+		return "", false
+	}
+	m := archivesChannelRegex.FindStringSubmatch(att.FromURL)
+	if m == nil {
+		return "", false
+	}
+
+	return "Forwarded from @" + strings.TrimPrefix(att.AuthorName, "@") + " in <#" + m[1] + ">:", true
+}
+
+// markupStartRegex matches an escaped "<" which, decoded, would start Slack
+// markup such as a mention or a link.
+var markupStartRegex = regexp.MustCompile(`&lt;([@#!]|(?:https?|mailto|tel):)`)
+
+// escapedEntityRegex matches an escaped "&" which, decoded, would start an
+// entity, e.g. &amp;lt; for a literal "&lt;" typed by the user.
+var escapedEntityRegex = regexp.MustCompile(`&amp;((?:lt|gt|amp);)`)
+
+// decodeEntities holds the characters which, decoded, would be taken for
+// markup or an entity again as private use runes, so that decoding its
+// output once more changes nothing. restoreEscapes turns them back into
+// literal characters once markup has been parsed.
+const (
+	escapedAmp = "\uE000"
+	escapedLT  = "\uE001"
+)
+
+var escapeRestorer = strings.NewReplacer(escapedAmp, "&", escapedLT, "<")
+
+// restoreEscapes turns the characters decodeEntities held back into the
+// literal "&" and "<" they stand for.
+func restoreEscapes(s string) string {
+	return escapeRestorer.Replace(s)
+}
+
+// slackEntities decodes the entities Slack escapes, &amp; last so that an
+// escaped entity such as &amp;lt; decodes to &lt; and not further.
+var slackEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// decodeEntities decodes the only entities Slack escapes in message text,
+// &amp;, &lt; and &gt;. It is idempotent: extraction decodes, and
+// ProcessTextPlain decodes again for callers passing raw text. Markup is
+// left intact, only &amp; is decoded within it. An escaped "<" typed in
+// front of what looks like markup, e.g. "&lt;@U12345678&gt;", which would
+// otherwise render as a mention, and an escaped "&" in front of an entity
+// are held back, see restoreEscapes.
+func decodeEntities(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range markupRegex.FindAllStringIndex(s, -1) {
+		b.WriteString(decodeOutsideMarkup(s[last:loc[0]]))
+		b.WriteString(strings.ReplaceAll(s[loc[0]:loc[1]], "&amp;", "&"))
+		last = loc[1]
+	}
+	b.WriteString(decodeOutsideMarkup(s[last:]))
+	return b.String()
+}
+
+func decodeOutsideMarkup(s string) string {
+	s = escapedEntityRegex.ReplaceAllString(s, escapedAmp+"$1")
+	s = markupStartRegex.ReplaceAllString(s, escapedLT+"$1")
+	return slackEntities.Replace(s)
+}
+
 // extractTextFromFiles extracts text from file metadata
 func extractTextFromFiles(files []slack.File) string {
 	var parts []string
@@ -249,7 +405,7 @@ func ExtractTextFromSearchMessage(msg *slack.SearchMessage) string {
 
 	// 1. Basic text field
 	if msg.Text != "" {
-		parts = append(parts, msg.Text)
+		parts = append(parts, decodeEntities(msg.Text))
 	}
 
 	// 2. Extract text from Blocks
@@ -296,3 +452,94 @@ func deduplicateAndJoin(parts []string) string {
 
 	return strings.Join(unique, "\n")
 }
+
+// extractTextFromRichTextList renders each list item on its own line,
+// prefixed with "- " or its number and indented two spaces per level. Slack
+// usually sends nested lists as sibling lists with a higher Indent, lists
+// nested inside items are indented one level further than their parent.
+func extractTextFromRichTextList(list *slack.RichTextList, depth int) []string {
+	var parts []string
+
+	indent := strings.Repeat("  ", list.Indent+depth)
+	number := list.Offset
+	for _, item := range list.Elements {
+		if nested, ok := item.(*slack.RichTextList); ok {
+			parts = append(parts, extractTextFromRichTextList(nested, depth+1)...)
+			continue
+		}
+
+		marker := "- "
+		if list.Style == slack.RTEListOrdered {
+			number++
+			marker = strconv.Itoa(number) + ". "
+		}
+		parts = append(parts, indent+marker+strings.Join(extractTextFromRichTextElement(item), ""))
+	}
+
+	return parts
+}
+
+// extractTextFromActionBlock renders the interactive elements of an actions
+// block on one line: buttons as [label], menus and choices as their option
+// labels.
+func extractTextFromActionBlock(block *slack.ActionBlock) string {
+	if block.Elements == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, elem := range block.Elements.ElementSet {
+		var options []*slack.OptionBlockObject
+		switch e := elem.(type) {
+		case *slack.ButtonBlockElement:
+			if e.Text != nil && e.Text.Text != "" {
+				parts = append(parts, "["+e.Text.Text+"]")
+			}
+		case *slack.SelectBlockElement:
+			options = e.Options
+		case *slack.MultiSelectBlockElement:
+			options = e.Options
+		case *slack.OverflowBlockElement:
+			options = e.Options
+		case *slack.RadioButtonsBlockElement:
+			options = e.Options
+		case *slack.CheckboxGroupsBlockElement:
+			options = e.Options
+		}
+
+		var labels []string
+		for _, opt := range options {
+			if opt != nil && opt.Text != nil && opt.Text.Text != "" {
+				labels = append(labels, opt.Text.Text)
+			}
+		}
+		if len(labels) > 0 {
+			parts = append(parts, strings.Join(labels, " / "))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// attachmentSeverity maps an attachment color to the name of Slack's
+// predefined color it matches, or an empty string for any other color.
+func attachmentSeverity(color string) string {
+	switch strings.ToLower(strings.TrimPrefix(color, "#")) {
+	case "good", "2eb886":
+		return "good"
+	case "warning", "daa038":
+		return "warning"
+	case "danger", "a30200":
+		return "danger"
+	}
+	return ""
+}
+
+// dateElementText renders a date element using the fallback text Slack
+// supplies for clients that cannot format dates, or as RFC3339 in UTC.
+func dateElementText(e *slack.RichTextSectionDateElement) string {
+	if e.Fallback != nil && *e.Fallback != "" {
+		return *e.Fallback
+	}
+	return time.Unix(int64(e.Timestamp), 0).UTC().Format(time.RFC3339)
+}
@@ -1,6 +1,8 @@
 package text
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/slack-go/slack"
@@ -204,6 +206,44 @@ func TestExtractTextFromMessage_ComplexAttachment(t *testing.T) {
 	}
 }
 
+func TestExtractTextFromMessage_ExceedsCaps(t *testing.T) {
+	t.Setenv("SLACK_MCP_MAX_ATTACHMENTS", "2")
+	t.Setenv("SLACK_MCP_MAX_BLOCKS", "3")
+
+	var attachments []slack.Attachment
+	for i := 0; i < 5; i++ {
+		attachments = append(attachments, slack.Attachment{Text: fmt.Sprintf("attachment %d", i)})
+	}
+
+	var blocks []slack.Block
+	for i := 0; i < 10; i++ {
+		blocks = append(blocks, &slack.HeaderBlock{
+			Type: slack.MBTHeader,
+			Text: &slack.TextBlockObject{Type: "plain_text", Text: fmt.Sprintf("header %d", i)},
+		})
+	}
+
+	msg := &slack.Message{
+		Msg: slack.Msg{
+			Attachments: attachments,
+			Blocks:      slack.Blocks{BlockSet: blocks},
+		},
+	}
+
+	result := ExtractTextFromMessage(msg)
+
+	for _, exp := range []string{"attachment 1", "header 2", "[+3 more attachments]", "[+7 more blocks]"} {
+		if !contains(result, exp) {
+			t.Errorf("Expected result to contain '%s', got: %s", exp, result)
+		}
+	}
+	for _, unexp := range []string{"attachment 2", "header 3"} {
+		if contains(result, unexp) {
+			t.Errorf("Expected result not to contain '%s', got: %s", unexp, result)
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
@@ -216,3 +256,283 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestExtractTextFromRichTextSectionElement_Emoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		element  *slack.RichTextSectionEmojiElement
+		expected string
+	}{
+		{
+			name:     "standard emoji from lookup table",
+			element:  &slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "tada"},
+			expected: "🎉",
+		},
+		{
+			name:     "standard emoji from unicode codepoints",
+			element:  &slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "wave::skin-tone-2", Unicode: "1f44b-1f3fb"},
+			expected: "👋🏻",
+		},
+		{
+			name:     "codepoints of an emoji missing from the table fall back to colon form",
+			element:  &slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "unicorn_face", Unicode: "1f984"},
+			expected: ":unicorn_face:",
+		},
+		{
+			name:     "custom emoji falls back to colon form",
+			element:  &slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "partyparrot"},
+			expected: ":partyparrot:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := extractTextFromRichTextSectionElement(tt.element)
+			if len(parts) != 1 || parts[0] != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, parts)
+			}
+		})
+	}
+}
+
+func TestExtractTextFromRichTextSectionElement_Date(t *testing.T) {
+	fallback := "Nov 14th, 2023"
+
+	tests := []struct {
+		name     string
+		element  *slack.RichTextSectionDateElement
+		expected string
+	}{
+		{
+			name:     "fallback text is preferred",
+			element:  &slack.RichTextSectionDateElement{Type: slack.RTSEDate, Timestamp: 1700000000, Format: "{date_short}", Fallback: &fallback},
+			expected: "Nov 14th, 2023",
+		},
+		{
+			name:     "timestamp formatted as RFC3339 without fallback",
+			element:  &slack.RichTextSectionDateElement{Type: slack.RTSEDate, Timestamp: 1700000000, Format: "{date_short}"},
+			expected: "2023-11-14T22:13:20Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := extractTextFromRichTextSectionElement(tt.element)
+			if len(parts) != 1 || parts[0] != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, parts)
+			}
+			if parts[0] == "1700000000" {
+				t.Errorf("Expected a human-readable date, got the bare timestamp")
+			}
+		})
+	}
+}
+
+func TestExtractTextFromMessage_AttachmentColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		att      slack.Attachment
+		expected string
+	}{
+		{
+			name: "danger color prefixes the attachment",
+			att: slack.Attachment{
+				Color:  "danger",
+				Title:  "Database down",
+				Text:   "Primary is unreachable",
+				Fields: []slack.AttachmentField{{Title: "Region", Value: "eu-west-1"}},
+				Footer: "PagerDuty",
+			},
+			expected: "[danger] Database down\nPrimary is unreachable\nRegion: eu-west-1\nPagerDuty",
+		},
+		{
+			name:     "hex value of a predefined color",
+			att:      slack.Attachment{Color: "#A30200", Text: "Error rate above 5%"},
+			expected: "[danger] Error rate above 5%",
+		},
+		{
+			name:     "custom colors are not mapped",
+			att:      slack.Attachment{Color: "#439FE0", Text: "Deploy finished"},
+			expected: "Deploy finished",
+		},
+		{
+			name:     "fallback used when text is empty",
+			att:      slack.Attachment{Color: "good", Fallback: "Build #42 passed"},
+			expected: "[good] Build #42 passed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := slack.Message{Msg: slack.Msg{Attachments: []slack.Attachment{tt.att}}}
+			if result := ExtractTextFromMessage(&msg); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExtractTextFromBlocks_ActionBlock(t *testing.T) {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "Deploy v1.2.3 to production?", false, false), nil, nil),
+		slack.NewActionBlock("approval",
+			slack.NewButtonBlockElement("approve", "yes", slack.NewTextBlockObject(slack.PlainTextType, "Approve", false, false)),
+			slack.NewButtonBlockElement("reject", "no", slack.NewTextBlockObject(slack.PlainTextType, "Reject", false, false)),
+		),
+		slack.NewActionBlock("env",
+			slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, nil, "env",
+				slack.NewOptionBlockObject("stg", slack.NewTextBlockObject(slack.PlainTextType, "Staging", false, false), nil),
+				slack.NewOptionBlockObject("prd", slack.NewTextBlockObject(slack.PlainTextType, "Production", false, false), nil),
+			),
+		),
+		slack.NewDividerBlock(),
+	}
+
+	expected := "Deploy v1.2.3 to production?\n[Approve] [Reject]\nStaging / Production"
+	if result := extractTextFromBlocks(blocks); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestExtractTextFromBlocks_SectionAccessory(t *testing.T) {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*Build #42* passed", false, false), nil,
+			slack.NewAccessory(slack.NewImageBlockElement("https://example.com/ok.png", "green check mark"))),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "Release notes are ready", false, false), nil,
+			slack.NewAccessory(slack.NewButtonBlockElement("open", "notes", slack.NewTextBlockObject(slack.PlainTextType, "Open", false, false)))),
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "No alt text", false, false), nil,
+			slack.NewAccessory(slack.NewImageBlockElement("https://example.com/x.png", ""))),
+	}
+
+	expected := "*Build #42* passed\n[Image: green check mark]\nRelease notes are ready\n[Open]\nNo alt text"
+	if result := extractTextFromBlocks(blocks); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestExtractTextFromRichTextBlock_OrderedList(t *testing.T) {
+	item := func(text string) slack.RichTextElement {
+		return slack.NewRichTextSection(slack.NewRichTextSectionTextElement(text, nil))
+	}
+
+	block := slack.NewRichTextBlock("list",
+		&slack.RichTextList{Type: slack.RTEList, Style: slack.RTEListOrdered, Elements: []slack.RichTextElement{
+			item("Prepare release"),
+			item("Deploy"),
+		}},
+		&slack.RichTextList{Type: slack.RTEList, Style: slack.RTEListOrdered, Indent: 1, Elements: []slack.RichTextElement{
+			item("Staging"),
+			item("Production"),
+		}},
+		&slack.RichTextList{Type: slack.RTEList, Style: slack.RTEListOrdered, Offset: 2, Elements: []slack.RichTextElement{
+			item("Announce"),
+		}},
+		&slack.RichTextList{Type: slack.RTEList, Style: slack.RTEListBullet, Elements: []slack.RichTextElement{
+			item("Notes"),
+			&slack.RichTextList{Type: slack.RTEList, Style: slack.RTEListBullet, Elements: []slack.RichTextElement{
+				item("Nested note"),
+			}},
+		}},
+	)
+
+	expected := []string{
+		"1. Prepare release",
+		"2. Deploy",
+		"  1. Staging",
+		"  2. Production",
+		"3. Announce",
+		"- Notes",
+		"  - Nested note",
+	}
+
+	result := extractTextFromRichTextBlock(block)
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestExtractTextFromMessage_DecodesEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "entities next to a link", text: "Tom &amp; Jerry <https://x|link>", expected: "Tom & Jerry <https://x|link>"},
+		{name: "angle brackets", text: "1 &lt; 2 &gt; 0", expected: "1 < 2 > 0"},
+		{name: "escaped entity decodes once", text: "type &amp;lt; for <", expected: "type &lt; for <"},
+		{name: "ampersand within markup", text: "<https://x?a=1&amp;b=2|Q&amp;A>", expected: "<https://x?a=1&b=2|Q&A>"},
+		{name: "mentions are untouched", text: "<@U12345678> and <#C12345678|general>", expected: "<@U12345678> and <#C12345678|general>"},
+		{name: "escaped mention is a literal", text: "literally &lt;@U12345678&gt;", expected: "literally <@U12345678>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &slack.Message{Msg: slack.Msg{Text: tt.text}}
+			got := ExtractTextFromMessage(msg)
+			if decoded := decodeEntities(got); decoded != got {
+				t.Errorf("expected decoding the extracted text again to change nothing, got %q", decoded)
+			}
+			if got := restoreEscapes(got); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	// the link is still processed downstream
+	msg := &slack.Message{Msg: slack.Msg{Text: "Tom &amp; Jerry <https://x|link>"}}
+	if got := ProcessText(ExtractTextFromMessage(msg)); got != "Tom & Jerry https://x - link" {
+		t.Errorf("expected the processed text to keep the ampersand, got %q", got)
+	}
+}
+
+func TestExtractTextFromMessage_DecodedTextDeduplicatesWithRichText(t *testing.T) {
+	// rich text carries the raw characters, the text field their entities
+	msg := &slack.Message{Msg: slack.Msg{
+		Text: "R&amp;D",
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewRichTextBlock("b1", slack.NewRichTextSection(slack.NewRichTextSectionTextElement("R&D", nil))),
+		}},
+	}}
+	if got := ExtractTextFromMessage(msg); got != "R&D" {
+		t.Errorf("expected a single R&D, got %q", got)
+	}
+}
+
+func TestExtractTextFromMessage_ForwardedMessage(t *testing.T) {
+	msg := &slack.Message{Msg: slack.Msg{
+		Text: "FYI",
+		Attachments: []slack.Attachment{{
+			AuthorName: "alice",
+			Text:       "deploy is done &amp; verified",
+			Ts:         "1700000000.000100",
+			FromURL:    "https://example.slack.com/archives/C12345678/p1700000000000100",
+		}},
+	}}
+
+	expected := "FYI\nForwarded from @alice in <#C12345678>: deploy is done & verified"
+	if got := ExtractTextFromMessage(msg); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// the channel is rendered like any channel mention
+	channelName := func(id string) (string, bool) { return "ops", id == "C12345678" }
+	if got := ProcessTextPlain(ExtractTextFromMessage(msg), nil, channelName); got != "FYI\nForwarded from @alice in #ops: deploy is done & verified" {
+		t.Errorf("unexpected plain text %q", got)
+	}
+
+	// unfurls have an author but no ts, they are not forwards
+	unfurl := &slack.Message{Msg: slack.Msg{Attachments: []slack.Attachment{{AuthorName: "GitHub", Text: "PR merged"}}}}
+	if got := ExtractTextFromMessage(unfurl); got != "PR merged\nAuthor: GitHub" {
+		t.Errorf("expected an unfurl to keep its author line, got %q", got)
+	}
+
+	// integrations set ts and an author too, but link outside Slack
+	github := &slack.Message{Msg: slack.Msg{Attachments: []slack.Attachment{{
+		AuthorName: "octocat",
+		Text:       "PR merged",
+		Ts:         "1700000000",
+		FromURL:    "https://github.com/acme/api/archives/C12345678/p1",
+	}}}}
+	if got := ExtractTextFromMessage(github); got != "PR merged\nAuthor: octocat" {
+		t.Errorf("expected an integration attachment not to be a forward, got %q", got)
+	}
+}
@@ -1,17 +1,172 @@
 package text
 
 import (
+	"fmt"
+	"html"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/slack-go/slack"
 )
 
+// ProcessText normalizes message text for output: Slack, markdown and HTML
+// links are rewritten as "URL - description", tel: and mailto: links are
+// decoded and any remaining markup characters are stripped. Mentions are
+// reduced to the bare ID, e.g. <@U12345678> becomes U12345678; use
+// ProcessTextWithUsers to render them as names instead.
 func ProcessText(s string) string {
 	s = filterSpecialChars(s)
 
 	return s
 }
 
+// mentionRegex matches user and channel mentions with an optional label,
+// e.g. <@U12345678>, <@U12345678|alice> or <#C12345678|general>.
+var mentionRegex = regexp.MustCompile(`<([@#])([A-Z0-9]+)(?:\|([^>]*))?>`)
+
+// ProcessTextWithUsers behaves like ProcessText but renders user mentions as
+// @name using users and channel mentions as #channel using the label Slack
+// sends along. Mentions that cannot be resolved keep their ID.
+func ProcessTextWithUsers(s string, users map[string]slack.User) string {
+	return processText(s, users, nil, 0)
+}
+
+// ProcessTextWithReach behaves like ProcessTextWithUsers, or like ProcessText
+// when users is nil, and annotates broadcast mentions with how many people
+// they reached, e.g. "@channel (notified ~120)". reach is the channel's
+// member count; @here only notifies active members, hence "up to".
+func ProcessTextWithReach(s string, users map[string]slack.User, reach int) string {
+	return processText(s, users, nil, reach)
+}
+
+// ChannelNamer returns the name of the channel with the given ID, without
+// its prefix, and whether the channel is known.
+type ChannelNamer func(id string) (string, bool)
+
+// ProcessTextWithChannels behaves like ProcessTextWithReach and renders
+// channel mentions, <#C12345678> included which carry no label, as
+// #channel-name using channelName. Mentions of channels it does not know are
+// processed as before, reduced to the ID unless users is set.
+func ProcessTextWithChannels(s string, users map[string]slack.User, channelName ChannelNamer, reach int) string {
+	return processText(s, users, channelName, reach)
+}
+
+// broadcastRegex matches broadcast mentions, e.g. <!channel> or <!here|here>.
+var broadcastRegex = regexp.MustCompile(`<!(channel|here|everyone)(?:\|[^>]*)?>`)
+
+func processText(s string, users map[string]slack.User, channelName ChannelNamer, reach int) string {
+	var mentions []string
+	protect := func(mention string) string {
+		mentions = append(mentions, mention)
+		return mentionPlaceholder(len(mentions) - 1)
+	}
+
+	if users != nil || channelName != nil {
+		s = mentionRegex.ReplaceAllStringFunc(s, func(mention string) string {
+			if match := mentionRegex.FindStringSubmatch(mention); channelName != nil && match[1] == "#" {
+				if name, ok := channelName(match[2]); ok {
+					return protect("#" + name)
+				}
+			}
+			if users == nil {
+				return mention
+			}
+			return protect(renderMention(mention, users))
+		})
+	}
+	if reach > 0 {
+		s = broadcastRegex.ReplaceAllStringFunc(s, func(mention string) string {
+			return protect(renderBroadcast(broadcastRegex.FindStringSubmatch(mention)[1], reach))
+		})
+	}
+
+	s = filterSpecialChars(s)
+
+	// Restore the mentions, which the cleaning would have stripped of @ and #
+	for i, mention := range mentions {
+		s = strings.Replace(s, mentionPlaceholder(i), mention, 1)
+	}
+
+	return s
+}
+
+func renderBroadcast(name string, reach int) string {
+	if name == "here" {
+		return fmt.Sprintf("@here (notified up to ~%d)", reach)
+	}
+	return fmt.Sprintf("@%s (notified ~%d)", name, reach)
+}
+
+// renderMention renders a user or channel mention matched by mentionRegex as
+// @name, see UserName with the username by default, or #channel, falling
+// back to its label and then to the ID.
+func renderMention(mention string, users map[string]slack.User) string {
+	match := mentionRegex.FindStringSubmatch(mention)
+	sigil, id, label := match[1], match[2], strings.TrimPrefix(match[3], match[1])
+
+	if u, ok := users[id]; sigil == "@" && ok {
+		if name := UserName(u, NameUsername); name != "" {
+			return sigil + name
+		}
+	}
+	if label != "" {
+		return sigil + label
+	}
+	return sigil + id
+}
+
+var (
+	// inlineLinkRegex matches Slack links with an optional label, e.g.
+	// <https://example.com|Example>.
+	inlineLinkRegex = regexp.MustCompile(`<((?:https?|mailto|tel):[^>|]+)(?:\|([^>]*))?>`)
+	// specialMentionRegex matches special mentions such as <!here> or
+	// <!subteam^S123|@oncall>.
+	specialMentionRegex = regexp.MustCompile(`<!([^>|]+)(?:\|([^>]*))?>`)
+)
+
+// ProcessInlineText cleans up short single line texts such as channel topics
+// and purposes without dropping punctuation the way ProcessText does: links
+// and mentions are decoded, HTML entities unescaped and all whitespace,
+// newlines included, collapsed to single spaces.
+func ProcessInlineText(s string, users map[string]slack.User) string {
+	s = mentionRegex.ReplaceAllStringFunc(s, func(mention string) string {
+		return renderMention(mention, users)
+	})
+
+	s = inlineLinkRegex.ReplaceAllStringFunc(s, func(link string) string {
+		match := inlineLinkRegex.FindStringSubmatch(link)
+		target, label := match[1], strings.TrimSpace(match[2])
+		if strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "tel:") {
+			if label != "" {
+				return label
+			}
+			return target[strings.Index(target, ":")+1:]
+		}
+		if label == "" || label == target {
+			return target
+		}
+		return target + " - " + label
+	})
+
+	s = specialMentionRegex.ReplaceAllStringFunc(s, func(mention string) string {
+		match := specialMentionRegex.FindStringSubmatch(mention)
+		if match[2] != "" {
+			return match[2]
+		}
+		return "@" + match[1]
+	})
+
+	s = html.UnescapeString(s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
+
 func filterSpecialChars(text string) string {
+	// An "&" extraction held back is kept like any other, an escaped "<" is
+	// stripped below like the markup characters, see decodeEntities
+	text = strings.ReplaceAll(text, escapedAmp, "&")
+
 	replaceWithCommaCheck := func(match []string, isLast bool) string {
 		var url, linkText string
 
@@ -42,6 +197,21 @@ func filterSpecialChars(text string) string {
 		return afterLink == ""
 	}
 
+	// Handle Slack-style contact links: <tel:+123|+1 (23)> and <mailto:x@y|x@y>,
+	// preferring the label. The decoded values are protected from cleaning
+	// below as they legitimately contain characters such as @, + and ().
+	contactLinkRegex := regexp.MustCompile(`<(?:tel|mailto):([^>|]+)(?:\|([^>]+))?>`)
+	var contacts []string
+	text = contactLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		match := contactLinkRegex.FindStringSubmatch(link)
+		contact := strings.TrimSpace(match[2])
+		if contact == "" {
+			contact = match[1]
+		}
+		contacts = append(contacts, contact)
+		return contactPlaceholder(len(contacts) - 1)
+	})
+
 	// Handle Slack-style links: <URL|Description>
 	slackLinkRegex := regexp.MustCompile(`<(https?://[^>|]+)\|([^>]+)>`)
 	slackMatches := slackLinkRegex.FindAllStringSubmatch(text, -1)
@@ -85,7 +255,7 @@ func filterSpecialChars(text string) string {
 		protected = strings.Replace(protected, url, placeholder, 1)
 	}
 
-	cleanRegex := regexp.MustCompile(`[^0-9\p{L}\p{M}\s\.\,\-_:/\?=&%]`)
+	cleanRegex := regexp.MustCompile(`[^0-9\p{L}\p{M}\s\.\,\-_:/\?=&%` + emojiGlyphChars + `]`)
 	cleaned := cleanRegex.ReplaceAllString(protected, "")
 
 	// Restore the URLs
@@ -94,8 +264,21 @@ func filterSpecialChars(text string) string {
 		cleaned = strings.Replace(cleaned, placeholder, url, 1)
 	}
 
+	// Restore the contact links
+	for i, contact := range contacts {
+		cleaned = strings.Replace(cleaned, contactPlaceholder(i), contact, 1)
+	}
+
 	spaceRegex := regexp.MustCompile(`[ \t]+`)
 	cleaned = spaceRegex.ReplaceAllString(cleaned, " ")
 
 	return strings.TrimSpace(cleaned)
 }
+
+func contactPlaceholder(i int) string {
+	return "___CONTACT_PLACEHOLDER_" + strconv.Itoa(i) + "___"
+}
+
+func mentionPlaceholder(i int) string {
+	return "___MENTION_PLACEHOLDER_" + strconv.Itoa(i) + "___"
+}
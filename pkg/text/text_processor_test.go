@@ -2,6 +2,8 @@ package text
 
 import (
 	"testing"
+
+	"github.com/slack-go/slack"
 )
 
 func TestFilterSpecialCharsWithCommas(t *testing.T) {
@@ -56,3 +58,303 @@ func TestFilterSpecialCharsWithCommas(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterSpecialCharsContactLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "tel link with label",
+			input:    "Call me at <tel:+12345678|+1 (234) 567-8>",
+			expected: "Call me at +1 (234) 567-8",
+		},
+		{
+			name:     "tel link without label",
+			input:    "Call <tel:+12345678> now",
+			expected: "Call +12345678 now",
+		},
+		{
+			name:     "mailto link with label",
+			input:    "Mail <mailto:jane@example.com|jane@example.com> please",
+			expected: "Mail jane@example.com please",
+		},
+		{
+			name:     "mailto link without label",
+			input:    "<mailto:jane@example.com>",
+			expected: "jane@example.com",
+		},
+		{
+			name:     "mixed with url link",
+			input:    "<mailto:a@b.co|a@b.co> or <https://example.com|site>",
+			expected: "a@b.co or https://example.com - site",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := filterSpecialChars(tt.input)
+			if result != tt.expected {
+				t.Errorf("filterSpecialChars() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessTextKeepsEmoji(t *testing.T) {
+	result := ProcessText("Shipped 🎉 :partyparrot:")
+	if result != "Shipped 🎉 :partyparrot:" {
+		t.Errorf("ProcessText() = %q, expected emoji to be kept", result)
+	}
+}
+
+func TestProcessTextWithUsers(t *testing.T) {
+	users := map[string]slack.User{
+		"U12345678": {ID: "U12345678", Name: "alice"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "known user mention",
+			input:    "Thanks <@U12345678>!",
+			expected: "Thanks @alice",
+		},
+		{
+			name:     "unknown user mention keeps the ID",
+			input:    "ping <@U99999999>",
+			expected: "ping @U99999999",
+		},
+		{
+			name:     "unknown user mention uses its label",
+			input:    "ping <@U99999999|bob>",
+			expected: "ping @bob",
+		},
+		{
+			name:     "channel mention",
+			input:    "see <#C12345678|general> and <#C87654321>",
+			expected: "see #general and #C87654321",
+		},
+		{
+			name:     "mentions next to links",
+			input:    "<@U12345678> shared <https://example.com|the doc>",
+			expected: "@alice shared https://example.com - the doc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ProcessTextWithUsers(tt.input, users); result != tt.expected {
+				t.Errorf("ProcessTextWithUsers() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+
+	// without a users map mentions are left as bare IDs
+	if result := ProcessText("Thanks <@U12345678>"); result != "Thanks U12345678" {
+		t.Errorf("ProcessText() = %q, expected mentions unchanged", result)
+	}
+}
+
+func TestProcessInlineText(t *testing.T) {
+	users := map[string]slack.User{
+		"U12345678": {ID: "U12345678", Name: "alice"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "links and newlines",
+			input:    "Release notes:\n<https://example.com/notes|notes> &amp; <https://example.com/faq>\n\n  (see pinned!)",
+			expected: "Release notes: https://example.com/notes - notes & https://example.com/faq (see pinned!)",
+		},
+		{
+			name:     "mentions",
+			input:    "Owner: <@U12345678>, escalate in <#C12345678|incidents> or ping <!subteam^S123|@oncall> / <!here>",
+			expected: "Owner: @alice, escalate in #incidents or ping @oncall / @here",
+		},
+		{
+			name:     "contact links",
+			input:    "Call <tel:+15551234567|+1 555 123 4567> or mail <mailto:ops@example.com|ops@example.com>",
+			expected: "Call +1 555 123 4567 or mail ops@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ProcessInlineText(tt.input, users); result != tt.expected {
+				t.Errorf("ProcessInlineText() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessTextWithReach(t *testing.T) {
+	users := map[string]slack.User{
+		"U12345678": {ID: "U12345678", Name: "alice"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		users    map[string]slack.User
+		reach    int
+		expected string
+	}{
+		{
+			name:     "channel broadcast",
+			input:    "<!channel> release is out",
+			reach:    120,
+			expected: "@channel (notified ~120) release is out",
+		},
+		{
+			name:     "here only reaches active members",
+			input:    "<!here|here> standup?",
+			reach:    8,
+			expected: "@here (notified up to ~8) standup?",
+		},
+		{
+			name:     "mentions stay bare IDs without users",
+			input:    "<!everyone> ask <@U12345678>",
+			reach:    500,
+			expected: "@everyone (notified ~500) ask U12345678",
+		},
+		{
+			name:     "mentions rendered with users",
+			input:    "<!channel> ask <@U12345678>",
+			users:    users,
+			reach:    3,
+			expected: "@channel (notified ~3) ask @alice",
+		},
+		{
+			name:     "unknown reach is not annotated",
+			input:    "<!channel> hi",
+			expected: "channel hi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ProcessTextWithReach(tt.input, tt.users, tt.reach); result != tt.expected {
+				t.Errorf("ProcessTextWithReach() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessTextWithChannels(t *testing.T) {
+	channelName := func(id string) (string, bool) {
+		if id == "C12345678" {
+			return "dev-ops", true
+		}
+		return "", false
+	}
+	users := map[string]slack.User{
+		"U12345678": {ID: "U12345678", Name: "alice"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		users    map[string]slack.User
+		expected string
+	}{
+		{
+			name:     "unlabelled mention",
+			input:    "see <#C12345678>",
+			expected: "see #dev-ops",
+		},
+		{
+			name:     "the cached name wins over a stale label",
+			input:    "see <#C12345678|old-name>",
+			expected: "see #dev-ops",
+		},
+		{
+			name:     "unknown channel keeps its ID",
+			input:    "see <#C99999999>",
+			expected: "see C99999999",
+		},
+		{
+			name:     "unknown channel falls back to the label with users",
+			input:    "<@U12345678> see <#C99999999|random>",
+			users:    users,
+			expected: "@alice see #random",
+		},
+		{
+			name:     "user mentions stay bare IDs without users",
+			input:    "<@U12345678> in <#C12345678>",
+			expected: "U12345678 in #dev-ops",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProcessTextWithChannels(tt.input, tt.users, channelName, 0); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProcessTextStripsOtherSymbols(t *testing.T) {
+	// only the glyphs of the emoji table are kept, not every symbol
+	result := ProcessText("Shipped 🎉 ™ ☯")
+	if result != "Shipped 🎉" {
+		t.Errorf("ProcessText() = %q, expected other symbols to be stripped", result)
+	}
+}
+
+func TestProcessTextKeepsExtractedEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		element  *slack.RichTextSectionEmojiElement
+		expected string
+	}{
+		{
+			name:     "skin tone sequence",
+			element:  &slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "wave::skin-tone-2", Unicode: "1f44b-1f3fb"},
+			expected: "👋🏻",
+		},
+		{
+			name:     "emoji missing from the table",
+			element:  &slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "unicorn_face", Unicode: "1f984"},
+			expected: ":unicorn_face:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &slack.Message{Msg: slack.Msg{Blocks: slack.Blocks{BlockSet: []slack.Block{
+				slack.NewRichTextBlock("b1", slack.NewRichTextSection(
+					tt.element,
+				)),
+			}}}}
+			if result := ProcessText(ExtractTextFromMessage(msg)); result != tt.expected {
+				t.Errorf("ProcessText() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessTextWithChannels_LooksUpChannelMentionsOnly(t *testing.T) {
+	var looked []string
+	channelName := func(id string) (string, bool) {
+		looked = append(looked, id)
+		return "dev-ops", true
+	}
+	users := map[string]slack.User{"U12345678": {ID: "U12345678", Name: "alice"}}
+
+	got := ProcessTextWithChannels("<@U12345678> see <#C12345678>", users, channelName, 0)
+	if got != "@alice see #dev-ops" {
+		t.Errorf("got %q", got)
+	}
+	if len(looked) != 1 || looked[0] != "C12345678" {
+		t.Errorf("expected only the channel mention to be looked up, got %v", looked)
+	}
+}
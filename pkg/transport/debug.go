@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// debugBodyLimit is how many bytes of a body DebugTransport logs.
+const debugBodyLimit = 2048
+
+// DebugTransport logs every request and its response, method, URL, status
+// and truncated bodies, to the standard logger. Slack tokens and session
+// cookies are redacted, headers aren't logged at all. It wraps another
+// RoundTripper, e.g. UserAgentTransport, so that requests are sent as
+// without it.
+type DebugTransport struct {
+	roundTripper http.RoundTripper
+	logger       *log.Logger
+}
+
+func NewDebug(roundTripper http.RoundTripper) *DebugTransport {
+	return &DebugTransport{
+		roundTripper: roundTripper,
+		logger:       log.Default(),
+	}
+}
+
+// RoundTrip implements the RoundTripper interface. The request is logged
+// from a copy of its body, the response once its body has been read or
+// closed, from the first debugBodyLimit bytes teed off while reading.
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	t.logger.Printf("HTTP > %s %s %s", req.Method, Redact(req.URL.String()), Redact(truncateBody(reqBody, len(reqBody) > debugBodyLimit)))
+
+	start := time.Now()
+	resp, err := t.roundTripper.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.logger.Printf("HTTP < %s %s failed after %s: %s", req.Method, Redact(req.URL.String()), elapsed, Redact(err.Error()))
+		return resp, err
+	}
+
+	method, reqURL, status := req.Method, Redact(req.URL.String()), resp.StatusCode
+	resp.Body = &debugBody{
+		ReadCloser: resp.Body,
+		log: func(head []byte, truncated bool) {
+			t.logger.Printf("HTTP < %s %s %d in %s %s", method, reqURL, status, elapsed, Redact(truncateBody(head, truncated)))
+		},
+	}
+
+	return resp, nil
+}
+
+// debugBody passes a response body through, keeping its first
+// debugBodyLimit bytes for log, which is called once at EOF or on Close. A
+// body closed before EOF counts as truncated.
+type debugBody struct {
+	io.ReadCloser
+	head   []byte
+	size   int
+	eof    bool
+	log    func(head []byte, truncated bool)
+	logged bool
+}
+
+func (b *debugBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if keep := min(n, debugBodyLimit-len(b.head)); keep > 0 {
+		b.head = append(b.head, p[:keep]...)
+	}
+	b.size += n
+	if err == io.EOF {
+		b.eof = true
+		b.flush()
+	}
+	return n, err
+}
+
+func (b *debugBody) Close() error {
+	b.flush()
+	return b.ReadCloser.Close()
+}
+
+func (b *debugBody) flush() {
+	if !b.logged {
+		b.logged = true
+		b.log(b.head, !b.eof || b.size > len(b.head))
+	}
+}
+
+// truncateBody renders at most debugBodyLimit bytes of head, marked when
+// the body went on past them.
+func truncateBody(head []byte, truncated bool) string {
+	if len(head) > debugBodyLimit {
+		head = head[:debugBodyLimit]
+	}
+	if truncated {
+		return string(head) + "... (truncated)"
+	}
+	return string(head)
+}
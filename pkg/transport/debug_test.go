@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDebugTransport_RedactsTokensAndCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("d"); err != nil || c.Value != "xoxd-secret%2Fcookie" {
+			t.Errorf("expected the cookie to still be sent, got %v, %v", c, err)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("expected the User-Agent to still be sent, got %q", ua)
+		}
+		b, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(b), "xoxc-123-secret") {
+			t.Errorf("expected the token to reach the server, got %q", b)
+		}
+		_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth","token":"xoxc-123-secret"}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	dt := NewDebug(New(http.DefaultTransport, "test-agent", []*http.Cookie{{Name: "d", Value: "xoxd-secret%2Fcookie"}}))
+	dt.logger = log.New(&buf, "", 0)
+	client := &http.Client{Transport: dt}
+
+	form := url.Values{"token": {"xoxc-123-secret"}, "query": {"deploy"}}
+	resp, err := client.PostForm(srv.URL+"/api/search.messages?token=xoxc-123-secret", form)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "invalid_auth") {
+		t.Errorf("expected the response body to be passed on, got %q", body)
+	}
+
+	logged := buf.String()
+	for _, want := range []string{"POST", "/api/search.messages", "query=deploy", "200", "invalid_auth"} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("expected the log to contain %q, got:\n%s", want, logged)
+		}
+	}
+	for _, secret := range []string{"secret", "cookie"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("expected %q to be redacted, got:\n%s", secret, logged)
+		}
+	}
+}
+
+func TestDebugTransport_TruncatesBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("a"), 3*debugBodyLimit))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	dt := NewDebug(http.DefaultTransport)
+	dt.logger = log.New(&buf, "", 0)
+	client := &http.Client{Transport: dt}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if len(body) != 3*debugBodyLimit {
+		t.Errorf("expected the full body to be passed on, got %d bytes", len(body))
+	}
+	if !strings.Contains(buf.String(), "(truncated)") || buf.Len() > 2*debugBodyLimit {
+		t.Errorf("expected the logged body to be truncated, got %d bytes", buf.Len())
+	}
+}
+
+func TestDebugTransport_LeavesRequestUntouched(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	dt := NewDebug(http.DefaultTransport)
+	dt.logger = log.New(&buf, "", 0)
+
+	body := io.NopCloser(strings.NewReader("query=deploy"))
+	req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := dt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	echoed, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if req.Body != body {
+		t.Error("expected the caller's request body not to be replaced")
+	}
+	if string(echoed) != "query=deploy" {
+		t.Errorf("expected the body to reach the server, got %q", echoed)
+	}
+	if strings.Count(buf.String(), "query=deploy") != 2 {
+		t.Errorf("expected the request and response bodies to be logged, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,186 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNoFixture is returned when replaying a request nothing was recorded for.
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// slackTokenPattern matches Slack tokens, e.g. xoxp-... or xoxd-..., which
+// are scrubbed from recorded fixtures.
+var slackTokenPattern = regexp.MustCompile(`xox[a-z]-[A-Za-z0-9%\-]+`)
+
+// Fixture is a recorded Slack API response.
+type Fixture struct {
+	Method string          `json:"method"`
+	Params url.Values      `json:"params"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// RecordTransport saves every response to a JSON fixture in dir, keyed by
+// the API method and its parameters, for ReplayTransport to serve later.
+// Tokens are scrubbed from the parameters and bodies, cookies and headers
+// are not recorded at all.
+type RecordTransport struct {
+	roundTripper http.RoundTripper
+	dir          string
+}
+
+func NewRecord(roundTripper http.RoundTripper, dir string) *RecordTransport {
+	return &RecordTransport{
+		roundTripper: roundTripper,
+		dir:          dir,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method, params, req, err := fixtureKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.roundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Only JSON bodies can be replayed through the Web API client
+	if !json.Valid(body) {
+		return resp, nil
+	}
+
+	fixture := Fixture{
+		Method: method,
+		Params: params,
+		Status: resp.StatusCode,
+		Body:   json.RawMessage(scrubTokens(string(body))),
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, fixtureName(method, params)), data, 0o644); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport answers requests with the fixtures RecordTransport saved
+// in dir without any network access.
+type ReplayTransport struct {
+	dir string
+}
+
+func NewReplay(dir string) *ReplayTransport {
+	return &ReplayTransport{dir: dir}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method, params, _, err := fixtureKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(t.dir, fixtureName(method, params)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w for %s %s", ErrNoFixture, method, params.Encode())
+		}
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture for %s: %w", method, err)
+	}
+
+	// Fixtures are indented for readability, Slack answers compact JSON
+	var body bytes.Buffer
+	if err := json.Compact(&body, fixture.Body); err != nil {
+		return nil, fmt.Errorf("invalid fixture for %s: %w", method, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(&body),
+		ContentLength: int64(body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// fixtureKey returns the API method, e.g. conversations.history, and the
+// request parameters from both the query and a form body, without tokens,
+// along with the request to send in place of req, see readRequestBody.
+func fixtureKey(req *http.Request) (string, url.Values, *http.Request, error) {
+	params := url.Values{}
+	for k, v := range req.URL.Query() {
+		params[k] = v
+	}
+
+	body, req, err := readRequestBody(req)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if body != nil && strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "", nil, nil, err
+		}
+		for k, v := range form {
+			params[k] = append(params[k], v...)
+		}
+	}
+
+	params.Del("token")
+	for k, values := range params {
+		for i, v := range values {
+			params[k][i] = scrubTokens(v)
+		}
+	}
+
+	return path.Base(req.URL.Path), params, req, nil
+}
+
+func fixtureName(method string, params url.Values) string {
+	sum := sha256.Sum256([]byte(params.Encode()))
+	return method + "_" + hex.EncodeToString(sum[:6]) + ".json"
+}
+
+func scrubTokens(s string) string {
+	return slackTokenPattern.ReplaceAllStringFunc(s, func(token string) string {
+		return token[:5] + "REDACTED"
+	})
+}
@@ -0,0 +1,22 @@
+package transport
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := map[string]string{
+		"token=xoxc-1-2-abc&channel=C1": "token=xoxc-REDACTED&channel=C1",
+		"Cookie: d=abc%2Fdef; d-s=123":  "Cookie: d=REDACTED; d-s=REDACTED",
+		`{"token":"xoxd-a%2Fb"}`:        `{"token":"xoxd-REDACTED"}`,
+		"id=C123":                       "id=C123",
+		`Post "https://example.slack.com/api/users.list?token=xoxb-1-abc": dial tcp: connection refused`: `Post "https://example.slack.com/api/users.list?token=xoxb-REDACTED": dial tcp: connection refused`,
+		"auth failed for xoxp-1-2-3 with cookie d=xoxd-a%2Fb":                                            "auth failed for xoxp-REDACTED with cookie d=REDACTED",
+		`parse "http://user:p@ss@proxy:3128": invalid port`:                                              `parse "http://REDACTED@proxy:3128": invalid port`,
+		"proxyconnect tcp via socks5://alice@proxy.internal:1080":                                        "proxyconnect tcp via socks5://REDACTED@proxy.internal:1080",
+		"mail bob@example.com at https://example.com/a@b":                                                "mail bob@example.com at https://example.com/a@b",
+	}
+	for in, want := range tests {
+		if got := Redact(in); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRetryWait is used when a rate limited response carries no
+	// usable Retry-After.
+	defaultRetryWait = time.Second
+	// rateLimitPeekSize bounds how much of a 200 response is inspected for
+	// the "ratelimited" error, such bodies are tiny so anything larger is a
+	// payload.
+	rateLimitPeekSize = 512
+)
+
+// RetryTransport retries rate limited requests once the delay Slack asks for
+// in Retry-After has passed. Slack signals rate limiting with 429 Too Many
+// Requests, and on some Web API endpoints with HTTP 200 and a
+// {"ok":false,"error":"ratelimited"} body; both share one retry budget. It
+// wraps another RoundTripper, e.g. UserAgentTransport, so that every retry
+// goes through it.
+type RetryTransport struct {
+	roundTripper http.RoundTripper
+	maxRetries   int
+}
+
+func NewRetry(roundTripper http.RoundTripper, maxRetries int) *RetryTransport {
+	return &RetryTransport{
+		roundTripper: roundTripper,
+		maxRetries:   maxRetries,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTripper.RoundTrip(req)
+		if err != nil || attempt >= t.maxRetries || !canReplay(req) {
+			return resp, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && !isRateLimitedBody(resp) {
+			return resp, nil
+		}
+
+		wait := retryAfter(resp.Header.Get("Retry-After"))
+		resp.Body.Close()
+		log.Printf("Slack API %s is rate limited, retrying in %s (attempt %d/%d)", req.URL.Path, wait, attempt+1, t.maxRetries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// isRateLimitedBody reports whether resp is a successful HTTP response
+// carrying Slack's "ratelimited" error. The body of resp stays readable.
+func isRateLimitedBody(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false
+	}
+
+	br := bufio.NewReaderSize(resp.Body, rateLimitPeekSize)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, resp.Body}
+
+	head, err := br.Peek(rateLimitPeekSize)
+	if err != io.EOF || !bytes.Contains(head, []byte(`"ratelimited"`)) {
+		return false
+	}
+
+	var r struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(head, &r); err != nil {
+		return false
+	}
+
+	return !r.Ok && r.Error == "ratelimited"
+}
+
+// retryAfter parses a Retry-After value given either in seconds or as an
+// HTTP date.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return defaultRetryWait
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return time.Duration(n) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return defaultRetryWait
+}
+
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,181 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport_RetriesOn429(t *testing.T) {
+	var calls int
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if r.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("expected the wrapped transport to run on every attempt, got UA %q", r.Header.Get("User-Agent"))
+		}
+
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRetry(New(http.DefaultTransport, "test-agent", nil), 3)}
+
+	resp, err := client.Post(srv.URL, "application/x-www-form-urlencoded", strings.NewReader("channel=C1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after retries, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	for _, b := range bodies {
+		if b != "channel=C1" {
+			t.Errorf("expected the body to be replayed, got %q", b)
+		}
+	}
+}
+
+func TestRetryTransport_GivesUp(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRetry(http.DefaultTransport, 2)}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the last 429 to be returned, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 1 call and 2 retries, got %d calls", calls)
+	}
+}
+
+func TestRetryTransport_RetriesRateLimitedBody(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "token=xoxp-test" {
+			t.Errorf("request body was not replayed, got %q", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRetry(http.DefaultTransport, 3)}
+	resp, err := client.Post(srv.URL, "application/x-www-form-urlencoded", strings.NewReader("token=xoxp-test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"ok":true}` {
+		t.Errorf("expected successful body after retry, got %q", body)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_SharesBudgetAcrossSignals(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		if calls%2 == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRetry(http.DefaultTransport, 3)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "ratelimited") {
+		t.Errorf("expected the last ratelimited body to be returned, got %q", body)
+	}
+	if calls != 4 {
+		t.Errorf("expected 1 call and 3 retries in total, got %d calls", calls)
+	}
+}
+
+func TestRetryTransport_PassesThroughOtherErrors(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRetry(http.DefaultTransport, 3)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"ok":false,"error":"channel_not_found"}` {
+		t.Errorf("expected body to be passed through untouched, got %q", body)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if got := retryAfter("2"); got != 2*time.Second {
+		t.Errorf("expected 2s, got %s", got)
+	}
+	if got := retryAfter(""); got != defaultRetryWait {
+		t.Errorf("expected default wait, got %s", got)
+	}
+	if got := retryAfter("soon"); got != defaultRetryWait {
+		t.Errorf("expected default wait for an invalid value, got %s", got)
+	}
+	if got := retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)); got != 0 {
+		t.Errorf("expected no wait for a date in the past, got %s", got)
+	}
+}
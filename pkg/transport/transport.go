@@ -1,6 +1,11 @@
 package transport
 
-import "net/http"
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
 
 type UserAgentTransport struct {
 	roundTripper http.RoundTripper
@@ -27,3 +32,73 @@ func (t *UserAgentTransport) RoundTrip(req *http.Request) (*http.Response, error
 
 	return t.roundTripper.RoundTrip(clonedReq)
 }
+
+// SplitUserAgents splits a list of User-Agents separated by newlines or
+// commas. Commas within parentheses, as in "(KHTML, like Gecko)", belong to
+// the User-Agent, so a single browser User-Agent is never split.
+func SplitUserAgents(s string) []string {
+	var (
+		uas   []string
+		depth int
+		start int
+	)
+	add := func(end int) {
+		if ua := strings.TrimSpace(s[start:end]); ua != "" {
+			uas = append(uas, ua)
+		}
+		start = end + 1
+	}
+
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == '\n':
+			depth = 0
+			add(i)
+		case r == ',' && depth == 0:
+			add(i)
+		}
+	}
+	add(len(s))
+
+	return uas
+}
+
+// readRequestBody returns the body of req along with the request to send in
+// its place, as a RoundTripper must not modify the request it is given. The
+// body is read from a copy through GetBody when possible, req is then sent
+// as is; otherwise it is consumed and a clone over the same bytes is sent.
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, req, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clonedReq := req.Clone(req.Context())
+	clonedReq.Body = io.NopCloser(bytes.NewReader(data))
+	clonedReq.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return data, clonedReq, nil
+}
@@ -0,0 +1,27 @@
+package transport
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitUserAgents(t *testing.T) {
+	chrome := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/136.0.0.0 Safari/537.36"
+	firefox := "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{chrome, []string{chrome}},
+		{chrome + "," + firefox, []string{chrome, firefox}},
+		{chrome + "\n" + firefox + "\n", []string{chrome, firefox}},
+		{" a , b ,, c ", []string{"a", "b", "c"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitUserAgents(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitUserAgents(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}